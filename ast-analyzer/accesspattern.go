@@ -0,0 +1,63 @@
+package main
+
+// 接口的读写模式：按方法名前缀判断方法是只读还是修改状态
+type InterfaceAccessPattern struct {
+	InterfaceName string   `json:"interfaceName"`
+	ReadMethods   []string `json:"readMethods"`
+	WriteMethods  []string `json:"writeMethods"`
+	// 无法从名称判断读写的方法（MIXED）
+	OtherMethods []string `json:"otherMethods"`
+	// read-only、write-only 或 mixed
+	Pattern  string   `json:"pattern"`
+	Location Location `json:"location"`
+}
+
+type AccessPatternResult struct {
+	Interfaces []InterfaceAccessPattern `json:"interfaces"`
+}
+
+var (
+	readPrefixes  = []string{"Get", "Find", "List", "Is", "Has", "Check"}
+	writePrefixes = []string{"Set", "Add", "Remove", "Delete", "Update", "Create"}
+)
+
+// 按方法名对接口的全部方法（含嵌入接口的方法）分类为 READ、WRITE 或 MIXED。
+// 方法全部为 READ 时是 read-only，全部为 WRITE 时是 write-only，其余为 mixed；
+// 没有任何 READ 或 WRITE 方法的接口无法判断，不列出
+func findInterfaceAccessPatterns(directory string) []InterfaceAccessPattern {
+	results := []InterfaceAccessPattern{}
+
+	for _, iface := range findAllInterfacesWithMethods(directory) {
+		result := InterfaceAccessPattern{
+			InterfaceName: iface.Name,
+			ReadMethods:   []string{},
+			WriteMethods:  []string{},
+			OtherMethods:  []string{},
+			Location:      iface.Location,
+		}
+		for _, name := range iface.Methods {
+			switch {
+			case matchesAnyPrefix(name, readPrefixes):
+				result.ReadMethods = append(result.ReadMethods, name)
+			case matchesAnyPrefix(name, writePrefixes):
+				result.WriteMethods = append(result.WriteMethods, name)
+			default:
+				result.OtherMethods = append(result.OtherMethods, name)
+			}
+		}
+		reads, writes, others := len(result.ReadMethods), len(result.WriteMethods), len(result.OtherMethods)
+		switch {
+		case reads == 0 && writes == 0:
+			continue
+		case writes == 0 && others == 0:
+			result.Pattern = "read-only"
+		case reads == 0 && others == 0:
+			result.Pattern = "write-only"
+		default:
+			result.Pattern = "mixed"
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
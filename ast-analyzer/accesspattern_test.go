@@ -0,0 +1,60 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+const accessPatternFixture = `package p
+
+type Reader interface {
+	Get(id string) string
+	IsOpen() bool
+	HasNext() bool
+}
+
+type Writer interface {
+	SetName(name string)
+	Delete(id string)
+}
+
+type Repo interface {
+	Reader
+	Writer
+	Close() error
+}
+
+// 前缀必须在单词边界结束：Issue 不是 Is，Settle 不是 Set
+type Noise interface {
+	Run()
+	Issue()
+	Settle()
+}
+`
+
+func TestInterfaceAccessPatterns(t *testing.T) {
+	dir := writeTree(t, map[string]string{"p.go": accessPatternFixture})
+	type summary struct {
+		pattern              string
+		reads, writes, other []string
+	}
+	sorted := func(names []string) []string {
+		copied := append(make([]string, 0, len(names)), names...)
+		sort.Strings(copied)
+		return copied
+	}
+	got := make(map[string]summary)
+	for _, iface := range runArgs(t, "find-interface-method-access-pattern", dir).(AccessPatternResult).Interfaces {
+		got[iface.InterfaceName] = summary{iface.Pattern, sorted(iface.ReadMethods), sorted(iface.WriteMethods), sorted(iface.OtherMethods)}
+	}
+
+	want := map[string]summary{
+		"Reader": {"read-only", []string{"Get", "HasNext", "IsOpen"}, []string{}, []string{}},
+		"Writer": {"write-only", []string{}, []string{"Delete", "SetName"}, []string{}},
+		"Repo":   {"mixed", []string{"Get", "HasNext", "IsOpen"}, []string{"Delete", "SetName"}, []string{"Close"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build/constraint"
+	"strings"
+)
+
+// 带有 //go:build ignore 约束的文件（生成器、独立脚本）不属于包，默认跳过
+type buildIgnoredError struct {
+	path string
+}
+
+func (e *buildIgnoredError) Error() string {
+	return fmt.Sprintf("%s: excluded by //go:build ignore (use --include-ignored)", e.path)
+}
+
+// 约束不超过这个数量的标签时才穷举求值，再多就只看是否直接为 ignore
+const maxConstraintTags = 10
+
+// package 子句之前的构建约束在不设置 ignore 标签时无法满足
+func buildIgnored(f *ast.File) bool {
+	for _, group := range f.Comments {
+		if group.Pos() >= f.Package {
+			break
+		}
+		for _, comment := range group.List {
+			if !constraint.IsGoBuild(comment.Text) && !constraint.IsPlusBuild(comment.Text) {
+				continue
+			}
+			expr, err := constraint.Parse(comment.Text)
+			if err == nil && !satisfiableWithout(expr, "ignore") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// 除 tag 以外的标签任意取值，是否存在一种组合使约束成立
+func satisfiableWithout(expr constraint.Expr, tag string) bool {
+	var tags []string
+	seen := map[string]bool{}
+	collectTags(expr, func(t string) {
+		if !seen[t] {
+			seen[t] = true
+			if t != tag {
+				tags = append(tags, t)
+			}
+		}
+	})
+	// 没有提到 tag 的约束（例如 //go:build linux）不是 ignore 文件
+	if !seen[tag] {
+		return true
+	}
+	if len(tags) > maxConstraintTags {
+		return strings.TrimSpace(expr.String()) != tag
+	}
+	for mask := 0; mask < 1<<len(tags); mask++ {
+		ok := expr.Eval(func(t string) bool {
+			for i, name := range tags {
+				if name == t {
+					return mask&(1<<i) != 0
+				}
+			}
+			return false
+		})
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func collectTags(expr constraint.Expr, visit func(string)) {
+	switch e := expr.(type) {
+	case *constraint.TagExpr:
+		visit(e.Tag)
+	case *constraint.NotExpr:
+		collectTags(e.X, visit)
+	case *constraint.AndExpr:
+		collectTags(e.X, visit)
+		collectTags(e.Y, visit)
+	case *constraint.OrExpr:
+		collectTags(e.X, visit)
+		collectTags(e.Y, visit)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestBuildIgnored(t *testing.T) {
+	for _, tc := range []struct {
+		header  string
+		ignored bool
+	}{
+		{"//go:build ignore\n\n", true},
+		{"// +build ignore\n\n", true},
+		{"// Command gen writes tables.go.\n\n//go:build ignore && linux\n\n", true},
+		{"//go:build ignore || linux\n\n", false},
+		{"//go:build !ignore\n\n", false},
+		{"//go:build linux\n\n", false},
+		{"", false},
+	} {
+		// package 子句之后的 //go:build 不是构建约束
+		f, err := parser.ParseFile(token.NewFileSet(), "gen.go", tc.header+"package p\n\n//go:build ignore\n", parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := buildIgnored(f); got != tc.ignored {
+			t.Errorf("%q: buildIgnored = %v, want %v", tc.header, got, tc.ignored)
+		}
+	}
+}
+
+func TestBuildIgnoredFilesAreSkipped(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"a.go":   serveFixtureInterface,
+		"gen.go": "//go:build ignore\n\npackage main\n\ntype Gen struct{}\n\nfunc (Gen) Run() {}\n",
+	})
+	for _, tc := range []struct {
+		args []string
+		want []string
+	}{
+		{nil, []string{"A"}},
+		{[]string{"--include-ignored"}, []string{"A", "Gen"}},
+	} {
+		args := append([]string{"find-implementations", dir, "Run"}, tc.args...)
+		got := receiverTypes(runArgs(t, args...).(AnalysisResult).Implementations)
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%v: got %v, want %v", tc.args, got, tc.want)
+		}
+	}
+}
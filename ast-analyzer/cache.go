@@ -0,0 +1,105 @@
+package main
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+)
+
+// 在方法中读写接收者的缓存字段（map、sync.Map 或 lru 缓存）的接口实现方法
+type CacheUsage struct {
+	ReceiverType string `json:"receiverType"`
+	MethodName   string `json:"methodName"`
+	CacheField   string `json:"cacheField"`
+	// 字段的类型，例如 map[string]*User、sync.Map、*lru.Cache[string, int]
+	CacheType string `json:"cacheType"`
+	// 方法中第一次访问该字段的位置
+	Location Location `json:"location"`
+}
+
+type CacheUsageResult struct {
+	Usages []CacheUsage `json:"usages"`
+}
+
+// 字段类型是否为缓存：map、sync.Map 或 lru 包中的类型
+func isCacheType(typeStr string) bool {
+	typeStr = strings.TrimPrefix(typeStr, "*")
+	return strings.HasPrefix(typeStr, "map[") || typeStr == "sync.Map" || strings.HasPrefix(typeStr, "lru.")
+}
+
+// 接收者字段访问 r.field，返回字段名
+func receiverField(expr ast.Expr, receiver string) (string, bool) {
+	selector, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	ident, ok := selector.X.(*ast.Ident)
+	if !ok || ident.Name != receiver {
+		return "", false
+	}
+	return selector.Sel.Name, true
+}
+
+// 查找访问接收者缓存字段的接口实现方法：map 字段的索引读写 r.cache[key]，
+// 以及 sync.Map、lru 缓存字段上的方法调用 r.cache.Load(key)；每个方法的每个字段输出一条
+func findCacheUsage(directory string) []CacheUsage {
+	results := []CacheUsage{}
+	scan := scanDirectory(directory)
+
+	for _, method := range uniqueImplementedMethods(matchImplementations(scan)) {
+		decl := method.FuncDecl
+		if method.fset == nil || decl.Body == nil || len(decl.Recv.List[0].Names) == 0 {
+			continue
+		}
+		receiver := decl.Recv.List[0].Names[0].Name
+		baseType := strings.TrimPrefix(getReceiverType(decl.Recv), "*")
+		info, ok := scan.Types[method.Package+":"+baseType]
+		if !ok || info.fields == nil {
+			continue
+		}
+
+		reported := make(map[string]bool)
+		report := func(field string, node ast.Node) {
+			fieldType, ok := info.fields[field]
+			if !ok || reported[field] {
+				return
+			}
+			typeStr := types.ExprString(fieldType)
+			if !isCacheType(typeStr) {
+				return
+			}
+			// 索引访问只对 map 有意义，方法调用只对 sync.Map 和 lru 有意义
+			_, isIndex := node.(*ast.IndexExpr)
+			if isIndex != strings.HasPrefix(strings.TrimPrefix(typeStr, "*"), "map[") {
+				return
+			}
+			reported[field] = true
+			pos := method.fset.Position(node.Pos())
+			results = append(results, CacheUsage{
+				ReceiverType: method.ReceiverType,
+				MethodName:   method.Name,
+				CacheField:   field,
+				CacheType:    typeStr,
+				Location:     Location{File: pos.Filename, Line: pos.Line - 1, Column: pos.Column - 1},
+			})
+		}
+
+		ast.Inspect(decl.Body, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.IndexExpr:
+				if field, ok := receiverField(node.X, receiver); ok {
+					report(field, node)
+				}
+			case *ast.CallExpr:
+				if selector, ok := node.Fun.(*ast.SelectorExpr); ok {
+					if field, ok := receiverField(selector.X, receiver); ok {
+						report(field, node)
+					}
+				}
+			}
+			return true
+		})
+	}
+
+	return results
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// 参数为函数类型的接口方法（访问者、回调、观察者等高阶约定）
+type HigherOrderMethod struct {
+	InterfaceName      string   `json:"interfaceName"`
+	MethodName         string   `json:"methodName"`
+	FuncParamName      string   `json:"funcParamName"`
+	FuncParamSignature string   `json:"funcParamSignature"`
+	Location           Location `json:"location"`
+}
+
+type HigherOrderResult struct {
+	Methods []HigherOrderMethod `json:"methods"`
+}
+
+// 查找参数类型为函数的接口方法，每个函数参数输出一条；可变参数 ...func() 也算在内
+func findHigherOrderMethods(directory string) []HigherOrderMethod {
+	results := []HigherOrderMethod{}
+
+	for _, iface := range findAllInterfacesWithMethods(directory) {
+		for _, spec := range iface.Specs {
+			if spec.embedded || spec.Func == nil || spec.Func.Params == nil {
+				continue
+			}
+			for _, field := range spec.Func.Params.List {
+				paramType := field.Type
+				if ellipsis, ok := paramType.(*ast.Ellipsis); ok {
+					paramType = ellipsis.Elt
+				}
+				if _, ok := paramType.(*ast.FuncType); !ok {
+					continue
+				}
+
+				names := []string{""}
+				if len(field.Names) > 0 {
+					names = names[:0]
+					for _, name := range field.Names {
+						names = append(names, name.Name)
+					}
+				}
+				for _, name := range names {
+					results = append(results, HigherOrderMethod{
+						InterfaceName:      iface.Name,
+						MethodName:         spec.Name,
+						FuncParamName:      name,
+						FuncParamSignature: types.ExprString(field.Type),
+						Location:           spec.Location,
+					})
+				}
+			}
+		}
+	}
+
+	return results
+}
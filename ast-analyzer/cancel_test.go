@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"go/token"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestServeCancelAbandonsOversizedParse(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"a.go":    serveFixtureInterface,
+		"huge.go": oversizedSource(),
+	})
+	enable, release := blockHugeParses(t)
+	client := startServe(t, dir)
+	// 先于 startServe 的清理执行，否则 serve 会一直等待被阻塞的解析
+	t.Cleanup(release)
+	query := []string{"find-implementations", dir, "Run"}
+	client.request(query...)
+
+	// 新增文件使索引过期，下一个请求重建索引时会卡在 huge.go 上
+	enable()
+	writeFile(t, filepath.Join(dir, "b.go"), "package p\n\ntype B struct{}\n\nfunc (B) Run() {}\n")
+	client.send(map[string]interface{}{"id": 2, "args": query})
+	time.Sleep(50 * time.Millisecond)
+
+	cancelled := time.Now()
+	client.send(map[string]interface{}{"method": "cancel", "params": map[string]interface{}{"id": 2}})
+	response := client.receiveWithin(5 * time.Second)
+	if latency := time.Since(cancelled); latency > time.Second {
+		t.Fatalf("response arrived %v after cancel", latency)
+	}
+	if response.Error != errRequestCancelled.Error() {
+		t.Fatalf("got error %q, want %q", response.Error, errRequestCancelled.Error())
+	}
+}
+
+func TestCancelledParseReportsAbandoned(t *testing.T) {
+	enable, _ := blockHugeParses(t)
+	enable()
+	dir := writeTree(t, map[string]string{"huge.go": oversizedSource()})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	savedCtx := requestCtx
+	requestCtx = ctx
+	defer func() { requestCtx = savedCtx }()
+	resetDiagnostics()
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	_, err := parseLargeFile(token.NewFileSet(), filepath.Join(dir, "huge.go"), []byte(oversizedSource()))
+	var abandoned *abandonedError
+	if !errors.As(err, &abandoned) || !abandoned.cancelled {
+		t.Fatalf("got %v, want a cancelled *abandonedError", err)
+	}
+	reportParseError(err)
+	if got := diagnosticsOfKind("abandoned"); len(got) != 1 {
+		t.Fatalf("got %d abandoned diagnostics, want 1", len(got))
+	}
+}
+
+// 只有执行中和排队中的请求能被取消；针对其他 ID 的 cancel 被忽略，之后到达的同 ID 请求正常执行
+func TestServeIgnoresCancelForRequestsNotPending(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"a.go":    serveFixtureInterface,
+		"huge.go": oversizedSource(),
+	})
+	enable, release := blockHugeParses(t)
+	client := startServe(t, dir)
+	t.Cleanup(release)
+	query := []string{"find-implementations", dir, "Run"}
+	cancelRequest := func(id int) {
+		client.send(map[string]interface{}{"method": "cancel", "params": map[string]interface{}{"id": id}})
+	}
+
+	// 空闲时取消一个还没有到达的请求
+	client.request(query...)
+	cancelRequest(7)
+	client.send(map[string]interface{}{"id": 7, "args": query})
+	if response := client.receiveWithin(5 * time.Second); response.Error != "" {
+		t.Fatalf("request 7 after an early cancel: got error %q", response.Error)
+	}
+
+	// 请求 2 执行期间：取消排队中的 3，以及尚未到达的 8
+	enable()
+	writeFile(t, filepath.Join(dir, "b.go"), "package p\n\ntype B struct{}\n\nfunc (B) Run() {}\n")
+	client.send(map[string]interface{}{"id": 2, "args": query})
+	time.Sleep(50 * time.Millisecond)
+	client.send(map[string]interface{}{"id": 3, "args": query})
+	cancelRequest(3)
+	cancelRequest(8)
+	cancelRequest(2)
+	for _, id := range []int{2, 3} {
+		if response := client.receiveWithin(5 * time.Second); response.Error != errRequestCancelled.Error() {
+			t.Fatalf("request %d: got error %q, want %q", id, response.Error, errRequestCancelled.Error())
+		}
+	}
+
+	release()
+	for _, id := range []int{3, 8} {
+		client.send(map[string]interface{}{"id": id, "args": query})
+		if response := client.receiveWithin(5 * time.Second); response.Error != "" {
+			t.Fatalf("request %d sent after its cancel: got error %q", id, response.Error)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import "go/ast"
+
+// 流式（builder）接口：多数方法返回接口自身，便于链式调用
+type FluentInterface struct {
+	InterfaceName       string   `json:"interfaceName"`
+	ChainableMethods    []string `json:"chainableMethods"`
+	NonChainableMethods []string `json:"nonChainableMethods"`
+	Location            Location `json:"location"`
+}
+
+type FluentInterfaceResult struct {
+	Interfaces []FluentInterface `json:"interfaces"`
+}
+
+// 方法的最后一个返回值是否为接口自身，泛型接口的 Builder[T] 也算在内
+func returnsSelf(ft *ast.FuncType, name string) bool {
+	if ft == nil || ft.Results == nil || len(ft.Results.List) == 0 {
+		return false
+	}
+	last := ft.Results.List[len(ft.Results.List)-1].Type
+	switch t := last.(type) {
+	case *ast.IndexExpr:
+		last = t.X
+	case *ast.IndexListExpr:
+		last = t.X
+	}
+	ident, ok := last.(*ast.Ident)
+	return ok && ident.Name == name
+}
+
+// 查找至少一半直接声明的方法返回接口自身的接口
+func findFluentInterfaces(directory string) []FluentInterface {
+	results := []FluentInterface{}
+
+	for _, iface := range findAllInterfacesWithMethods(directory) {
+		chainable, nonChainable := []string{}, []string{}
+		for _, spec := range iface.Specs {
+			if spec.embedded {
+				continue
+			}
+			if returnsSelf(spec.Func, iface.Name) {
+				chainable = append(chainable, spec.Name)
+			} else {
+				nonChainable = append(nonChainable, spec.Name)
+			}
+		}
+		if len(chainable) == 0 || len(chainable) < len(nonChainable) {
+			continue
+		}
+		results = append(results, FluentInterface{
+			InterfaceName:       iface.Name,
+			ChainableMethods:    chainable,
+			NonChainableMethods: nonChainable,
+			Location:            iface.Location,
+		})
+	}
+
+	return results
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 参与比较的接口
+type ComparedInterface struct {
+	Name        string   `json:"name"`
+	Package     string   `json:"package"`
+	PackageName string   `json:"packageName"`
+	Location    Location `json:"location"`
+}
+
+// 只在一个接口中存在的方法
+type ComparedMethod struct {
+	Name      string   `json:"name"`
+	Signature string   `json:"signature"`
+	Location  Location `json:"location"`
+}
+
+// 两个接口中同名但签名不同的方法
+type ChangedMethod struct {
+	Name       string   `json:"name"`
+	SignatureA string   `json:"signatureA"`
+	SignatureB string   `json:"signatureB"`
+	LocationA  Location `json:"locationA"`
+	LocationB  Location `json:"locationB"`
+}
+
+// 两个接口（展开嵌入后）的方法集比较。
+// A 是 B 的子集时，B 的所有实现也满足 A，把 A 换成 B 对 A 的使用方是向后兼容的
+type InterfaceComparison struct {
+	A          ComparedInterface `json:"a"`
+	B          ComparedInterface `json:"b"`
+	ASubsetOfB bool              `json:"aSubsetOfB"`
+	BSubsetOfA bool              `json:"bSubsetOfA"`
+	OnlyInA    []ComparedMethod  `json:"onlyInA"`
+	OnlyInB    []ComparedMethod  `json:"onlyInB"`
+	Changed    []ChangedMethod   `json:"changed"`
+}
+
+// 按名称查找接口，名称可用包名或导入路径限定（例如 v2.Store）；不存在或有多个同名接口时返回错误
+func lookupInterface(scan *ScanResult, query string) (*InterfaceInfo, error) {
+	qualifier, name := parseTypeQuery(query)
+	var found []*InterfaceInfo
+	for i := range scan.Interfaces {
+		iface := &scan.Interfaces[i]
+		if iface.Name == name && packageMatches(iface.Package, iface.PackageName, qualifier) {
+			found = append(found, iface)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("interface %s not found", query)
+	case 1:
+		return found[0], nil
+	}
+	var candidates []string
+	for _, iface := range found {
+		candidates = append(candidates, iface.PackageName+"."+iface.Name+" ("+iface.Package+")")
+	}
+	return nil, fmt.Errorf("interface %s is ambiguous, qualify it with a package name or import path: %s", query, strings.Join(candidates, ", "))
+}
+
+func newComparedInterface(iface *InterfaceInfo) ComparedInterface {
+	return ComparedInterface{Name: iface.Name, Package: iface.Package, PackageName: iface.PackageName, Location: iface.Location}
+}
+
+// 比较两个接口展开嵌入后的方法集，签名按规范化形式比较
+func compareInterfaces(directory, queryA, queryB string) (InterfaceComparison, error) {
+	scan := scanDirectory(directory)
+	a, err := lookupInterface(scan, queryA)
+	if err != nil {
+		return InterfaceComparison{}, err
+	}
+	b, err := lookupInterface(scan, queryB)
+	if err != nil {
+		return InterfaceComparison{}, err
+	}
+
+	result := InterfaceComparison{
+		A:       newComparedInterface(a),
+		B:       newComparedInterface(b),
+		OnlyInA: []ComparedMethod{},
+		OnlyInB: []ComparedMethod{},
+		Changed: []ChangedMethod{},
+	}
+	for _, spec := range a.Specs {
+		other := b.spec(spec.Name)
+		switch {
+		case other == nil:
+			result.OnlyInA = append(result.OnlyInA, ComparedMethod{Name: spec.Name, Signature: spec.Signature, Location: spec.Location})
+		case other.Key != spec.Key:
+			result.Changed = append(result.Changed, ChangedMethod{
+				Name:       spec.Name,
+				SignatureA: spec.Signature,
+				SignatureB: other.Signature,
+				LocationA:  spec.Location,
+				LocationB:  other.Location,
+			})
+		}
+	}
+	for _, spec := range b.Specs {
+		if a.spec(spec.Name) == nil {
+			result.OnlyInB = append(result.OnlyInB, ComparedMethod{Name: spec.Name, Signature: spec.Signature, Location: spec.Location})
+		}
+	}
+
+	result.ASubsetOfB = len(result.OnlyInA) == 0 && len(result.Changed) == 0
+	result.BSubsetOfA = len(result.OnlyInB) == 0 && len(result.Changed) == 0
+	return result, nil
+}
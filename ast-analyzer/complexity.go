@@ -0,0 +1,105 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+)
+
+// 按圈复杂度排名的一个实现方法
+type RankedImpl struct {
+	ReceiverType string `json:"receiverType"`
+	Complexity   int    `json:"complexity"`
+	// 从 1 开始，复杂度相同的实现名次相同
+	Rank     int      `json:"rank"`
+	Location Location `json:"location"`
+}
+
+// 接口方法的全部实现，按圈复杂度从低到高排列；第一个可以作为参考实现
+type MethodRanking struct {
+	MethodName      string       `json:"methodName"`
+	Implementations []RankedImpl `json:"implementations"`
+}
+
+type ComplexityRankingResult struct {
+	Methods []MethodRanking `json:"methods"`
+}
+
+// 圈复杂度：1 加上分支数（if、for、range、非 default 的 case 和 select 分支、&& 和 ||）。
+// 函数字面量计入所在的方法
+func cyclomaticComplexity(body *ast.BlockStmt) int {
+	complexity := 1
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			complexity++
+		case *ast.CaseClause:
+			if node.List != nil {
+				complexity++
+			}
+		case *ast.CommClause:
+			if node.Comm != nil {
+				complexity++
+			}
+		case *ast.BinaryExpr:
+			if node.Op == token.LAND || node.Op == token.LOR {
+				complexity++
+			}
+		}
+		return true
+	})
+	return complexity
+}
+
+// 对指定接口（不同包中的同名接口一起统计）的每个方法，按圈复杂度排列全部实现
+func rankImplementationComplexity(directory, interfaceName string) []MethodRanking {
+	rankings := []MethodRanking{}
+	byMethod := make(map[string][]RankedImpl)
+	seen := make(map[Location]bool)
+	var methodNames []string
+
+	for _, match := range matchAllImplementations(directory) {
+		if match.Interface.Name != interfaceName {
+			continue
+		}
+		for _, name := range match.Interface.Methods {
+			if _, ok := byMethod[name]; !ok {
+				byMethod[name] = []RankedImpl{}
+				methodNames = append(methodNames, name)
+			}
+		}
+		for _, method := range match.ImplementedMethods() {
+			if method.FuncDecl == nil || method.FuncDecl.Body == nil || seen[method.Location] {
+				continue
+			}
+			seen[method.Location] = true
+			byMethod[method.Name] = append(byMethod[method.Name], RankedImpl{
+				ReceiverType: method.ReceiverType,
+				Complexity:   cyclomaticComplexity(method.FuncDecl.Body),
+				Location:     method.Location,
+			})
+		}
+	}
+
+	for _, name := range methodNames {
+		impls := byMethod[name]
+		sort.SliceStable(impls, func(i, j int) bool {
+			if impls[i].Complexity != impls[j].Complexity {
+				return impls[i].Complexity < impls[j].Complexity
+			}
+			if impls[i].Location.File != impls[j].Location.File {
+				return impls[i].Location.File < impls[j].Location.File
+			}
+			return impls[i].Location.Line < impls[j].Location.Line
+		})
+		for i := range impls {
+			impls[i].Rank = i + 1
+			if i > 0 && impls[i].Complexity == impls[i-1].Complexity {
+				impls[i].Rank = impls[i-1].Rank
+			}
+		}
+		rankings = append(rankings, MethodRanking{MethodName: name, Implementations: impls})
+	}
+
+	return rankings
+}
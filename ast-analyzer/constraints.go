@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+)
+
+// 只能用作类型约束的接口元素：联合类型 T1 | T2 和近似类型 ~T
+type GenericConstraint struct {
+	InterfaceName    string   `json:"interfaceName"`
+	Location         Location `json:"location"`
+	UnionTypes       []string `json:"unionTypes"`
+	ApproximateTypes []string `json:"approximateTypes"`
+	// 接口没有声明任何方法，只描述类型集合
+	ConstraintOnly bool `json:"constraintOnly"`
+}
+
+type GenericConstraintResult struct {
+	Constraints []GenericConstraint `json:"constraints"`
+}
+
+// 把约束元素展开为联合项，~T 同时记录到近似类型中
+func collectConstraintTerms(expr ast.Expr, constraint *GenericConstraint) {
+	switch t := expr.(type) {
+	case *ast.BinaryExpr:
+		if t.Op == token.OR {
+			collectConstraintTerms(t.X, constraint)
+			collectConstraintTerms(t.Y, constraint)
+			return
+		}
+	case *ast.ParenExpr:
+		collectConstraintTerms(t.X, constraint)
+		return
+	case *ast.UnaryExpr:
+		if t.Op == token.TILDE {
+			constraint.ApproximateTypes = append(constraint.ApproximateTypes, types.ExprString(t.X))
+		}
+	}
+	constraint.UnionTypes = append(constraint.UnionTypes, types.ExprString(expr))
+}
+
+// 查找包含联合类型或近似类型元素的接口
+func findGenericConstraints(directory string) []GenericConstraint {
+	constraints := []GenericConstraint{}
+
+	err := walkGoFiles(directory, func(path string, f *ast.File, fset *token.FileSet) {
+		ast.Inspect(f, func(n ast.Node) bool {
+			spec, ok := n.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			interfaceType, ok := spec.Type.(*ast.InterfaceType)
+			if !ok {
+				return true
+			}
+
+			pos := fset.Position(spec.Pos())
+			constraint := GenericConstraint{
+				InterfaceName:    spec.Name.Name,
+				Location:         Location{File: path, Line: pos.Line - 1, Column: pos.Column - 1},
+				UnionTypes:       []string{},
+				ApproximateTypes: []string{},
+				ConstraintOnly:   true,
+			}
+			hasTypeSet := false
+			for _, field := range interfaceType.Methods.List {
+				if len(field.Names) > 0 {
+					constraint.ConstraintOnly = false
+					continue
+				}
+				switch field.Type.(type) {
+				case *ast.BinaryExpr, *ast.UnaryExpr:
+					hasTypeSet = true
+					collectConstraintTerms(field.Type, &constraint)
+				}
+			}
+			if hasTypeSet {
+				constraints = append(constraints, constraint)
+			}
+			return true
+		})
+	})
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "查找类型约束时出错: %v\n", err)
+	}
+
+	return constraints
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"go/types"
+	"strings"
+)
+
+// 部分方法接收 context.Context、部分不接收的接口，取消语义不一致
+type ContextPropagationInconsistency struct {
+	InterfaceName         string   `json:"interfaceName"`
+	MethodsWithContext    []string `json:"methodsWithContext"`
+	MethodsWithoutContext []string `json:"methodsWithoutContext"`
+	Location              Location `json:"location"`
+}
+
+type ContextPropagationResult struct {
+	Inconsistencies []ContextPropagationInconsistency `json:"inconsistencies"`
+}
+
+// 方法的第一个参数是否为 context.Context；规范化签名与导入别名无关，没有签名时按源码判断
+func takesContext(spec MethodSpec) bool {
+	if spec.Key != "" {
+		return strings.HasPrefix(spec.Key, "(context.Context,") || strings.HasPrefix(spec.Key, "(context.Context)")
+	}
+	if spec.Func == nil {
+		return false
+	}
+	params := expandFields(spec.Func.Params)
+	return len(params) > 0 && types.ExprString(params[0]) == "context.Context"
+}
+
+// 查找直接声明的方法中，只有一部分以 context.Context 作为第一个参数的接口
+func findContextPropagation(directory string) []ContextPropagationInconsistency {
+	results := []ContextPropagationInconsistency{}
+
+	for _, iface := range findAllInterfacesWithMethods(directory) {
+		withContext, withoutContext := []string{}, []string{}
+		for _, spec := range iface.Specs {
+			if spec.embedded {
+				continue
+			}
+			if takesContext(spec) {
+				withContext = append(withContext, spec.Name)
+			} else {
+				withoutContext = append(withoutContext, spec.Name)
+			}
+		}
+		if len(withContext) == 0 || len(withoutContext) == 0 {
+			continue
+		}
+		results = append(results, ContextPropagationInconsistency{
+			InterfaceName:         iface.Name,
+			MethodsWithContext:    withContext,
+			MethodsWithoutContext: withoutContext,
+			Location:              iface.Location,
+		})
+	}
+
+	return results
+}
@@ -0,0 +1,57 @@
+package main
+
+// 接口中嵌入的其他包的接口，例如 io.Reader、context.Context
+type CrossPackageEmbedding struct {
+	InterfaceName     string `json:"interfaceName"`
+	PackageName       string `json:"packageName"`
+	EmbeddedPackage   string `json:"embeddedPackage"`
+	EmbeddedInterface string `json:"embeddedInterface"`
+	Stdlib            bool   `json:"stdlib"`
+	// 是否解析到了被嵌入接口的方法集（标准库接口需要 --stdlib-embeddings 或 --goroot）
+	Resolved bool     `json:"resolved"`
+	Methods  []string `json:"methods"`
+	Location Location `json:"location"`
+}
+
+type CrossPackageEmbeddingResult struct {
+	Embeddings []CrossPackageEmbedding `json:"embeddings"`
+}
+
+// 查找嵌入了其他包接口的接口，并解析被嵌入接口的方法
+func findCrossPackageEmbeddings(directory string) []CrossPackageEmbedding {
+	embeddings := []CrossPackageEmbedding{}
+	scan := scanDirectory(directory)
+
+	resolver := newInterfaceResolver()
+	for i := range scan.Interfaces {
+		resolver.add(&scan.Interfaces[i])
+	}
+
+	for i := range scan.Interfaces {
+		iface := &scan.Interfaces[i]
+		for _, embed := range iface.Embeds {
+			if embed.ImportPath == "" {
+				continue
+			}
+			embedding := CrossPackageEmbedding{
+				InterfaceName:     iface.Name,
+				PackageName:       iface.PackageName,
+				EmbeddedPackage:   embed.ImportPath,
+				EmbeddedInterface: embed.Name,
+				Stdlib:            isStdlibPath(embed.ImportPath),
+				Methods:           []string{},
+				Location:          iface.Location,
+			}
+			if target := resolver.lookup(iface, embed); target != nil {
+				resolver.flatten(target)
+				embedding.Resolved = true
+				for _, spec := range target.Specs {
+					embedding.Methods = append(embedding.Methods, spec.Signature)
+				}
+			}
+			embeddings = append(embeddings, embedding)
+		}
+	}
+
+	return embeddings
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// 在方法体中直接调用数据库的接口实现方法（应当通过 repository 接口访问）
+type DatabaseCall struct {
+	ReceiverType string `json:"receiverType"`
+	MethodName   string `json:"methodName"`
+	// 调用表达式，例如 s.db.QueryRow、tx.Exec
+	DBFunc   string   `json:"dbFunc"`
+	Location Location `json:"location"`
+}
+
+type DatabaseCallResult struct {
+	Calls []DatabaseCall `json:"calls"`
+}
+
+// database/sql、sqlx、gorm 中执行查询的方法名。没有类型信息，只按方法名和参数个数判断
+var databaseFuncs = map[string]bool{
+	"Query": true, "QueryContext": true, "QueryRow": true, "QueryRowContext": true,
+	"Exec": true, "ExecContext": true,
+	"Queryx": true, "QueryRowx": true, "NamedExec": true, "NamedQuery": true, "MustExec": true,
+	"Find": true, "Save": true,
+}
+
+// 查找方法体中调用了数据库查询方法的接口实现方法，每个调用输出一条
+func findDatabaseCalls(directory string) []DatabaseCall {
+	results := []DatabaseCall{}
+
+	for _, method := range uniqueImplementedMethods(matchAllImplementations(directory)) {
+		if method.fset == nil || method.FuncDecl.Body == nil {
+			continue
+		}
+		ast.Inspect(method.FuncDecl.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			selector, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || !databaseFuncs[selector.Sel.Name] {
+				return true
+			}
+			pos := method.fset.Position(call.Pos())
+			results = append(results, DatabaseCall{
+				ReceiverType: method.ReceiverType,
+				MethodName:   method.Name,
+				DBFunc:       types.ExprString(call.Fun),
+				Location:     Location{File: pos.Filename, Line: pos.Line - 1, Column: pos.Column - 1},
+			})
+			return true
+		})
+	}
+
+	return results
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+)
+
+// 方法体只返回零值的接口实现方法，通常是空实现或桩
+type DefaultValueImplementation struct {
+	ReceiverType string `json:"receiverType"`
+	MethodName   string `json:"methodName"`
+	// 返回的零值，多个返回值以逗号分隔，例如 0, nil
+	ReturnValue string   `json:"returnValue"`
+	Location    Location `json:"location"`
+}
+
+type DefaultValueResult struct {
+	Implementations []DefaultValueImplementation `json:"implementations"`
+}
+
+// 表达式是否为零值字面量：nil、false、0、0.0 或空字符串（解释型和原始字符串）
+func isZeroLiteral(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name == "nil" || e.Name == "false"
+	case *ast.BasicLit:
+		switch e.Kind {
+		case token.STRING:
+			value, err := strconv.Unquote(e.Value)
+			return err == nil && value == ""
+		case token.INT, token.FLOAT:
+			value, err := strconv.ParseFloat(strings.ReplaceAll(e.Value, "_", ""), 64)
+			return err == nil && value == 0
+		}
+	case *ast.ParenExpr:
+		return isZeroLiteral(e.X)
+	}
+	return false
+}
+
+// 查找方法体只有一条 return 语句、且返回值全部是零值字面量的接口实现方法
+func findDefaultValueImplementations(directory string) []DefaultValueImplementation {
+	results := []DefaultValueImplementation{}
+
+	for _, method := range uniqueImplementedMethods(matchAllImplementations(directory)) {
+		body := method.FuncDecl.Body
+		if method.fset == nil || body == nil || len(body.List) != 1 {
+			continue
+		}
+		ret, ok := body.List[0].(*ast.ReturnStmt)
+		if !ok || len(ret.Results) == 0 {
+			continue
+		}
+
+		values := make([]string, 0, len(ret.Results))
+		for _, result := range ret.Results {
+			if !isZeroLiteral(result) {
+				values = nil
+				break
+			}
+			values = append(values, types.ExprString(result))
+		}
+		if values == nil {
+			continue
+		}
+
+		pos := method.fset.Position(ret.Pos())
+		results = append(results, DefaultValueImplementation{
+			ReceiverType: method.ReceiverType,
+			MethodName:   method.Name,
+			ReturnValue:  strings.Join(values, ", "),
+			Location:     Location{File: pos.Filename, Line: pos.Line - 1, Column: pos.Column - 1},
+		})
+	}
+
+	return results
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+)
+
+// 只是把调用原样转发给接收者字段上同名方法的接口实现方法（装饰器、代理、包装器）
+type DelegationMethod struct {
+	ReceiverType string `json:"receiverType"`
+	MethodName   string `json:"methodName"`
+	// 被转发的字段，例如 inner
+	DelegateField string `json:"delegateField"`
+	// 字段的类型，找不到字段定义时为空
+	DelegateType string   `json:"delegateType"`
+	Location     Location `json:"location"`
+}
+
+type DelegationResult struct {
+	Methods []DelegationMethod `json:"methods"`
+}
+
+// 方法体中唯一的调用语句：return r.field.M(...) 或 r.field.M(...)
+func soleCall(body *ast.BlockStmt) (*ast.CallExpr, bool) {
+	if len(body.List) != 1 {
+		return nil, false
+	}
+	var expr ast.Expr
+	switch stmt := body.List[0].(type) {
+	case *ast.ReturnStmt:
+		if len(stmt.Results) != 1 {
+			return nil, false
+		}
+		expr = stmt.Results[0]
+	case *ast.ExprStmt:
+		expr = stmt.X
+	default:
+		return nil, false
+	}
+	call, ok := expr.(*ast.CallExpr)
+	return call, ok
+}
+
+// 调用参数是否按顺序原样传递了方法的全部参数（可变参数需要以 args... 传递）
+func passesParamsThrough(funcType *ast.FuncType, call *ast.CallExpr) bool {
+	var params []string
+	variadic := false
+	for _, field := range funcType.Params.List {
+		if len(field.Names) == 0 {
+			return false
+		}
+		for _, name := range field.Names {
+			params = append(params, name.Name)
+		}
+		_, variadic = field.Type.(*ast.Ellipsis)
+	}
+	if len(params) != len(call.Args) || variadic != call.Ellipsis.IsValid() {
+		return false
+	}
+	for i, arg := range call.Args {
+		ident, ok := arg.(*ast.Ident)
+		if !ok || ident.Name != params[i] || ident.Name == "_" {
+			return false
+		}
+	}
+	return true
+}
+
+// 查找方法体只有一条 return r.field.M(args...) 的接口实现方法：M 与方法同名，参数原样传递
+func findDelegationMethods(directory string) []DelegationMethod {
+	results := []DelegationMethod{}
+	scan := scanDirectory(directory)
+
+	for _, method := range uniqueImplementedMethods(matchImplementations(scan)) {
+		decl := method.FuncDecl
+		if method.fset == nil || decl.Body == nil || len(decl.Recv.List[0].Names) == 0 {
+			continue
+		}
+		call, ok := soleCall(decl.Body)
+		if !ok {
+			continue
+		}
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || selector.Sel.Name != method.Name {
+			continue
+		}
+		field, ok := receiverField(selector.X, decl.Recv.List[0].Names[0].Name)
+		if !ok || !passesParamsThrough(decl.Type, call) {
+			continue
+		}
+
+		delegateType := ""
+		if info, ok := scan.Types[method.Package+":"+strings.TrimPrefix(method.ReceiverType, "*")]; ok {
+			if fieldType, ok := info.fields[field]; ok {
+				delegateType = types.ExprString(fieldType)
+			}
+		}
+		pos := method.fset.Position(decl.Body.List[0].Pos())
+		results = append(results, DelegationMethod{
+			ReceiverType:  method.ReceiverType,
+			MethodName:    method.Name,
+			DelegateField: field,
+			DelegateType:  delegateType,
+			Location:      Location{File: pos.Filename, Line: pos.Line - 1, Column: pos.Column - 1},
+		})
+	}
+
+	return results
+}
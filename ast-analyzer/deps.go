@@ -0,0 +1,80 @@
+package main
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// 两个包通过接口互相依赖的警告
+type CircularDependencyWarning struct {
+	PackageA string   `json:"packageA"`
+	PackageB string   `json:"packageB"`
+	Via      []string `json:"via"`
+}
+
+type CircularDependencyResult struct {
+	Warnings []CircularDependencyWarning `json:"warnings"`
+}
+
+// 包目录相对于扫描根目录的路径，用于输出
+func packageLabel(root, dir string) string {
+	if rel, err := filepath.Rel(root, dir); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return dir
+}
+
+// 查找互相实现对方接口的包对：A 中的类型实现了 B 的接口，同时 B 中的类型实现了 A 的接口
+func findInterfaceCircularDependencies(root string) []CircularDependencyWarning {
+	warnings := []CircularDependencyWarning{}
+
+	// 实现方所在包 -> 接口所在包 -> 依赖说明
+	edges := make(map[string]map[string][]string)
+	for _, match := range matchAllImplementations(root) {
+		implPackage := packageOf(match.Methods)
+		ifacePackage := match.Interface.Package
+		if implPackage == ifacePackage {
+			continue
+		}
+
+		var typeName string
+		for _, method := range match.Methods {
+			typeName = method.ReceiverType
+			break
+		}
+		via := packageLabel(root, implPackage) + "." + typeName + " implements " +
+			packageLabel(root, ifacePackage) + "." + match.Interface.Name
+
+		if edges[implPackage] == nil {
+			edges[implPackage] = make(map[string][]string)
+		}
+		edges[implPackage][ifacePackage] = append(edges[implPackage][ifacePackage], via)
+	}
+
+	packages := make([]string, 0, len(edges))
+	for pkg := range edges {
+		packages = append(packages, pkg)
+	}
+	sort.Strings(packages)
+
+	for _, a := range packages {
+		for _, b := range packages {
+			if a >= b {
+				continue
+			}
+			forward, backward := edges[a][b], edges[b][a]
+			if len(forward) == 0 || len(backward) == 0 {
+				continue
+			}
+			via := append(append([]string{}, forward...), backward...)
+			sort.Strings(via)
+			warnings = append(warnings, CircularDependencyWarning{
+				PackageA: packageLabel(root, a),
+				PackageB: packageLabel(root, b),
+				Via:      via,
+			})
+		}
+	}
+
+	return warnings
+}
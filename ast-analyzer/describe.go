@@ -0,0 +1,128 @@
+package main
+
+import "strings"
+
+// 接口描述中的方法
+type DescribedMethod struct {
+	Name      string   `json:"name"`
+	Signature string   `json:"signature"`
+	Location  Location `json:"location"`
+	// 树中提供了匹配方法（名称和签名一致）的类型数量
+	ImplementedBy int `json:"implementedBy"`
+	// 签名中引用的其他包及其在接口文件中的别名
+	Imports []SignatureImport `json:"imports,omitempty"`
+	// --with-stubs 时按返回类型生成的默认实现，例如 return "", nil
+	DefaultBody string `json:"defaultBody,omitempty"`
+}
+
+// 接口描述中的嵌入接口
+type DescribedEmbed struct {
+	// 源码中的名称，其他包的接口以导入路径限定，例如 io.Reader、example.com/app/auth.TokenStore
+	Name     string   `json:"name"`
+	Location Location `json:"location"`
+}
+
+// 接口描述
+type InterfaceDescription struct {
+	Name        string            `json:"name"`
+	Package     string            `json:"package"`
+	PackageName string            `json:"packageName"`
+	Location    Location          `json:"location"`
+	EndLocation Location          `json:"endLocation"`
+	Doc         string            `json:"doc,omitempty"`
+	TypeParams  []TypeParam       `json:"typeParams,omitempty"`
+	Methods     []DescribedMethod `json:"methods"`
+	// 直接嵌入的接口及其引用位置
+	Embeds []DescribedEmbed `json:"embeds,omitempty"`
+	// 含未导出方法的接口只能被同包类型实现
+	Sealed bool `json:"sealed"`
+	// 接口被赋值、返回或用作字段类型的位置（仅 describe-interface 填充）
+	UsageLocations []UsageLocation `json:"usageLocations,omitempty"`
+}
+
+type DescribeResult struct {
+	Interfaces []InterfaceDescription `json:"interfaces"`
+}
+
+// 描述目录中指定名称的接口（不同包中可能存在同名接口）
+func describeInterface(directory, interfaceName string) []InterfaceDescription {
+	var descriptions []InterfaceDescription
+
+	scan := scanDirectory(directory)
+	var described []*InterfaceInfo
+	for i := range scan.Interfaces {
+		iface := &scan.Interfaces[i]
+		if iface.Name != interfaceName {
+			continue
+		}
+		description := newInterfaceDescription(*iface)
+		for i, spec := range iface.Specs {
+			description.Methods[i].ImplementedBy = countMethodProviders(scan.TypeMethods, iface, spec)
+			if options.Has("with-stubs") {
+				description.Methods[i].DefaultBody = defaultBody(spec, scan.Types)
+			}
+		}
+		described = append(described, iface)
+		descriptions = append(descriptions, description)
+	}
+
+	// 使用位置从扫描时解析的文件中一次查出，不再重新遍历目录
+	for i, usages := range findUsageLocations(scan.files, described) {
+		descriptions[i].UsageLocations = usages
+	}
+	return descriptions
+}
+
+func newInterfaceDescription(iface InterfaceInfo) InterfaceDescription {
+	description := InterfaceDescription{
+		Name:        iface.Name,
+		Package:     iface.Package,
+		PackageName: iface.PackageName,
+		Location:    iface.Location,
+		EndLocation: iface.EndLocation,
+		Doc:         iface.Doc,
+		TypeParams:  iface.TypeParams,
+		Methods:     []DescribedMethod{},
+		Sealed:      iface.Sealed(),
+	}
+
+	for _, spec := range iface.Specs {
+		description.Methods = append(description.Methods, DescribedMethod{
+			Name:      spec.Name,
+			Signature: spec.Signature,
+			Location:  spec.Location,
+			Imports:   spec.Imports,
+		})
+	}
+
+	for _, embed := range iface.Embeds {
+		name := embed.Name
+		if embed.ImportPath != "" {
+			name = embed.ImportPath + "." + name
+		}
+		description.Embeds = append(description.Embeds, DescribedEmbed{Name: name, Location: embed.Location})
+	}
+
+	return description
+}
+
+// 统计提供了某个接口方法的类型数量
+func countMethodProviders(allTypeMethods map[string]map[string]*MethodInfo, iface *InterfaceInfo, spec MethodSpec) int {
+	count := 0
+	for typeKey, methods := range allTypeMethods {
+		// 值类型和指针类型是同一个类型，只统计一次
+		if valueMethods, ok := allTypeMethods[strings.Replace(typeKey, ":*", ":", 1)]; ok && strings.Contains(typeKey, ":*") {
+			if _, provided := valueMethods[spec.Name]; provided {
+				continue
+			}
+		}
+		method, ok := methods[spec.Name]
+		if !ok || !iface.visibleTo(method.Package) {
+			continue
+		}
+		if spec.Key == "" || method.Key == "" || spec.Key == method.Key {
+			count++
+		}
+	}
+	return count
+}
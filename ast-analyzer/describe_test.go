@@ -0,0 +1,143 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func usageNames(usages []UsageLocation) []string {
+	var names []string
+	for _, usage := range usages {
+		names = append(names, usage.Kind+" "+usage.Name)
+	}
+	return names
+}
+
+func TestDescribeInterfaceUsageLocations(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"store/store.go": `package store
+
+type Store interface{ Get(key string) string }
+
+type memory struct{}
+
+func (memory) Get(key string) string { return key }
+
+var Default Store = memory{}
+
+func New() Store {
+	return memory{}
+}
+`,
+		"app/app.go": `package app
+
+import "example.com/m/store"
+
+type Server struct {
+	cache store.Store
+}
+
+func (s *Server) Reset() {
+	var backup store.Store
+	backup = store.New()
+	s.cache = backup
+}
+`,
+		// 同名但无关的接口，使用位置分别统计
+		"other/other.go": `package other
+
+type Store interface{ Put(key string) }
+
+var Fallback Store
+`,
+		"go.mod": "module example.com/m\n",
+	})
+	recorder := useRecordingFS(t, nil)
+
+	result := runArgs(t, "describe-interface", dir, "Store").(DescribeResult)
+	got := make(map[string][]string)
+	for _, description := range result.Interfaces {
+		got[description.PackageName] = usageNames(description.UsageLocations)
+	}
+	want := map[string][]string{
+		"store": {"field Server.cache", "var backup", "assign backup", "var Default", "return New"},
+		"other": {"var Fallback"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got usages %v, want %v", got, want)
+	}
+
+	// 使用位置复用扫描时解析的文件，每个文件只读取一次
+	for _, name := range []string{"store.go", "app.go", "other.go"} {
+		if recorder.opened[name] != 1 {
+			t.Errorf("%s was read %d times, want 1", name, recorder.opened[name])
+		}
+	}
+}
+
+// 分组 type ( ... ) 声明中的接口：文档和范围取自各自的 TypeSpec，只有单个 spec 的分组才使用分组的文档
+const groupedInterfaces = `package p
+
+// Group doc belongs to the block.
+type (
+	// A doc.
+	A interface{ MA() }
+
+	B interface {
+		MB()
+	}
+)
+
+// Single doc.
+type (
+	Single interface{ MS() }
+)
+
+// Plain doc.
+type Plain interface{ MP() }
+`
+
+func TestDescribeInterfaceGroupedDeclarations(t *testing.T) {
+	dir := writeTree(t, map[string]string{"a.go": groupedInterfaces})
+	tests := []struct {
+		name       string
+		doc        string
+		start, end Location
+	}{
+		{"A", "A doc.\n", Location{Line: 5, Column: 1}, Location{Line: 5, Column: 20}},
+		{"B", "", Location{Line: 7, Column: 1}, Location{Line: 9, Column: 2}},
+		{"Single", "Single doc.\n", Location{Line: 14, Column: 1}, Location{Line: 14, Column: 25}},
+		{"Plain", "Plain doc.\n", Location{Line: 18, Column: 5}, Location{Line: 18, Column: 28}},
+	}
+	for _, tt := range tests {
+		result := runArgs(t, "describe-interface", dir, tt.name).(DescribeResult)
+		if len(result.Interfaces) != 1 {
+			t.Fatalf("%s: got %d interfaces", tt.name, len(result.Interfaces))
+		}
+		got := result.Interfaces[0]
+		if got.Doc != tt.doc {
+			t.Errorf("%s: doc = %q, want %q", tt.name, got.Doc, tt.doc)
+		}
+		if got.Location.Line != tt.start.Line || got.Location.Column != tt.start.Column ||
+			got.EndLocation.Line != tt.end.Line || got.EndLocation.Column != tt.end.Column {
+			t.Errorf("%s: range %d:%d-%d:%d, want %d:%d-%d:%d", tt.name,
+				got.Location.Line, got.Location.Column, got.EndLocation.Line, got.EndLocation.Column,
+				tt.start.Line, tt.start.Column, tt.end.Line, tt.end.Column)
+		}
+	}
+}
+
+// 文件大纲（find-file-interfaces）中 --lens-anchor interface-line 的锚点指向各自的接口，而不是分组的开头
+func TestFileOutlineGroupedDeclarations(t *testing.T) {
+	dir := writeTree(t, map[string]string{"a.go": groupedInterfaces})
+	result := runArgs(t, "find-file-interfaces", filepath.Join(dir, "a.go"), "--lens-anchor", "interface-line").(AnalysisResult)
+	got := make(map[string]int)
+	for _, method := range result.Interfaces {
+		got[method.InterfaceName] = method.Anchor.Line
+	}
+	want := map[string]int{"A": 5, "B": 7, "Single": 14, "Plain": 18}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got anchor lines %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+// 分析过程中发现的问题（不影响结果，但源码可能有误）
+type Diagnostic struct {
+	Kind       string   `json:"kind"`
+	Message    string   `json:"message"`
+	Interfaces []string `json:"interfaces,omitempty"`
+	Location   Location `json:"location"`
+}
+
+// 本次命令收集到的诊断，同一问题在多次展开中只记录一次
+var diagnostics struct {
+	list []Diagnostic
+	seen map[string]bool
+}
+
+func resetDiagnostics() {
+	diagnostics.list = nil
+	diagnostics.seen = make(map[string]bool)
+}
+
+func reportDiagnostic(d Diagnostic) {
+	if diagnostics.seen == nil {
+		diagnostics.seen = make(map[string]bool)
+	}
+	if diagnostics.seen[d.Kind+"|"+d.Message] {
+		return
+	}
+	diagnostics.seen[d.Kind+"|"+d.Message] = true
+	diagnostics.list = append(diagnostics.list, d)
+}
+
+// 有诊断时在结果中追加 diagnostics 字段
+func appendDiagnostics(output []byte) []byte {
+	if len(diagnostics.list) == 0 {
+		return output
+	}
+	return appendJSONField(output, "diagnostics", diagnostics.list)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// 结构体的多个嵌入字段在同一深度提供同名方法：该方法不会被提升，直接调用是编译错误（ambiguous selector）
+type EmbeddingConflict struct {
+	StructType        string `json:"structType"`
+	ConflictingMethod string `json:"conflictingMethod"`
+	// 提供该方法的嵌入字段（按声明顺序），例如 *Base、io.Reader
+	Sources  []string `json:"sources"`
+	Location Location `json:"location"`
+}
+
+type EmbeddingConflictResult struct {
+	Conflicts []EmbeddingConflict `json:"conflicts"`
+}
+
+// 查找有多个嵌入字段的结构体中有歧义的方法。较浅的同名方法会遮蔽较深的方法，这种情况不算冲突
+func findEmbeddingConflicts(directory string) []EmbeddingConflict {
+	results := []EmbeddingConflict{}
+
+	scan := scanDirectory(directory)
+	for key, methods := range scan.ambiguous {
+		info, ok := scan.Types[key]
+		if !ok || info.Kind != "struct" || len(info.Embeds) < 2 {
+			continue
+		}
+		for name, sources := range methods {
+			results = append(results, EmbeddingConflict{
+				StructType:        info.Name,
+				ConflictingMethod: name,
+				Sources:           sources,
+				Location:          info.Location,
+			})
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Location.File != b.Location.File {
+			return a.Location.File < b.Location.File
+		}
+		if a.Location.Line != b.Location.Line {
+			return a.Location.Line < b.Location.Line
+		}
+		return strings.Compare(a.ConflictingMethod, b.ConflictingMethod) < 0
+	})
+	return results
+}
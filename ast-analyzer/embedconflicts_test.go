@@ -0,0 +1,55 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// A 和 B 在同一个文件中声明同名方法 Close
+const embedConflictFixture = `package p
+
+type A struct{}
+
+func (A) Close() error { return nil }
+func (A) Read()        {}
+
+type B struct{}
+
+func (*B) Close() error { return nil }
+
+type Both struct {
+	A
+	*B
+}
+
+type Shadowed struct {
+	A
+	*B
+}
+
+func (Shadowed) Close() error { return nil }
+
+type Inner struct{ A }
+
+type Shallower struct {
+	Inner
+	*B
+}
+`
+
+func TestEmbeddedStructMethodConflicts(t *testing.T) {
+	dir := writeTree(t, map[string]string{"p.go": embedConflictFixture})
+	conflicts := runArgs(t, "find-interface-embedded-struct-method-conflicts", dir).(EmbeddingConflictResult).Conflicts
+
+	// Shadowed 自己声明了 Close；Shallower 中 *B 的 Close 比 Inner 提升的更浅
+	want := []EmbeddingConflict{{StructType: "Both", ConflictingMethod: "Close", Sources: []string{"A", "*B"}}}
+	for i := range conflicts {
+		if conflicts[i].Location.Line != 11 {
+			t.Errorf("%s at line %d, want the declaration of Both", conflicts[i].StructType, conflicts[i].Location.Line)
+		}
+		conflicts[i].Location = Location{}
+	}
+	if !reflect.DeepEqual(conflicts, want) {
+		t.Fatalf("got %+v, want %+v", conflicts, want)
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"io"
+)
+
+// serve 模式响应的编码层，命令逻辑只产生结果值，与编码方式无关
+type responseEncoder interface {
+	Encode(response serveResponse) error
+}
+
+// 默认编码：每行一个 JSON 对象，结果经 formatResult 处理（--lsp、meta）
+type jsonResponseEncoder struct {
+	encoder *json.Encoder
+}
+
+func newJSONResponseEncoder(w io.Writer) *jsonResponseEncoder {
+	return &jsonResponseEncoder{encoder: json.NewEncoder(w)}
+}
+
+func (e *jsonResponseEncoder) Encode(response serveResponse) error {
+	if _, raw := response.Result.(json.RawMessage); !raw && response.Result != nil {
+		response.Result = json.RawMessage(formatResult(response.Result))
+	}
+	return e.encoder.Encode(response)
+}
+
+// gob 编码的响应；meta 单独成字段，位置始终是原始的 file/line/column 形式
+type gobResponse struct {
+	ID      []byte
+	Warming bool
+	Result  interface{}
+	Meta    *AnalysisMeta
+	Error   string
+	Panic   bool
+}
+
+// 长度前缀的 gob 编码：每条消息先写 4 字节大端长度，再写 gob 数据。
+// 同一连接共用一个 gob.Encoder，类型描述只在第一次出现时发送，客户端需要按顺序用同一个解码器读取
+type gobResponseEncoder struct {
+	w       io.Writer
+	buf     bytes.Buffer
+	encoder *gob.Encoder
+}
+
+func newGobResponseEncoder(w io.Writer) *gobResponseEncoder {
+	e := &gobResponseEncoder{w: w}
+	e.encoder = gob.NewEncoder(&e.buf)
+	return e
+}
+
+func (e *gobResponseEncoder) Encode(response serveResponse) error {
+	message := gobResponse{
+		ID:      response.ID,
+		Warming: response.Warming,
+		Result:  response.Result,
+		Error:   response.Error,
+		Panic:   response.Panic,
+	}
+	if response.Result != nil && scanStats.directory != "" {
+		meta := collectMeta()
+		message.Meta = &meta
+	}
+
+	e.buf.Reset()
+	if err := e.encoder.Encode(message); err != nil {
+		return err
+	}
+
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(e.buf.Len()))
+	if _, err := e.w.Write(length[:]); err != nil {
+		return err
+	}
+	_, err := e.w.Write(e.buf.Bytes())
+	return err
+}
+
+// 通过 interface{} 传输、需要向 gob 登记的结果类型；新增命令的结果类型要加在这里
+var gobResultTypes = []interface{}{
+	AnalysisResult{},
+	PackageAnalysisResult{},
+	BodySizeResult{},
+	TimelineResult{},
+	NamingPatternResult{},
+	CircularDependencyResult{},
+	ReturnCountResult{},
+	HigherOrderResult{},
+	GenericConstraintResult{},
+	TypeInterfacesResult{},
+	InitOrderResult{},
+	SynchronizationResult{},
+	PackagesAnalysisResult{},
+	GenericImplementationResult{},
+	PackageTreeResult{},
+	MockInfoResult{},
+	CrossPackageEmbeddingResult{},
+	FluentInterfaceResult{},
+	SuggestionResult{},
+	ContextPropagationResult{},
+	ErrorWrappingResult{},
+	LoggingResult{},
+	SynthesizeResult{},
+	IOOperationResult{},
+	NetworkCallResult{},
+	DatabaseCallResult{},
+	CacheUsageResult{},
+	ModuleInterfacesResult{},
+	RetryPatternResult{},
+	GraphResult{},
+	TestCoverageResult{},
+	DefaultValueResult{},
+	DelegationResult{},
+	TransactionResult{},
+	ConstrainedTypeParamResult{},
+	ComplexityRankingResult{},
+	FileMapResult{},
+	StructLiteralReturnResult{},
+	ExplainResult{},
+	StreamTrailer{},
+	NilSafeResult{},
+	EmbeddingConflictResult{},
+	InterfaceComparison{},
+	StubResult{},
+	FileDistributionResult{},
+	SelfReturnResult{},
+	StubImplementationResult{},
+	MultipleInterfaceResult{},
+	AccessPatternResult{},
+	DescribeResult{},
+}
+
+func init() {
+	for _, result := range gobResultTypes {
+		gob.Register(result)
+	}
+}
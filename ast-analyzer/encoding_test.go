@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 读取 gobResponseEncoder 写出的长度前缀消息；同一连接的消息必须用同一个 decoder 按顺序解码
+type gobResponseReader struct {
+	r       io.Reader
+	frame   *bytes.Reader
+	decoder *gob.Decoder
+}
+
+func newGobResponseReader(r io.Reader) *gobResponseReader {
+	// bytes.Reader 实现了 io.ByteReader，decoder 不会额外缓冲，每条消息换入新的数据即可
+	frame := bytes.NewReader(nil)
+	return &gobResponseReader{r: r, frame: frame, decoder: gob.NewDecoder(frame)}
+}
+
+func (g *gobResponseReader) read() (gobResponse, error) {
+	var length [4]byte
+	if _, err := io.ReadFull(g.r, length[:]); err != nil {
+		return gobResponse{}, err
+	}
+	payload := make([]byte, binary.BigEndian.Uint32(length[:]))
+	if _, err := io.ReadFull(g.r, payload); err != nil {
+		return gobResponse{}, err
+	}
+	g.frame.Reset(payload)
+	var response gobResponse
+	err := g.decoder.Decode(&response)
+	return response, err
+}
+
+// 用非零值填满导出字段：切片和映射各放一个元素，递归深度有限，避免自引用类型无限展开
+func populate(v reflect.Value, depth int) {
+	if depth > 5 {
+		return
+	}
+	if v.Type() == reflect.TypeOf(time.Time{}) {
+		v.Set(reflect.ValueOf(time.Unix(1700000000, 0).UTC()))
+		return
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString("s" + fmt.Sprint(depth))
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(depth + 1))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(uint64(depth + 1))
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(float64(depth) + 0.5)
+	case reflect.Pointer:
+		v.Set(reflect.New(v.Type().Elem()))
+		populate(v.Elem(), depth+1)
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		populate(v.Index(0), depth+1)
+	case reflect.Map:
+		v.Set(reflect.MakeMap(v.Type()))
+		key := reflect.New(v.Type().Key()).Elem()
+		value := reflect.New(v.Type().Elem()).Elem()
+		populate(key, depth+1)
+		populate(value, depth+1)
+		v.SetMapIndex(key, value)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				populate(v.Field(i), depth+1)
+			}
+		}
+	}
+}
+
+func TestGobResponseEncoderRoundTripsEveryResultType(t *testing.T) {
+	var stream bytes.Buffer
+	encoder := newGobResponseEncoder(&stream)
+	reader := newGobResponseReader(&stream)
+
+	for _, registered := range gobResultTypes {
+		value := reflect.New(reflect.TypeOf(registered)).Elem()
+		populate(value, 0)
+		want := value.Interface()
+
+		// 所有结果走同一个连接，后面的消息依赖前面发送过的类型描述
+		if err := encoder.Encode(serveResponse{ID: json.RawMessage(`1`), Result: want}); err != nil {
+			t.Fatalf("%T: encode: %v", want, err)
+		}
+		response, err := reader.read()
+		if err != nil {
+			t.Fatalf("%T: decode: %v", want, err)
+		}
+		if !reflect.DeepEqual(response.Result, want) {
+			t.Errorf("%T did not round-trip:\n got %#v\nwant %#v", want, response.Result, want)
+		}
+	}
+}
+
+// 命令结果类型（XxxResult）都要登记到 gobResultTypes，否则 gob 编码时报错
+func TestEveryResultTypeIsRegisteredWithGob(t *testing.T) {
+	registered := make(map[string]bool)
+	for _, result := range gobResultTypes {
+		registered[reflect.TypeOf(result).Name()] = true
+	}
+
+	pkgs, err := parser.ParseDir(token.NewFileSet(), ".", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range pkgs["main"].Files {
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				name := spec.(*ast.TypeSpec).Name.Name
+				// ScanResult 是内部的扫描结果，不会输出
+				if strings.HasSuffix(name, "Result") && ast.IsExported(name) && name != "ScanResult" && !registered[name] {
+					t.Errorf("%s is not in gobResultTypes", name)
+				}
+			}
+		}
+	}
+}
+
+// 10k 个实现的 find-implementations 响应
+func largeAnalysisResult() AnalysisResult {
+	result := AnalysisResult{Implementations: make([]Implementation, 0, 10000)}
+	for i := 0; i < 10000; i++ {
+		file := fmt.Sprintf("/work/repo/pkg%d/file%d.go", i%50, i%400)
+		location := func(line, column int) Location {
+			return Location{File: file, Line: line, Column: column, oneBased: true}
+		}
+		receiver := fmt.Sprintf("*Type%d", i)
+		result.Implementations = append(result.Implementations, Implementation{
+			MethodName:          "Handle",
+			ReceiverType:        receiver,
+			Location:            location(i%900+1, 1),
+			EndLocation:         location(i%900+12, 1),
+			NameLocation:        location(i%900+1, 18),
+			NameEndLocation:     location(i%900+1, 24),
+			ReceiverName:        "h",
+			ReceiverLocation:    location(i%900+1, 7),
+			ReceiverEndLocation: location(i%900+1, 16),
+			Kind:                "struct",
+			Signature:           "Handle(ctx context.Context, req *Request) error",
+			Interface:           "Handler",
+			Label:               receiver + ".Handle(ctx context.Context, req *Request) error",
+			ImportRelation:      "imports-interface-package",
+		})
+	}
+	return result
+}
+
+// 编码并由客户端解码一条 10k 结果的响应，对比 JSON 和 gob 两种编码
+func BenchmarkServeResponse10k(b *testing.B) {
+	result := largeAnalysisResult()
+	response := serveResponse{ID: json.RawMessage(`1`), Result: result}
+
+	b.Run("json", func(b *testing.B) {
+		var stream bytes.Buffer
+		encoder := newJSONResponseEncoder(&stream)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			stream.Reset()
+			if err := encoder.Encode(response); err != nil {
+				b.Fatal(err)
+			}
+			b.SetBytes(int64(stream.Len()))
+			var decoded struct {
+				Result AnalysisResult `json:"result"`
+			}
+			if err := json.Unmarshal(stream.Bytes(), &decoded); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("gob", func(b *testing.B) {
+		var stream bytes.Buffer
+		encoder := newGobResponseEncoder(&stream)
+		reader := newGobResponseReader(&stream)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := encoder.Encode(response); err != nil {
+				b.Fatal(err)
+			}
+			b.SetBytes(int64(stream.Len()))
+			if _, err := reader.read(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
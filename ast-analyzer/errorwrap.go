@@ -0,0 +1,92 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// 直接返回 err、没有用 fmt.Errorf("...: %w", err) 包装的位置
+type UnwrappedError struct {
+	ReceiverType string   `json:"receiverType"`
+	MethodName   string   `json:"methodName"`
+	Location     Location `json:"location"`
+}
+
+type ErrorWrappingResult struct {
+	Errors []UnwrappedError `json:"errors"`
+}
+
+// 是否为带 %w 的 fmt.Errorf 调用
+func isWrappingCall(expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || types.ExprString(call.Fun) != "fmt.Errorf" || len(call.Args) == 0 {
+		return false
+	}
+	format, ok := call.Args[0].(*ast.BasicLit)
+	return ok && format.Kind == token.STRING && strings.Contains(format.Value, "%w")
+}
+
+// 方法体中直接返回 err 的 return 语句（嵌套的函数字面量有自己的返回值，不计入）。
+// 之前已经执行过 err = fmt.Errorf("...: %w", err) 的视为已包装
+func unwrappedReturns(ft *ast.FuncType, body *ast.BlockStmt) []*ast.ReturnStmt {
+	results := expandFields(ft.Results)
+	var errorIndexes []int
+	for i, result := range results {
+		if ident, ok := result.(*ast.Ident); ok && ident.Name == "error" {
+			errorIndexes = append(errorIndexes, i)
+		}
+	}
+	if len(errorIndexes) == 0 || body == nil {
+		return nil
+	}
+
+	var returns []*ast.ReturnStmt
+	wrappedAt := token.NoPos
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch t := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			for i, lhs := range t.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok && ident.Name == "err" && i < len(t.Rhs) && isWrappingCall(t.Rhs[i]) {
+					wrappedAt = t.Pos()
+				}
+			}
+		case *ast.ReturnStmt:
+			if len(t.Results) != len(results) || wrappedAt.IsValid() {
+				return true
+			}
+			for _, i := range errorIndexes {
+				if ident, ok := t.Results[i].(*ast.Ident); ok && ident.Name == "err" {
+					returns = append(returns, t)
+					break
+				}
+			}
+		}
+		return true
+	})
+	return returns
+}
+
+// 查找返回 error 的接口实现方法中，没有包装就直接返回 err 的位置
+func findUnwrappedErrors(directory string) []UnwrappedError {
+	results := []UnwrappedError{}
+
+	for _, method := range uniqueImplementedMethods(matchAllImplementations(directory)) {
+		if method.fset == nil {
+			continue
+		}
+		for _, ret := range unwrappedReturns(method.FuncDecl.Type, method.FuncDecl.Body) {
+			pos := method.fset.Position(ret.Pos())
+			results = append(results, UnwrappedError{
+				ReceiverType: method.ReceiverType,
+				MethodName:   method.Name,
+				Location:     Location{File: pos.Filename, Line: pos.Line - 1, Column: pos.Column - 1},
+			})
+		}
+	}
+
+	return results
+}
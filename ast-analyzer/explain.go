@@ -0,0 +1,258 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// 匹配过程中的一步判断
+type ExplainStep struct {
+	// type、files、method、interface、embed、visibility、compare 或 excludedFile
+	Stage   string `json:"stage"`
+	OK      bool   `json:"ok"`
+	Message string `json:"message"`
+	// 失败原因：missingMethod、signatureMismatch、pointerOnly、typeParams、packageMismatch、
+	// conflictingInterface、typeNotFound、interfaceNotFound、unresolvedEmbed、excludedFile
+	Reason   string    `json:"reason,omitempty"`
+	Location *Location `json:"location,omitempty"`
+}
+
+// 一个接收者类型（T 或 *T）与一个接口的匹配结论
+type ExplainVerdict struct {
+	Interface    string `json:"interface"`
+	ReceiverType string `json:"receiverType"`
+	TypePackage  string `json:"typePackage"`
+	Satisfied    bool   `json:"satisfied"`
+	// 不满足时第一个失败的原因
+	Reason string `json:"reason,omitempty"`
+}
+
+type ExplainResult struct {
+	Type      string           `json:"type"`
+	Interface string           `json:"interface"`
+	Steps     []ExplainStep    `json:"steps"`
+	Verdicts  []ExplainVerdict `json:"verdicts"`
+}
+
+// 解释类型为什么满足或不满足接口：按匹配流程逐步记录判断，结论与 find-implementations 使用的 isExactMatch 一致
+func explainMatch(directory, typeQuery, interfaceName string) ExplainResult {
+	result := ExplainResult{Type: typeQuery, Interface: interfaceName, Steps: []ExplainStep{}, Verdicts: []ExplainVerdict{}}
+	step := func(stage string, ok bool, reason, message string, location *Location) {
+		result.Steps = append(result.Steps, ExplainStep{Stage: stage, OK: ok, Reason: reason, Message: message, Location: location})
+	}
+
+	scan := scanDirectory(directory)
+	_, typeName := parseTypeQuery(typeQuery)
+
+	// 1. 类型及其方法集（没有方法的类型只在类型定义中出现）
+	keys := matchingTypeKeys(scan, typeQuery)
+	if len(keys) == 0 {
+		for key, info := range scan.Types {
+			if info.Name == typeName && info.Kind != "interface" {
+				keys[key] = true
+			}
+		}
+	}
+	typeKeys := make([]string, 0, len(keys))
+	for key := range keys {
+		typeKeys = append(typeKeys, key)
+	}
+	sort.Strings(typeKeys)
+
+	if len(typeKeys) == 0 {
+		step("type", false, "typeNotFound", fmt.Sprintf("type %s was not found in the scanned files", typeQuery), nil)
+		for _, file := range excludedDeclarations(directory, typeName) {
+			location := Location{File: file.path}
+			message := fmt.Sprintf("%s mentions %s but is a test file, excluded from the scan without --include-tests", file.path, typeName)
+			if file.reason == excludedTooLarge {
+				message = fmt.Sprintf("%s (%d bytes) is larger than --max-file-size and was neither scanned nor searched; it may declare %s", file.path, file.size, typeName)
+			}
+			step("excludedFile", false, "excludedFile", message, &location)
+		}
+	}
+
+	for _, key := range typeKeys {
+		pkg, receiver := splitTypeKey(key)
+		methods := scan.TypeMethods[key]
+		if info, ok := scan.Types[pkg+":"+typeName]; ok {
+			location := info.Location
+			step("type", true, "", fmt.Sprintf("%s declared in package %s (%s), kind %s", receiver, info.PackageName, pkg, info.Kind), &location)
+		} else {
+			step("type", true, "", fmt.Sprintf("%s has methods in %s but its declaration was not scanned", receiver, pkg), nil)
+		}
+
+		names := make([]string, 0, len(methods))
+		files := make(map[string]bool)
+		for name, method := range methods {
+			names = append(names, name)
+			if method.Location.File != "" {
+				files[method.Location.File] = true
+			}
+		}
+		sort.Strings(names)
+		fileList := make([]string, 0, len(files))
+		for file := range files {
+			fileList = append(fileList, file)
+		}
+		sort.Strings(fileList)
+		if len(fileList) == 0 {
+			step("files", true, "", fmt.Sprintf("%s declares no methods", receiver), nil)
+		} else {
+			step("files", true, "", fmt.Sprintf("method set of %s comes from %d file(s): %s", receiver, len(fileList), strings.Join(fileList, ", ")), nil)
+		}
+
+		for _, name := range names {
+			method := methods[name]
+			message := fmt.Sprintf("%s has %s with receiver %s", receiver, method.Signature(), method.ReceiverType)
+			if method.PromotedFrom != "" {
+				message += " (promoted from " + method.PromotedFrom + ")"
+			}
+			location := method.Location
+			step("method", true, "", message, &location)
+		}
+	}
+
+	// 2. 接口及逐个方法的比较
+	found := false
+	for i := range scan.Interfaces {
+		iface := &scan.Interfaces[i]
+		if iface.Name != interfaceName {
+			continue
+		}
+		found = true
+		location := iface.Location
+		step("interface", true, "", fmt.Sprintf("interface %s.%s (%s) requires %d method(s): %s", iface.PackageName, iface.Name, iface.Package, len(iface.Methods), strings.Join(iface.Methods, ", ")), &location)
+		for _, embed := range iface.Embeds {
+			embedLocation := embed.Location
+			name := embed.Name
+			if embed.ImportPath != "" {
+				name = embed.ImportPath + "." + embed.Name
+			}
+			if embedResolved(iface, embed) {
+				step("embed", true, "", fmt.Sprintf("embedded %s was expanded into the method set", name), &embedLocation)
+			} else {
+				step("embed", false, "unresolvedEmbed", fmt.Sprintf("embedded %s could not be resolved (standard library interfaces need --goroot); its methods are not required", name), &embedLocation)
+			}
+		}
+
+		for _, key := range typeKeys {
+			pkg, receiver := splitTypeKey(key)
+			verdict := ExplainVerdict{Interface: iface.PackageName + "." + iface.Name, ReceiverType: receiver, TypePackage: pkg}
+			fail := func(reason, message string, location *Location) {
+				step("compare", false, reason, message, location)
+				if verdict.Reason == "" {
+					verdict.Reason = reason
+				}
+			}
+
+			if !iface.visibleTo(pkg) {
+				step("visibility", false, "packageMismatch", fmt.Sprintf("%s cannot implement %s from package %s: the interface has unexported methods or belongs to an external test package", receiver, iface.Name, pkg), nil)
+				verdict.Reason = "packageMismatch"
+			}
+			if iface.conflicting {
+				fail("conflictingInterface", fmt.Sprintf("%s has conflicting method signatures and cannot be implemented", iface.Name), &location)
+			}
+
+			methods := scan.TypeMethods[key]
+			for _, spec := range iface.Specs {
+				specLocation := spec.Location
+				method, ok := methods[spec.Name]
+				switch {
+				case !ok && !strings.HasPrefix(receiver, "*") && scan.TypeMethods[pkg+":*"+receiver][spec.Name] != nil:
+					fail("pointerOnly", fmt.Sprintf("%s: declared with a pointer receiver, so only *%s has it", spec.Name, receiver), &specLocation)
+				case !ok:
+					fail("missingMethod", fmt.Sprintf("%s: %s has no method %s", spec.Name, receiver, spec.Signature), &specLocation)
+				case method.hasTypeParams():
+					fail("typeParams", fmt.Sprintf("%s: the method declares its own type parameters and cannot implement an interface method", spec.Name), &specLocation)
+				case spec.Key != "" && method.Key != "" && spec.Key != method.Key:
+					fail("signatureMismatch", fmt.Sprintf("%s: interface wants %s, %s has %s", spec.Name, spec.Signature, receiver, method.Signature()), &specLocation)
+				default:
+					step("compare", true, "", fmt.Sprintf("%s: %s matches", spec.Name, method.Signature()), &specLocation)
+				}
+			}
+
+			verdict.Satisfied = iface.visibleTo(pkg) && isExactMatch(methods, iface)
+			if verdict.Satisfied {
+				verdict.Reason = ""
+			}
+			result.Verdicts = append(result.Verdicts, verdict)
+		}
+	}
+	if !found {
+		step("interface", false, "interfaceNotFound", fmt.Sprintf("interface %s was not found in the scanned files", interfaceName), nil)
+	}
+
+	return result
+}
+
+// 嵌入的接口是否已展开：至少有一个方法规格来自它
+func embedResolved(iface *InterfaceInfo, embed EmbedRef) bool {
+	for _, spec := range iface.Specs {
+		if spec.embedded && spec.DeclaredIn == embed.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// 扫描时被排除、可能声明了该类型的文件
+type excludedFile struct {
+	path   string
+	reason string
+	size   int64
+}
+
+// 按扫描规则（walkSourceFiles）找出被排除的文件：测试文件中提到该类型声明或方法的文件；
+// 超过 --max-file-size 的文件只根据大小报告，不读取内容
+func excludedDeclarations(directory, typeName string) []excludedFile {
+	pattern := regexp.MustCompile(`\btype\s+` + regexp.QuoteMeta(typeName) + `\b|func\s*\([^)]*\b` + regexp.QuoteMeta(typeName) + `\b`)
+	var files []excludedFile
+	walkSourceFiles(directory, func(string) {}, func(path string, info os.FileInfo, reason string) {
+		if reason == excludedTooLarge {
+			files = append(files, excludedFile{path: path, reason: reason, size: info.Size()})
+			return
+		}
+		file, err := sourceFS.Open(path)
+		if err != nil {
+			return
+		}
+		defer file.Close()
+		if data, err := io.ReadAll(file); err == nil && pattern.Match(data) {
+			files = append(files, excludedFile{path: path, reason: reason, size: info.Size()})
+		}
+	})
+	return files
+}
+
+// --human 时的文本输出
+func (r ExplainResult) Human() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "explain %s against %s\n", r.Type, r.Interface)
+	for _, s := range r.Steps {
+		mark := "ok  "
+		if !s.OK {
+			mark = "FAIL"
+		}
+		fmt.Fprintf(&b, "  [%s] %-10s %s", mark, s.Stage, s.Message)
+		if s.Location != nil && s.Location.File != "" {
+			line := s.Location.Line
+			if !s.Location.oneBased {
+				line++
+			}
+			fmt.Fprintf(&b, " (%s:%d)", s.Location.File, line)
+		}
+		b.WriteString("\n")
+	}
+	for _, v := range r.Verdicts {
+		if v.Satisfied {
+			fmt.Fprintf(&b, "%s (%s) satisfies %s\n", v.ReceiverType, v.TypePackage, v.Interface)
+		} else {
+			fmt.Fprintf(&b, "%s (%s) does not satisfy %s: %s\n", v.ReceiverType, v.TypePackage, v.Interface, v.Reason)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const explainFixture = `package p
+
+type Runner interface {
+	Run()
+	Stop(code int) error
+}
+
+type Full struct{}
+
+func (Full) Run()                {}
+func (Full) Stop(code int) error { return nil }
+
+type Ptr struct{}
+
+func (Ptr) Run()                 {}
+func (*Ptr) Stop(code int) error { return nil }
+
+type Wrong struct{}
+
+func (Wrong) Run()                   {}
+func (Wrong) Stop(code string) error { return nil }
+
+type Half struct{}
+
+func (Half) Run() {}
+`
+
+// 每个接收者类型的结论和失败原因
+func explainVerdicts(t *testing.T, args ...string) map[string]string {
+	t.Helper()
+	result := runArgs(t, append([]string{"explain"}, args...)...).(ExplainResult)
+	verdicts := make(map[string]string)
+	for _, v := range result.Verdicts {
+		if v.Satisfied {
+			verdicts[v.ReceiverType] = "satisfied"
+		} else {
+			verdicts[v.ReceiverType] = v.Reason
+		}
+	}
+	return verdicts
+}
+
+func TestExplainVerdictsAndReasons(t *testing.T) {
+	dir := writeTree(t, map[string]string{"p.go": explainFixture})
+
+	for typeName, want := range map[string]map[string]string{
+		"Full":  {"Full": "satisfied", "*Full": "satisfied"},
+		"Ptr":   {"Ptr": "pointerOnly", "*Ptr": "satisfied"},
+		"Wrong": {"Wrong": "signatureMismatch", "*Wrong": "signatureMismatch"},
+		"Half":  {"Half": "missingMethod", "*Half": "missingMethod"},
+	} {
+		if got := explainVerdicts(t, dir, typeName, "Runner"); !reflect.DeepEqual(got, want) {
+			t.Errorf("explain %s Runner: got %v, want %v", typeName, got, want)
+		}
+	}
+}
+
+// 类型只在被排除的测试文件中声明时，报告该文件
+func TestExplainReportsExcludedTestFiles(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"p.go":      explainFixture,
+		"h_test.go": "package p\n\ntype Hidden struct{}\n\nfunc (Hidden) Run() {}\n",
+	})
+
+	result := runArgs(t, "explain", dir, "Hidden", "Runner").(ExplainResult)
+	var excluded []string
+	for _, step := range result.Steps {
+		if step.Stage == "type" && step.Reason != "typeNotFound" {
+			t.Fatalf("unexpected type step %+v", step)
+		}
+		if step.Stage == "excludedFile" {
+			excluded = append(excluded, step.Location.File)
+		}
+	}
+	if want := []string{filepath.Join(dir, "h_test.go")}; !reflect.DeepEqual(excluded, want) {
+		t.Fatalf("excluded files %v, want %v", excluded, want)
+	}
+
+	if got, want := explainVerdicts(t, dir, "Hidden", "Runner", "--include-tests"), map[string]string{"Hidden": "missingMethod", "*Hidden": "missingMethod"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("with --include-tests: got %v, want %v", got, want)
+	}
+}
+
+// 找不到类型时列出被排除的文件：遵守扫描的跳过规则，过大的文件不读取
+func TestExplainExcludedFilesFollowScanRules(t *testing.T) {
+	hidden := "package p\n\ntype Hidden struct{}\n\nfunc (Hidden) Run() {}\n"
+	dir := writeTree(t, map[string]string{
+		"a.go":                  serveFixtureInterface,
+		"gen.go":                hidden,
+		"hidden_test.go":        hidden,
+		"other_test.go":         "package p\n",
+		"vendor/x/x_test.go":    hidden,
+		".cache/c_test.go":      hidden,
+		"sub/deep/deep_test.go": hidden,
+		"sub/shallow_test.go":   hidden,
+	})
+	recorder := useRecordingFS(t, map[string]int64{"gen.go": 3 << 30})
+
+	result := runArgs(t, "explain", dir, "Hidden", "Runner", "--max-depth", "1").(ExplainResult)
+	got := make(map[string]string)
+	for _, step := range result.Steps {
+		if step.Stage == "excludedFile" {
+			rel, _ := filepath.Rel(dir, step.Location.File)
+			got[filepath.ToSlash(rel)] = step.Message
+		}
+	}
+	var files []string
+	for file := range got {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	if want := []string{"gen.go", "hidden_test.go", "sub/shallow_test.go"}; !reflect.DeepEqual(files, want) {
+		t.Fatalf("excluded files %v, want %v", files, want)
+	}
+	if !strings.Contains(got["gen.go"], "3221225472 bytes") || !strings.Contains(got["gen.go"], "--max-file-size") {
+		t.Errorf("gen.go: %s", got["gen.go"])
+	}
+	if !strings.Contains(got["hidden_test.go"], "--include-tests") {
+		t.Errorf("hidden_test.go: %s", got["hidden_test.go"])
+	}
+	if recorder.opened["gen.go"] > 0 {
+		t.Fatal("gen.go was read although it exceeds --max-file-size")
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import "path/filepath"
+
+// 在目录下没有找到任何实现时扩大的查找范围：目录所在模块的根目录。
+// 目录本身就是模块根、不在模块中或指定了 --no-fallback 时返回空字符串
+func fallbackScope(directory string) string {
+	if options.Has("no-fallback") {
+		return ""
+	}
+	abs, err := filepath.Abs(directory)
+	if err != nil {
+		return ""
+	}
+	root := findModuleRoot(abs)
+	if root == "" || root == abs {
+		return ""
+	}
+	return root
+}
+
+// find-implementations：先在目录下查找，没有结果时从模块根目录重试一次（仍受 --exclude 等扫描规则约束）。
+// --package-prefix 始终相对于用户给出的目录
+func findImplementationsWithFallback(directory, methodName string) AnalysisResult {
+	result := findImplementationsResult(directory, directory, methodName)
+	if len(result.Implementations) > 0 {
+		return result
+	}
+	if root := fallbackScope(directory); root != "" {
+		result = findImplementationsResult(root, directory, methodName)
+		result.Scope = "module"
+	}
+	return result
+}
+
+func findImplementationsResult(searchDir, prefixBase, methodName string) AnalysisResult {
+	implementations := findImplementations(searchDir, methodName)
+	implementations = filterByPackagePrefix(implementations, prefixBase)
+	implementations = rankResults(implementations,
+		func(impl Implementation) Location { return impl.Location },
+		func(impl Implementation) string { return impl.ReceiverType + "." + impl.MethodName })
+	result := AnalysisResult{Implementations: implementations}
+	if options.Has("explain") {
+		result.NearMisses = findNearMisses(searchDir, methodName)
+	}
+	if options.Has("group-by-type") {
+		result.groupByType()
+	}
+	return result
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// 接口和实现位于同一模块中的兄弟包
+func siblingPackagesModule(t *testing.T) string {
+	return writeTree(t, map[string]string{
+		"go.mod": "module example.com/m\n",
+		"api/api.go": `package api
+
+type Runner interface{ Run() }
+`,
+		"worker/worker.go": `package worker
+
+type Worker struct{}
+
+func (*Worker) Run() {}
+`,
+		"empty/empty.go": `package empty
+
+type Stopper interface{ Stop() }
+`,
+		"local/local.go": `package local
+
+type Closer interface{ Close() }
+
+type File struct{}
+
+func (File) Close() {}
+`,
+	})
+}
+
+func TestFindImplementationsFallsBackToModuleRoot(t *testing.T) {
+	dir := siblingPackagesModule(t)
+	result := runArgs(t, "find-implementations", filepath.Join(dir, "api"), "Run").(AnalysisResult)
+	if got, want := receiverTypes(result.Implementations), []string{"*Worker"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got implementations %v, want %v", got, want)
+	}
+	if result.Scope != "module" {
+		t.Fatalf("scope = %q, want module", result.Scope)
+	}
+}
+
+func TestFindImplementationsNoFallback(t *testing.T) {
+	dir := siblingPackagesModule(t)
+	result := runArgs(t, "find-implementations", filepath.Join(dir, "api"), "Run", "--no-fallback").(AnalysisResult)
+	if len(result.Implementations) != 0 || result.Scope != "" {
+		t.Fatalf("got %v with scope %q, want no implementations and no scope", receiverTypes(result.Implementations), result.Scope)
+	}
+}
+
+func TestFindImplementationsFallbackFindsNothing(t *testing.T) {
+	dir := siblingPackagesModule(t)
+	result := runArgs(t, "find-implementations", filepath.Join(dir, "empty"), "Stop").(AnalysisResult)
+	if len(result.Implementations) != 0 {
+		t.Fatalf("got implementations %v, want none", receiverTypes(result.Implementations))
+	}
+	// 已经在模块根目录重试过，扩大后的范围仍然标记出来
+	if result.Scope != "module" {
+		t.Fatalf("scope = %q, want module", result.Scope)
+	}
+}
+
+func TestFindImplementationsLocalResultsDoNotFallBack(t *testing.T) {
+	dir := siblingPackagesModule(t)
+	result := runArgs(t, "find-implementations", filepath.Join(dir, "local"), "Close").(AnalysisResult)
+	if got, want := receiverTypes(result.Implementations), []string{"File"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got implementations %v, want %v", got, want)
+	}
+	if result.Scope != "" {
+		t.Fatalf("scope = %q, want the local directory only", result.Scope)
+	}
+}
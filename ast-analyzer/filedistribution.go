@@ -0,0 +1,79 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// 接口的实现类型与接口的相对位置：同一文件、同一目录或其他目录
+type FileDistribution struct {
+	InterfaceName string   `json:"interfaceName"`
+	Package       string   `json:"package"`
+	Location      Location `json:"location"`
+	SameFile      int      `json:"sameFile"`
+	SameDir       int      `json:"sameDir"`
+	DifferentDir  int      `json:"differentDir"`
+	Total         int      `json:"total"`
+}
+
+type FileDistributionResult struct {
+	Distributions []FileDistribution `json:"distributions"`
+}
+
+// 统计每个接口的实现类型所在的位置。实现类型按类型定义所在的文件计算（没有扫描到定义时取第一个实现方法的文件），
+// T 和 *T 只算一个类型；同一目录不包含同一文件
+func findFileDistribution(directory string) []FileDistribution {
+	results := []FileDistribution{}
+
+	scan := scanDirectory(directory)
+	byInterface := make(map[*InterfaceInfo]*FileDistribution)
+	var order []*InterfaceInfo
+	counted := make(map[*InterfaceInfo]map[string]bool)
+
+	for _, match := range matchImplementations(scan) {
+		pkg, receiver := splitTypeKey(match.TypeKey)
+		baseKey := pkg + ":" + strings.TrimPrefix(receiver, "*")
+		if counted[match.Interface][baseKey] {
+			continue
+		}
+
+		file := ""
+		if info, ok := scan.Types[baseKey]; ok {
+			file = info.Location.File
+		} else if methods := match.ImplementedMethods(); len(methods) > 0 {
+			file = methods[0].Location.File
+		}
+		if file == "" {
+			continue
+		}
+
+		distribution, ok := byInterface[match.Interface]
+		if !ok {
+			distribution = &FileDistribution{
+				InterfaceName: match.Interface.Name,
+				Package:       match.Interface.Package,
+				Location:      match.Interface.Location,
+			}
+			byInterface[match.Interface] = distribution
+			counted[match.Interface] = make(map[string]bool)
+			order = append(order, match.Interface)
+		}
+		counted[match.Interface][baseKey] = true
+
+		ifaceFile := match.Interface.Location.File
+		switch {
+		case file == ifaceFile:
+			distribution.SameFile++
+		case filepath.Dir(file) == filepath.Dir(ifaceFile):
+			distribution.SameDir++
+		default:
+			distribution.DifferentDir++
+		}
+		distribution.Total++
+	}
+
+	for _, iface := range order {
+		results = append(results, *byInterface[iface])
+	}
+	return results
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestImplementationFileDistribution(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"go.mod":         "module ex\n\ngo 1.21\n",
+		"iface/iface.go": "package iface\n\ntype Runner interface{ Run() }\n\ntype Local struct{}\n\nfunc (Local) Run() {}\n",
+		"iface/other.go": "package iface\n\ntype Sibling struct{}\n\nfunc (*Sibling) Run() {}\n",
+		"impl/impl.go":   "package impl\n\ntype Remote struct{}\n\nfunc (Remote) Run() {}\n",
+		// Hidden 的定义在未扫描的测试文件中，按实现方法所在的文件计算
+		"impl/hidden.go": "package impl\n\nfunc (Hidden) Run() {}\n",
+		"impl/h_test.go": "package impl\n\ntype Hidden struct{}\n",
+	})
+
+	distributions := runArgs(t, "find-interface-implementation-file-distribution", dir).(FileDistributionResult).Distributions
+	if len(distributions) != 1 {
+		t.Fatalf("got %+v, want one distribution for Runner", distributions)
+	}
+	got := distributions[0]
+	if got.InterfaceName != "Runner" || got.Location.File != filepath.Join(dir, "iface", "iface.go") {
+		t.Fatalf("got %+v, want Runner in iface/iface.go", got)
+	}
+	// T 和 *T 都满足接口的 Local、Remote 只算一次
+	if got.SameFile != 1 || got.SameDir != 1 || got.DifferentDir != 2 || got.Total != 4 {
+		t.Fatalf("got sameFile %d, sameDir %d, differentDir %d, total %d; want 1, 1, 2, 4", got.SameFile, got.SameDir, got.DifferentDir, got.Total)
+	}
+}
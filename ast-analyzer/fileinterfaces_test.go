@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// 接口声明与其他声明交错，分组声明中的接口、同一行上的两个接口、嵌入接口都有
+const interleavedInterfaces = `package p
+
+type Zed interface {
+	Second()
+	First()
+}
+
+type helper struct{}
+
+func (helper) Second() {}
+
+type (
+	Alpha interface{ Run() }
+	config struct{}
+	Beta  interface {
+		Stop()
+		Alpha
+		Start()
+	}
+)
+
+var _ = helper{}
+
+type Left interface{ L() }; type Right interface{ R() }
+`
+
+func TestFindFileInterfacesOrderAndOrdinals(t *testing.T) {
+	dir := writeTree(t, map[string]string{"a.go": interleavedInterfaces})
+	file := filepath.Join(dir, "a.go")
+
+	want := []string{
+		"Zed.Second 0/0",
+		"Zed.First 0/1",
+		"Alpha.Run 1/0",
+		"Beta.Stop 2/0",
+		"Beta.Start 2/1",
+		"Left.L 3/0",
+		"Right.R 4/0",
+	}
+	// 多次运行的结果必须完全相同
+	for run := 0; run < 10; run++ {
+		result := runArgs(t, "find-file-interfaces", file).(AnalysisResult)
+		var got []string
+		for _, method := range result.Interfaces {
+			got = append(got, fmt.Sprintf("%s.%s %d/%d", method.InterfaceName, method.Name, *method.InterfaceOrdinal, *method.Ordinal))
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("run %d: got\n%v\nwant\n%v", run, got, want)
+		}
+	}
+}
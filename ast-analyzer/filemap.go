@@ -0,0 +1,122 @@
+package main
+
+import (
+	"path/filepath"
+)
+
+// 文件中某个具体方法实现的接口方法
+type FileMapImplementation struct {
+	InterfaceName string `json:"interfaceName"`
+	MethodName    string `json:"methodName"`
+	// 接口方法的位置，通过嵌入获得的方法为接口名的位置
+	InterfaceLocation Location `json:"interfaceLocation"`
+}
+
+// 文件中某个接口方法的实现情况
+type FileMapInterfaceMethod struct {
+	InterfaceName        string `json:"interfaceName"`
+	MethodName           string `json:"methodName"`
+	ImplementationsCount int    `json:"implementationsCount"`
+	// 没有实现时省略
+	FirstImplementationLocation *Location `json:"firstImplementationLocation,omitempty"`
+}
+
+// 以行号（从 0 开始，与编辑器一致）为键的整文件映射，供插件一次性绘制装饰
+type FileMapResult struct {
+	MethodsImplementing map[int][]FileMapImplementation  `json:"methodsImplementing"`
+	InterfaceMethods    map[int][]FileMapInterfaceMethod `json:"interfaceMethods"`
+}
+
+// 文件相关命令的扫描范围：--root、文件所在模块或文件所在目录
+func fileScanRoot(filePath string) string {
+	root := options.String("root", "")
+	if root == "" {
+		root = findModuleRoot(filepath.Dir(filePath))
+	}
+	if root == "" {
+		root = filepath.Dir(filePath)
+	}
+	return root
+}
+
+// 计算文件中方法与接口方法的双向映射。只使用目录扫描结果（serve 模式下复用缓存），不再单独解析文件
+func buildFileMap(filePath string) FileMapResult {
+	result := FileMapResult{
+		MethodsImplementing: make(map[int][]FileMapImplementation),
+		InterfaceMethods:    make(map[int][]FileMapInterfaceMethod),
+	}
+	absFile, _ := filepath.Abs(filePath)
+	inFile := func(file string) bool {
+		abs, _ := filepath.Abs(file)
+		return abs == absFile
+	}
+
+	scan := scanDirectory(fileScanRoot(filePath))
+	matches := matchImplementations(scan)
+
+	// 文件中的方法实现了哪些接口方法
+	seen := make(map[*MethodInfo]map[string]bool)
+	for _, match := range matches {
+		iface := match.Interface
+		for _, method := range match.ImplementedMethods() {
+			if method.FuncDecl == nil || !inFile(method.Location.File) {
+				continue
+			}
+			key := iface.Package + ":" + iface.Name
+			if seen[method] == nil {
+				seen[method] = make(map[string]bool)
+			}
+			if seen[method][key] {
+				continue
+			}
+			seen[method][key] = true
+
+			spec := iface.spec(method.Name)
+			location := spec.Location
+			if spec.embedded {
+				location = iface.Location
+			}
+			line := method.Location.Line - 1
+			result.MethodsImplementing[line] = append(result.MethodsImplementing[line], FileMapImplementation{
+				InterfaceName:     iface.Name,
+				MethodName:        method.Name,
+				InterfaceLocation: location,
+			})
+		}
+	}
+
+	// 文件中的接口方法各有多少实现
+	for i := range scan.Interfaces {
+		iface := &scan.Interfaces[i]
+		if !inFile(iface.Location.File) {
+			continue
+		}
+		for _, spec := range iface.Specs {
+			if spec.embedded {
+				continue
+			}
+			entry := FileMapInterfaceMethod{InterfaceName: iface.Name, MethodName: spec.Name}
+			// T 和 *T 的方法集共用值接收者方法，同一个实现只计一次
+			counted := make(map[Location]bool)
+			for _, match := range matches {
+				if match.Interface != iface {
+					continue
+				}
+				method, ok := match.Methods[spec.Name]
+				if !ok || counted[method.Location] {
+					continue
+				}
+				counted[method.Location] = true
+				entry.ImplementationsCount++
+				if entry.FirstImplementationLocation == nil {
+					location := method.Location
+					entry.FirstImplementationLocation = &location
+				}
+			}
+			line := spec.Location.Line
+			result.InterfaceMethods[line] = append(result.InterfaceMethods[line], entry)
+		}
+	}
+
+	return result
+}
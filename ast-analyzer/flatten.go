@@ -0,0 +1,184 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// 接口解析器：展开嵌入的接口，把被嵌入接口的方法合并进来
+type interfaceResolver struct {
+	interfaces []*InterfaceInfo
+	byPackage  map[string]map[string]*InterfaceInfo // 包目录 -> 接口名 -> 接口
+	// 已加载的标准库包（导入路径 -> 包目录）
+	stdlibPackages map[string]string
+	flattened      map[*InterfaceInfo]bool
+	visiting       map[*InterfaceInfo]bool
+	// 正在展开的接口链，用于报告循环嵌入
+	stack []*InterfaceInfo
+}
+
+func newInterfaceResolver() *interfaceResolver {
+	return &interfaceResolver{
+		byPackage:      make(map[string]map[string]*InterfaceInfo),
+		stdlibPackages: make(map[string]string),
+		flattened:      make(map[*InterfaceInfo]bool),
+		visiting:       make(map[*InterfaceInfo]bool),
+	}
+}
+
+func (r *interfaceResolver) add(iface *InterfaceInfo) {
+	r.interfaces = append(r.interfaces, iface)
+	if r.byPackage[iface.Package] == nil {
+		r.byPackage[iface.Package] = make(map[string]*InterfaceInfo)
+	}
+	if _, exists := r.byPackage[iface.Package][iface.Name]; !exists {
+		r.byPackage[iface.Package][iface.Name] = iface
+	}
+}
+
+// 展开所有接口的嵌入接口（原地修改）
+func flattenInterfaces(interfaces []InterfaceInfo) []InterfaceInfo {
+	r := newInterfaceResolver()
+	for i := range interfaces {
+		r.add(&interfaces[i])
+	}
+	for i := range interfaces {
+		r.flatten(&interfaces[i])
+	}
+	return interfaces
+}
+
+func (r *interfaceResolver) flatten(iface *InterfaceInfo) {
+	if r.flattened[iface] || r.visiting[iface] {
+		return
+	}
+	r.visiting[iface] = true
+	r.stack = append(r.stack, iface)
+
+	for _, embed := range iface.Embeds {
+		target := r.lookup(iface, embed)
+		if target == nil {
+			continue
+		}
+		if r.visiting[target] {
+			// 循环嵌入（编辑过程中可能出现）：不再展开，只报告
+			r.reportCycle(target)
+			continue
+		}
+		r.flatten(target)
+		// 通过多条嵌入路径得到的同一个方法（菱形嵌入）只保留一份；同名但签名不同是编译错误，报告出来
+		for _, spec := range target.Specs {
+			if existing := iface.spec(spec.Name); existing != nil {
+				if existing.Key != spec.Key {
+					r.reportConflict(iface, *existing, spec)
+				}
+				continue
+			}
+			spec.embedded = true
+			iface.addMethod(spec)
+		}
+	}
+
+	r.stack = r.stack[:len(r.stack)-1]
+	delete(r.visiting, iface)
+	r.flattened[iface] = true
+}
+
+// 报告接口中来自不同声明、同名但签名不同的方法
+func (r *interfaceResolver) reportConflict(iface *InterfaceInfo, existing, spec MethodSpec) {
+	iface.conflicting = true
+	reportDiagnostic(Diagnostic{
+		Kind: "conflictingMethod",
+		Message: "interface " + iface.PackageName + "." + iface.Name + ": duplicate method " + spec.Name +
+			" with different signatures: " + existing.Signature + " (" + existing.DeclaredIn + ") and " +
+			spec.Signature + " (" + spec.DeclaredIn + ")",
+		Interfaces: []string{iface.PackageName + "." + iface.Name},
+		Location:   iface.Location,
+	})
+}
+
+// 报告从 target 开始、回到 target 的嵌入环
+func (r *interfaceResolver) reportCycle(target *InterfaceInfo) {
+	start := len(r.stack) - 1
+	for start > 0 && r.stack[start] != target {
+		start--
+	}
+
+	// 从名称最小的接口开始列出，同一个环无论从哪里发现都得到相同的描述
+	cycle := r.stack[start:]
+	first := 0
+	for i, iface := range cycle {
+		if iface.PackageName+"."+iface.Name < cycle[first].PackageName+"."+cycle[first].Name {
+			first = i
+		}
+	}
+	var names []string
+	for i := range cycle {
+		iface := cycle[(first+i)%len(cycle)]
+		names = append(names, iface.PackageName+"."+iface.Name)
+	}
+	names = append(names, names[0])
+
+	reportDiagnostic(Diagnostic{
+		Kind:       "cyclicEmbedding",
+		Message:    "interface embedding cycle: " + strings.Join(names, " -> "),
+		Interfaces: names,
+		Location:   cycle[first].Location,
+	})
+}
+
+// 查找嵌入项对应的接口
+func (r *interfaceResolver) lookup(from *InterfaceInfo, embed EmbedRef) *InterfaceInfo {
+	if embed.ImportPath == "" {
+		if target := r.byPackage[from.Package][embed.Name]; target != nil {
+			return target
+		}
+		if embed.Name == "error" {
+			return errorInterface
+		}
+		return nil
+	}
+
+	if isStdlibPath(embed.ImportPath) {
+		return r.stdlibInterface(embed.ImportPath, embed.Name)
+	}
+
+	// 工作区中的其他包：按包名和目录名匹配
+	name := importPackageName(embed.ImportPath)
+	for _, iface := range r.interfaces {
+		if iface.Name == embed.Name && iface.PackageName == name && filepath.Base(iface.Package) == name {
+			return iface
+		}
+	}
+	for _, iface := range r.interfaces {
+		if iface.Name == embed.Name && iface.PackageName == name {
+			return iface
+		}
+	}
+	return nil
+}
+
+// 查找标准库接口：已加载的标准库包直接使用；--goroot 模式下解析源码，
+// GOROOT 不可用时回退到内置注册表；--stdlib-embeddings 模式下只使用内置注册表
+func (r *interfaceResolver) stdlibInterface(importPath, name string) *InterfaceInfo {
+	dir, loaded := r.stdlibPackages[importPath]
+	if !loaded && !options.Has("goroot") {
+		if options.Has("stdlib-embeddings") {
+			return registryInterface(importPath, name)
+		}
+		return nil
+	}
+	if !loaded {
+		interfaces := parseStdlibPackage(importPath)
+		for i := range interfaces {
+			r.add(&interfaces[i])
+			dir = interfaces[i].Package
+		}
+		r.stdlibPackages[importPath] = dir
+	}
+
+	if target := r.byPackage[dir][name]; dir != "" && target != nil {
+		return target
+	}
+	return registryInterface(importPath, name)
+}
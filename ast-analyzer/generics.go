@@ -0,0 +1,225 @@
+package main
+
+import (
+	"go/ast"
+	"go/types"
+	"regexp"
+	"sort"
+)
+
+// 泛型接口的一个实现：类型（可以是泛型类型）以特定类型实参实现泛型接口
+type GenericImplementation struct {
+	GenericType string `json:"genericType"`
+	// 类型自身的类型参数，非泛型类型为空
+	TypeArgs         []string `json:"typeArgs"`
+	GenericInterface string   `json:"genericInterface"`
+	// 接口的类型实参，方法签名中没有约束到的参数保留参数名
+	InterfaceArgs []string `json:"interfaceArgs"`
+	Location      Location `json:"location"`
+}
+
+type GenericImplementationResult struct {
+	Implementations []GenericImplementation `json:"implementations"`
+}
+
+var identPattern = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+
+// 推导接口类型参数的实参：接口方法签名与实现方法签名逐项对应
+type typeUnifier struct {
+	params   map[string]bool   // 接口的类型参数
+	rename   map[string]string // 实现方法接收者中的参数名 -> 类型声明中的参数名
+	bindings map[string]string
+}
+
+// 渲染实现一侧的类型，并把接收者中的类型参数名换成类型声明中的名字
+func (u *typeUnifier) render(expr ast.Expr) string {
+	text := types.ExprString(expr)
+	if len(u.rename) == 0 {
+		return text
+	}
+	var result []byte
+	last := 0
+	for _, loc := range identPattern.FindAllStringIndex(text, -1) {
+		name := text[loc[0]:loc[1]]
+		renamed, ok := u.rename[name]
+		if !ok || (loc[0] > 0 && text[loc[0]-1] == '.') {
+			continue
+		}
+		result = append(result, text[last:loc[0]]...)
+		result = append(result, renamed...)
+		last = loc[1]
+	}
+	return string(append(result, text[last:]...))
+}
+
+func (u *typeUnifier) unify(want, got ast.Expr) bool {
+	if ident, ok := want.(*ast.Ident); ok && u.params[ident.Name] {
+		actual := u.render(got)
+		if bound, ok := u.bindings[ident.Name]; ok {
+			return bound == actual
+		}
+		u.bindings[ident.Name] = actual
+		return true
+	}
+
+	switch w := want.(type) {
+	case *ast.StarExpr:
+		g, ok := got.(*ast.StarExpr)
+		return ok && u.unify(w.X, g.X)
+	case *ast.ArrayType:
+		g, ok := got.(*ast.ArrayType)
+		return ok && (w.Len == nil) == (g.Len == nil) && u.unify(w.Elt, g.Elt)
+	case *ast.MapType:
+		g, ok := got.(*ast.MapType)
+		return ok && u.unify(w.Key, g.Key) && u.unify(w.Value, g.Value)
+	case *ast.ChanType:
+		g, ok := got.(*ast.ChanType)
+		return ok && w.Dir == g.Dir && u.unify(w.Value, g.Value)
+	case *ast.Ellipsis:
+		g, ok := got.(*ast.Ellipsis)
+		return ok && u.unify(w.Elt, g.Elt)
+	case *ast.FuncType:
+		g, ok := got.(*ast.FuncType)
+		return ok && u.unifyFunc(w, g)
+	case *ast.IndexExpr:
+		g, ok := got.(*ast.IndexExpr)
+		return ok && u.unify(w.X, g.X) && u.unify(w.Index, g.Index)
+	case *ast.IndexListExpr:
+		g, ok := got.(*ast.IndexListExpr)
+		if !ok || len(w.Indices) != len(g.Indices) || !u.unify(w.X, g.X) {
+			return false
+		}
+		for i := range w.Indices {
+			if !u.unify(w.Indices[i], g.Indices[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return types.ExprString(want) == u.render(got)
+}
+
+// 按位置展开参数列表，(a, b int) 展开为两项
+func expandFields(list *ast.FieldList) []ast.Expr {
+	var exprs []ast.Expr
+	if list == nil {
+		return exprs
+	}
+	for _, field := range list.List {
+		count := max(len(field.Names), 1)
+		for i := 0; i < count; i++ {
+			exprs = append(exprs, field.Type)
+		}
+	}
+	return exprs
+}
+
+func (u *typeUnifier) unifyFunc(want, got *ast.FuncType) bool {
+	for _, pair := range [][2]*ast.FieldList{{want.Params, got.Params}, {want.Results, got.Results}} {
+		wantTypes, gotTypes := expandFields(pair[0]), expandFields(pair[1])
+		if len(wantTypes) != len(gotTypes) {
+			return false
+		}
+		for i := range wantTypes {
+			if !u.unify(wantTypes[i], gotTypes[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// 实现方法接收者中的类型参数名到类型声明参数名的映射
+func receiverRename(method *MethodInfo, info *TypeInfo) map[string]string {
+	rename := make(map[string]string)
+	params := receiverTypeParams(method.FuncDecl.Recv)
+	if method.PromotedFrom != "" || params == nil {
+		return rename
+	}
+	for i, field := range params.List {
+		if i < len(info.TypeParams) && field.Names[0].Name != info.TypeParams[i].Name {
+			rename[field.Names[0].Name] = info.TypeParams[i].Name
+		}
+	}
+	return rename
+}
+
+// 尝试推导类型的方法集实现泛型接口所需的接口类型实参
+func inferInterfaceArgs(iface *InterfaceInfo, info *TypeInfo, methods map[string]*MethodInfo) ([]string, bool) {
+	u := &typeUnifier{params: make(map[string]bool), bindings: make(map[string]string)}
+	for _, param := range iface.TypeParams {
+		u.params[param.Name] = true
+	}
+
+	for _, spec := range iface.Specs {
+		method, ok := methods[spec.Name]
+		if !ok || method.FuncDecl == nil || spec.Func == nil {
+			return nil, false
+		}
+		u.rename = receiverRename(method, info)
+		if !u.unifyFunc(spec.Func, method.FuncDecl.Type) {
+			return nil, false
+		}
+	}
+
+	args := make([]string, len(iface.TypeParams))
+	for i, param := range iface.TypeParams {
+		args[i] = param.Name
+		if bound, ok := u.bindings[param.Name]; ok {
+			args[i] = bound
+		}
+	}
+	return args, true
+}
+
+// 查找实现泛型接口的类型及对应的类型实参
+func findGenericImplementations(directory string) []GenericImplementation {
+	results := []GenericImplementation{}
+	scan := scanDirectory(directory)
+
+	typeKeys := make([]string, 0, len(scan.Types))
+	for key, info := range scan.Types {
+		if info.Kind != "interface" && info.Kind != "alias" {
+			typeKeys = append(typeKeys, key)
+		}
+	}
+	sort.Strings(typeKeys)
+
+	for i := range scan.Interfaces {
+		iface := &scan.Interfaces[i]
+		if len(iface.TypeParams) == 0 || len(iface.Specs) == 0 {
+			continue
+		}
+		for _, key := range typeKeys {
+			info := scan.Types[key]
+			if !iface.visibleTo(info.Package) {
+				continue
+			}
+			// 值类型实现时只报告值类型
+			for _, pointer := range []bool{false, true} {
+				typeName := info.Name
+				if pointer {
+					typeName = "*" + info.Name
+				}
+				args, ok := inferInterfaceArgs(iface, info, scan.TypeMethods[info.Package+":"+typeName])
+				if !ok {
+					continue
+				}
+				typeArgs := []string{}
+				for _, param := range info.TypeParams {
+					typeArgs = append(typeArgs, param.Name)
+				}
+				results = append(results, GenericImplementation{
+					GenericType:      typeName,
+					TypeArgs:         typeArgs,
+					GenericInterface: iface.Name,
+					InterfaceArgs:    args,
+					Location:         info.Location,
+				})
+				break
+			}
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// --env KEY=VAL 指定的环境变量覆盖（可重复），格式不对的项记为诊断并忽略
+func envOverrides() []string {
+	var env []string
+	for _, value := range options.Values("env") {
+		if eq := strings.Index(value, "="); eq <= 0 {
+			reportDiagnostic(Diagnostic{Kind: "toolchain", Message: "invalid --env value (want KEY=VAL): " + value})
+			continue
+		}
+		env = append(env, value)
+	}
+	return env
+}
+
+// 读取环境变量，--env 的覆盖优先
+func goEnv(key string) string {
+	overrides := envOverrides()
+	for i := len(overrides) - 1; i >= 0; i-- {
+		if name, value, _ := strings.Cut(overrides[i], "="); name == key {
+			return value
+		}
+	}
+	return os.Getenv(key)
+}
+
+// 在被分析的目录中执行 go 命令，继承用户的环境（GOFLAGS、GOPATH、GOPROXY、GONOSUMDB 等）并应用 --env 覆盖，
+// 使 go.mod、go.work 和 vendor 设置与项目构建时一致。失败时把 go 命令的错误输出记为诊断
+func runGoCommand(args ...string) (string, bool) {
+	cmd := exec.Command("go", args...)
+	cmd.Dir = scanStats.directory
+	// 同名变量以后出现的为准
+	cmd.Env = append(os.Environ(), envOverrides()...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		reportDiagnostic(Diagnostic{Kind: "toolchain", Message: "go " + strings.Join(args, " ") + ": " + message})
+		return "", false
+	}
+	return strings.TrimSpace(string(out)), true
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 在 testdata/vendored 模块中执行 go 命令：依赖只存在于 vendor 目录，且不允许联网下载
+func vendoredModule(t *testing.T, args ...string) {
+	t.Helper()
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain not available")
+	}
+	dir, err := filepath.Abs(filepath.Join("testdata", "vendored"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GOPROXY", "off")
+	t.Setenv("GOWORK", "off")
+	t.Setenv("GOTOOLCHAIN", "local")
+	t.Setenv("GOMODCACHE", t.TempDir())
+
+	savedOptions, savedDirectory := options, scanStats.directory
+	t.Cleanup(func() { options, scanStats.directory = savedOptions, savedDirectory })
+	options = parseOptions(append([]string{"find-implementations", dir, "Get"}, args...))
+	scanStats.directory = dir
+	resetDiagnostics()
+}
+
+func TestRunGoCommandHonorsGOFLAGSVendor(t *testing.T) {
+	vendoredModule(t)
+	t.Setenv("GOFLAGS", "-mod=vendor")
+
+	if flags, _ := runGoCommand("env", "GOFLAGS"); flags != "-mod=vendor" {
+		t.Fatalf("go env GOFLAGS = %q, want -mod=vendor", flags)
+	}
+	deps, ok := runGoCommand("list", "-deps", "-f", "{{.ImportPath}}", "./...")
+	if !ok {
+		t.Fatalf("go list failed: %v", diagnosticsOfKind("toolchain"))
+	}
+	if !strings.Contains(deps, "example.com/dep") {
+		t.Fatalf("go list did not resolve the vendored dependency:\n%s", deps)
+	}
+}
+
+func TestRunGoCommandEnvOverrideWins(t *testing.T) {
+	// 用户环境要求下载依赖，--env 改回 vendor 模式
+	vendoredModule(t, "--env", "GOFLAGS=-mod=vendor")
+	t.Setenv("GOFLAGS", "-mod=mod")
+
+	if _, ok := runGoCommand("list", "-deps", "./..."); !ok {
+		t.Fatalf("go list failed: %v", diagnosticsOfKind("toolchain"))
+	}
+}
+
+func TestRunGoCommandReportsToolchainDiagnostic(t *testing.T) {
+	vendoredModule(t)
+	t.Setenv("GOFLAGS", "-mod=mod")
+
+	if _, ok := runGoCommand("list", "-deps", "./..."); ok {
+		t.Fatal("go list succeeded without vendor mode and without a proxy")
+	}
+	found := diagnosticsOfKind("toolchain")
+	if len(found) != 1 || !strings.Contains(found[0].Message, "go list -deps ./...") || !strings.Contains(found[0].Message, "GOPROXY=off") {
+		t.Fatalf("got toolchain diagnostics %v", found)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 接口与实现类型的关系图中的节点
+type GraphNode struct {
+	// 接口为 包目录:接口名，实现类型为方法集的键（包目录:T 或 包目录:*T）
+	ID string `json:"id"`
+	// 以包名限定的名称，例如 store.Repository、*mysql.repo
+	Label string `json:"label"`
+	// interface 或 type
+	Kind     string   `json:"kind"`
+	Location Location `json:"location"`
+}
+
+// 实现类型（From）满足接口（To）
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type GraphResult struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// 构建接口与实现类型的关系图，只包含至少有一个实现的接口
+func analyzeGraph(directory string) GraphResult {
+	result := GraphResult{Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+	scan := scanDirectory(directory)
+	seen := make(map[string]bool)
+
+	for _, match := range matchImplementations(scan) {
+		iface := match.Interface
+		ifaceID := iface.Package + ":" + iface.Name
+		if !seen[ifaceID] {
+			seen[ifaceID] = true
+			result.Nodes = append(result.Nodes, GraphNode{
+				ID:       ifaceID,
+				Label:    iface.PackageName + "." + iface.Name,
+				Kind:     "interface",
+				Location: iface.Location,
+			})
+		}
+
+		if !seen[match.TypeKey] {
+			seen[match.TypeKey] = true
+			pkg, receiverType := splitTypeKey(match.TypeKey)
+			node := GraphNode{ID: match.TypeKey, Label: receiverType, Kind: "type"}
+			if info, ok := scan.Types[pkg+":"+strings.TrimPrefix(receiverType, "*")]; ok {
+				pointer := strings.TrimSuffix(receiverType, info.Name)
+				node.Label = pointer + info.PackageName + "." + info.Name
+				node.Location = info.Location
+			}
+			result.Nodes = append(result.Nodes, node)
+		}
+
+		result.Edges = append(result.Edges, GraphEdge{From: match.TypeKey, To: ifaceID})
+	}
+
+	return result
+}
+
+// 渲染为 Graphviz DOT：接口为椭圆，实现类型为方框，边由实现类型指向接口
+func (g GraphResult) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph interfaces {\n")
+	b.WriteString("\trankdir=LR;\n")
+	for _, node := range g.Nodes {
+		shape := "box"
+		if node.Kind == "interface" {
+			shape = "ellipse"
+		}
+		fmt.Fprintf(&b, "\t%s [label=%s, shape=%s];\n", strconv.Quote(node.ID), strconv.Quote(node.Label), shape)
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "\t%s -> %s;\n", strconv.Quote(edge.From), strconv.Quote(edge.To))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// --group-by-type：把平铺的实现列表按实现类型分组，键为 包目录:类型名（T 和 *T 的方法归为同一类型），
+// 组内保持原有顺序；分组后不再输出平铺列表
+func (result *AnalysisResult) groupByType() {
+	groups := make(map[string][]Implementation)
+	for _, impl := range result.Implementations {
+		key := filepath.Dir(impl.Location.File) + ":" + strings.TrimPrefix(impl.ReceiverType, "*")
+		groups[key] = append(groups[key], impl)
+	}
+	result.ImplementationsByType = groups
+	result.Implementations = nil
+}
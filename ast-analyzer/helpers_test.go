@@ -0,0 +1,85 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// 最简单的接口和实现，多个测试共用
+const serveFixtureInterface = "package p\n\ntype Runner interface{ Run() }\n\ntype A struct{}\n\nfunc (A) Run() {}\n"
+
+// 在临时目录中写入文件（路径使用 /），返回目录
+func writeTree(t testing.TB, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		writeFile(t, filepath.Join(dir, filepath.FromSlash(name)), content)
+	}
+	return dir
+}
+
+func writeFile(t testing.TB, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// 以命令行参数执行一条命令，与 main 中的流程相同（不含输出格式化）
+func runArgs(t testing.TB, args ...string) interface{} {
+	t.Helper()
+	saved := options
+	t.Cleanup(func() { options = saved })
+
+	options = parseOptions(args)
+	startMeta(options.Args[1])
+	result, err := runCommand(options.Args[0], options.Args[1])
+	if err != nil {
+		t.Fatalf("%v: %v", args, err)
+	}
+	return result
+}
+
+// 本次命令报告的某类诊断
+func diagnosticsOfKind(kind string) []Diagnostic {
+	var found []Diagnostic
+	for _, d := range diagnostics.list {
+		if d.Kind == kind {
+			found = append(found, d)
+		}
+	}
+	return found
+}
+
+func receiverTypes(implementations []Implementation) []string {
+	var types []string
+	for _, impl := range implementations {
+		types = append(types, impl.ReceiverType)
+	}
+	return types
+}
+
+// 执行 fn 期间写到标准错误的内容
+func captureStderr(t testing.TB, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = saved }()
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		output <- string(data)
+	}()
+	fn()
+	w.Close()
+	return <-output
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"go/ast"
+	"strconv"
+	"strings"
+)
+
+// 实现类型所在包与接口所在包的导入关系
+const (
+	relationSamePackage    = "same-package"
+	relationImportsIface   = "imports-interface-package"
+	relationSharedImporter = "shared-importer"
+	relationUnrelated      = "unrelated"
+)
+
+// 扫描范围内包之间的导入关系，只根据文件的 import 声明计算，不需要构建
+type importGraph struct {
+	// 包目录 -> 导入的、位于扫描范围内的包目录
+	imports map[string][]string
+	// 包目录 -> 直接导入它的包目录
+	importers map[string][]string
+}
+
+// 记录文件的导入路径，按包目录合并
+func recordImports(imports map[string]map[string]bool, pkg string, f *ast.File) {
+	if imports[pkg] == nil {
+		imports[pkg] = make(map[string]bool)
+	}
+	for _, spec := range f.Imports {
+		if path, err := strconv.Unquote(spec.Path.Value); err == nil {
+			imports[pkg][path] = true
+		}
+	}
+}
+
+func buildImportGraph(imports map[string]map[string]bool) *importGraph {
+	graph := &importGraph{
+		imports:   make(map[string][]string),
+		importers: make(map[string][]string),
+	}
+	// 导入路径 -> 包目录；外部测试包不能被导入
+	dirs := make(map[string]string)
+	for dir := range imports {
+		if strings.HasSuffix(dir, "_test") {
+			continue
+		}
+		if path := packageImportPath(dir); path != "" {
+			dirs[path] = dir
+		}
+	}
+	for dir, paths := range imports {
+		for path := range paths {
+			if target, ok := dirs[path]; ok && target != dir {
+				graph.imports[dir] = append(graph.imports[dir], target)
+				graph.importers[target] = append(graph.importers[target], dir)
+			}
+		}
+	}
+	return graph
+}
+
+// 从 start 沿 edges 可以到达的包，包括 start 自身
+func reachable(edges map[string][]string, start string) map[string]bool {
+	seen := map[string]bool{start: true}
+	queue := []string{start}
+	for len(queue) > 0 {
+		dir := queue[0]
+		queue = queue[1:]
+		for _, next := range edges[dir] {
+			if !seen[next] {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return seen
+}
+
+// 实现类型所在包相对接口所在包的关系：同一个包、（间接）导入接口所在的包、
+// 有其他包同时（间接）导入两者（例如在 main 中组装），或者互不相关。
+// 接口所在包不在模块中、无法确定导入路径时返回空字符串
+func (s *ScanResult) importRelation(ifacePkg, implPkg string) string {
+	if ifacePkg == implPkg {
+		return relationSamePackage
+	}
+	if packageImportPath(strings.TrimSuffix(ifacePkg, "_test")) == "" {
+		return ""
+	}
+	s.importGraphOnce.Do(func() {
+		s.importGraph = buildImportGraph(s.imports)
+	})
+	graph := s.importGraph
+	if reachable(graph.imports, implPkg)[ifacePkg] {
+		return relationImportsIface
+	}
+	implImporters := reachable(graph.importers, implPkg)
+	for dir := range reachable(graph.importers, ifacePkg) {
+		if implImporters[dir] {
+			return relationSharedImporter
+		}
+	}
+	return relationUnrelated
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestImportRelationOfImplementations(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"go.mod":                   "module ex\n\ngo 1.21\n",
+		"iface/iface.go":           "package iface\n\ntype Runner interface{ Run() }\n\ntype Local struct{}\n\nfunc (Local) Run() {}\n",
+		"direct/direct.go":         "package direct\n\nimport \"ex/iface\"\n\nvar _ iface.Runner = Direct{}\n\ntype Direct struct{}\n\nfunc (Direct) Run() {}\n",
+		"transitive/transitive.go": "package transitive\n\nimport \"ex/direct\"\n\nvar _ = direct.Direct{}\n\ntype Transitive struct{}\n\nfunc (Transitive) Run() {}\n",
+		"wired/wired.go":           "package wired\n\ntype Wired struct{}\n\nfunc (Wired) Run() {}\n",
+		"loose/loose.go":           "package loose\n\ntype Loose struct{}\n\nfunc (Loose) Run() {}\n",
+		"cmd/main.go":              "package main\n\nimport (\n\t\"ex/iface\"\n\t\"ex/wired\"\n)\n\nvar _ iface.Runner = wired.Wired{}\n\nfunc main() {}\n",
+	})
+
+	got := make(map[string]string)
+	for _, impl := range runArgs(t, "find-implementations", dir, "Run").(AnalysisResult).Implementations {
+		got[strings.TrimPrefix(impl.ReceiverType, "*")] = impl.ImportRelation
+	}
+	want := map[string]string{
+		"Local":      relationSamePackage,
+		"Direct":     relationImportsIface,
+		"Transitive": relationImportsIface,
+		"Wired":      relationSharedImporter,
+		"Loose":      relationUnrelated,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+// 不在模块中的接口无法确定导入路径，不报告关系
+func TestImportRelationOutsideModule(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"iface/iface.go": "package iface\n\ntype Runner interface{ Run() }\n",
+		"impl/impl.go":   "package impl\n\ntype Impl struct{}\n\nfunc (Impl) Run() {}\n",
+	})
+	implementations := runArgs(t, "find-implementations", dir, "Run").(AnalysisResult).Implementations
+	if len(implementations) == 0 {
+		t.Fatal("Impl was not found")
+	}
+	for _, impl := range implementations {
+		if impl.ImportRelation != "" {
+			t.Fatalf("%s: got importRelation %q, want none", impl.ReceiverType, impl.ImportRelation)
+		}
+	}
+}
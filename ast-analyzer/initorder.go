@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+)
+
+// 给接口类型的包级变量赋具体实现的位置
+type InitAssignment struct {
+	InterfaceName string `json:"interfaceName"`
+	VariableName  string `json:"variableName"`
+	ConcreteType  string `json:"concreteType"`
+	InitFile      string `json:"initFile"`
+	InitLine      int    `json:"initLine"`
+	// 赋值来源：init 表示在 init() 中赋值，var 表示包级变量声明时初始化
+	Source string `json:"source"`
+}
+
+type InitOrderResult struct {
+	Assignments []InitAssignment `json:"assignments"`
+}
+
+// 待分析的文件
+type initOrderFile struct {
+	path      string
+	file      *ast.File
+	fset      *token.FileSet
+	qualifier *typeQualifier
+}
+
+// 赋值右侧表达式对应的具体类型：&T{} 为 *T，T{} 为 T，函数调用为 NewT()
+func concreteTypeOf(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.ParenExpr:
+		return concreteTypeOf(t.X)
+	case *ast.UnaryExpr:
+		if t.Op == token.AND {
+			if lit, ok := t.X.(*ast.CompositeLit); ok {
+				return "*" + types.ExprString(lit.Type)
+			}
+		}
+	case *ast.CompositeLit:
+		return types.ExprString(t.Type)
+	case *ast.CallExpr:
+		return types.ExprString(t.Fun) + "()"
+	}
+	return types.ExprString(expr)
+}
+
+// 查找 init() 中以及包级变量声明时给接口类型变量赋的具体实现
+func findInitAssignments(directory string) []InitAssignment {
+	assignments := []InitAssignment{}
+
+	var files []initOrderFile
+	var interfaces []InterfaceInfo
+	typeSpecs := make(map[string]*TypeInfo)
+	err := walkGoFiles(directory, func(path string, f *ast.File, fset *token.FileSet) {
+		files = append(files, initOrderFile{path: path, file: f, fset: fset, qualifier: newTypeQualifier(f)})
+		interfaces = append(interfaces, extractInterfaceInfos(f, fset, path, nil)...)
+		collectTypeSpecs(f, fset, path, typeSpecs)
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "查找 init 赋值时出错: %v\n", err)
+	}
+
+	// 判断类型表达式是否为接口：同包接口、工作区中其他包的接口、内置注册表中的标准库接口
+	isInterface := func(file initOrderFile, expr ast.Expr) bool {
+		switch t := expr.(type) {
+		case *ast.Ident:
+			if t.Name == "error" || t.Name == "any" {
+				return true
+			}
+			info, ok := typeSpecs[packageDir(file.path, file.file)+":"+t.Name]
+			return ok && info.Kind == "interface"
+		case *ast.SelectorExpr:
+			alias, ok := t.X.(*ast.Ident)
+			if !ok {
+				return false
+			}
+			importPath, ok := file.qualifier.importPath[alias.Name]
+			if !ok {
+				return false
+			}
+			if registryInterface(importPath, t.Sel.Name) != nil {
+				return true
+			}
+			for _, iface := range interfaces {
+				if iface.Name == t.Sel.Name && iface.PackageName == importPackageName(importPath) {
+					return true
+				}
+			}
+		case *ast.InterfaceType:
+			return true
+		}
+		return false
+	}
+
+	// 第一遍：收集每个包中接口类型的包级变量，并记录声明时的初始化
+	packageVars := make(map[string]map[string]string) // 包目录 -> 变量名 -> 接口类型
+	for _, file := range files {
+		pkg := packageDir(file.path, file.file)
+		if packageVars[pkg] == nil {
+			packageVars[pkg] = make(map[string]string)
+		}
+		for _, decl := range file.file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				valueSpec := spec.(*ast.ValueSpec)
+				if valueSpec.Type == nil || !isInterface(file, valueSpec.Type) {
+					continue
+				}
+				interfaceName := types.ExprString(valueSpec.Type)
+				for i, name := range valueSpec.Names {
+					packageVars[pkg][name.Name] = interfaceName
+					if i >= len(valueSpec.Values) {
+						continue
+					}
+					pos := file.fset.Position(name.Pos())
+					assignments = append(assignments, InitAssignment{
+						InterfaceName: interfaceName,
+						VariableName:  name.Name,
+						ConcreteType:  concreteTypeOf(valueSpec.Values[i]),
+						InitFile:      file.path,
+						InitLine:      pos.Line,
+						Source:        "var",
+					})
+				}
+			}
+		}
+	}
+
+	// 第二遍：init() 函数体中对这些变量的赋值
+	for _, file := range files {
+		vars := packageVars[packageDir(file.path, file.file)]
+		for _, decl := range file.file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || fn.Name.Name != "init" || fn.Body == nil {
+				continue
+			}
+			ast.Inspect(fn.Body, func(n ast.Node) bool {
+				assign, ok := n.(*ast.AssignStmt)
+				if !ok || assign.Tok != token.ASSIGN || len(assign.Lhs) != len(assign.Rhs) {
+					return true
+				}
+				for i, lhs := range assign.Lhs {
+					ident, ok := lhs.(*ast.Ident)
+					if !ok {
+						continue
+					}
+					interfaceName, ok := vars[ident.Name]
+					if !ok {
+						continue
+					}
+					pos := file.fset.Position(assign.Pos())
+					assignments = append(assignments, InitAssignment{
+						InterfaceName: interfaceName,
+						VariableName:  ident.Name,
+						ConcreteType:  concreteTypeOf(assign.Rhs[i]),
+						InitFile:      file.path,
+						InitLine:      pos.Line,
+						Source:        "init",
+					})
+				}
+				return true
+			})
+		}
+	}
+
+	return assignments
+}
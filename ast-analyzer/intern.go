@@ -0,0 +1,30 @@
+package main
+
+// 字符串驻留：大量文件中重复出现的包目录、类型名和方法名只保留一份。
+// 每次扫描使用自己的驻留表（ScanResult.interner），随扫描结果一起释放；
+// walkGoFiles 的回调在调用方的 goroutine 中顺序执行，无需加锁。nil 驻留表不驻留，原样返回
+type stringInterner map[string]string
+
+func (in stringInterner) intern(s string) string {
+	if in == nil {
+		return s
+	}
+	if interned, ok := in[s]; ok {
+		return interned
+	}
+	in[s] = s
+	return s
+}
+
+// 与 intern 相同，但参数是字节切片：已驻留时查找不会分配新的字符串，调用方可以复用缓冲区
+func (in stringInterner) internBytes(b []byte) string {
+	if in == nil {
+		return string(b)
+	}
+	if interned, ok := in[string(b)]; ok {
+		return interned
+	}
+	s := string(b)
+	in[s] = s
+	return s
+}
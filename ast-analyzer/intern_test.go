@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 基准测试用的目录：8 个包，每个包 25 个文件，每个文件 4 个类型各 6 个方法，
+// 包名、类型名和方法名在文件之间大量重复
+func methodFixture(b *testing.B) (*token.FileSet, []*ast.File) {
+	b.Helper()
+	files := make(map[string]string)
+	for p := 0; p < 8; p++ {
+		for n := 0; n < 25; n++ {
+			var src strings.Builder
+			fmt.Fprintf(&src, "package pkg%d\n\n", p)
+			for t := 0; t < 4; t++ {
+				typeName := fmt.Sprintf("Type%d_%d", n, t)
+				fmt.Fprintf(&src, "type %s struct{ next *%s }\n\n", typeName, typeName)
+				for m := 0; m < 6; m++ {
+					receiver := typeName
+					if m%2 == 0 {
+						receiver = "*" + typeName
+					}
+					fmt.Fprintf(&src, "func (r %s) Method%d(ctx string, n int) (string, error) { return ctx, nil }\n\n", receiver, m)
+				}
+			}
+			files[fmt.Sprintf("pkg%d/file%d.go", p, n)] = src.String()
+		}
+	}
+	dir := writeTree(b, files)
+
+	fset := token.NewFileSet()
+	var parsed []*ast.File
+	for name := range files {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, filepath.FromSlash(name)), nil, parser.ParseComments)
+		if err != nil {
+			b.Fatal(err)
+		}
+		parsed = append(parsed, f)
+	}
+	return fset, parsed
+}
+
+func BenchmarkCollectTypeMethods(b *testing.B) {
+	fset, files := methodFixture(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// 每次都从空的驻留表开始，计入驻留本身的开销
+		interner := make(stringInterner)
+		methods := make(map[string]map[string]*MethodInfo)
+		for _, f := range files {
+			collectTypeMethods(f, fset, methods, interner)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import "go/ast"
+
+// 在方法体中调用了 os 或 ioutil 包函数的接口实现方法（文件 I/O 等副作用）
+type IOOperation struct {
+	ReceiverType string `json:"receiverType"`
+	MethodName   string `json:"methodName"`
+	// 调用的函数，例如 os.Open、ioutil.ReadAll
+	IOFunc   string   `json:"ioFunc"`
+	Location Location `json:"location"`
+}
+
+type IOOperationResult struct {
+	Operations []IOOperation `json:"operations"`
+}
+
+// 产生 I/O 副作用的包，按调用时的包名判断
+var ioPackages = map[string]bool{"os": true, "ioutil": true}
+
+// 查找方法体中调用了 os.X 或 ioutil.X 的接口实现方法，每个调用输出一条
+func findIOOperations(directory string) []IOOperation {
+	results := []IOOperation{}
+
+	for _, method := range uniqueImplementedMethods(matchAllImplementations(directory)) {
+		if method.fset == nil || method.FuncDecl.Body == nil {
+			continue
+		}
+		ast.Inspect(method.FuncDecl.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			selector, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			if pkg, ok := selector.X.(*ast.Ident); ok && ioPackages[pkg.Name] {
+				pos := method.fset.Position(call.Pos())
+				results = append(results, IOOperation{
+					ReceiverType: method.ReceiverType,
+					MethodName:   method.Name,
+					IOFunc:       pkg.Name + "." + selector.Sel.Name,
+					Location:     Location{File: pos.Filename, Line: pos.Line - 1, Column: pos.Column - 1},
+				})
+			}
+			return true
+		})
+	}
+
+	return results
+}
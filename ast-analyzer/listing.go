@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 扫描范围内每个目录的列表摘要：.go 文件的名称、大小、修改时间以及子目录名。
+// 新增、删除或修改文件都会改变所在目录的摘要，每个目录只需一次 ReadDir。
+// 跳过的目录与 walkGoFiles 一致
+func listingDigests(root string) map[string]uint64 {
+	digests := make(map[string]uint64)
+	limits := newWalkLimits(root)
+	var walk func(dir string)
+	walk = func(dir string) {
+		if !limits.enter(dir) {
+			return
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return
+		}
+		h := fnv.New64a()
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() {
+				fmt.Fprintf(h, "d %s\n", name)
+				continue
+			}
+			if !strings.HasSuffix(name, ".go") {
+				continue
+			}
+			if info, err := entry.Info(); err == nil {
+				fmt.Fprintf(h, "f %s %d %d\n", name, info.Size(), info.ModTime().UnixNano())
+			}
+		}
+		digests[dir] = h.Sum64()
+
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+			if entry.IsDir() && !strings.Contains(path, "vendor") && !strings.HasPrefix(entry.Name(), ".") {
+				walk(path)
+			}
+		}
+	}
+	walk(root)
+	return digests
+}
+
+func sameDigests(a, b map[string]uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for dir, digest := range a {
+		if b[dir] != digest {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestListingDigestsNoticeFileChanges(t *testing.T) {
+	dir := writeTree(t, map[string]string{"a.go": serveFixtureInterface, "sub/b.go": "package sub\n", "notes.txt": "x"})
+	before := listingDigests(dir)
+	if len(before) != 2 {
+		t.Fatalf("got digests for %d directories, want 2", len(before))
+	}
+
+	for _, change := range []struct {
+		name  string
+		apply func()
+	}{
+		{"other files are ignored", func() { writeFile(t, filepath.Join(dir, "notes.txt"), "changed") }},
+		{"edited file", func() { writeFile(t, filepath.Join(dir, "sub", "b.go"), "package sub\n\n// edited\n") }},
+		{"touched file", func() {
+			later := time.Now().Add(time.Hour)
+			os.Chtimes(filepath.Join(dir, "a.go"), later, later)
+		}},
+		{"added file", func() { writeFile(t, filepath.Join(dir, "c.go"), "package p\n") }},
+		{"removed file", func() { os.Remove(filepath.Join(dir, "c.go")) }},
+		{"added directory", func() { os.Mkdir(filepath.Join(dir, "empty"), 0o755) }},
+	} {
+		change.apply()
+		after := listingDigests(dir)
+		if same := sameDigests(before, after); same != (change.name == "other files are ignored") {
+			t.Errorf("%s: sameDigests = %v", change.name, same)
+		}
+		before = after
+	}
+}
+
+// 索引就绪后新增和删除文件，下一个请求不需要其他修改就能看到变化
+func TestServeIndexRefreshesWhenListingChanges(t *testing.T) {
+	dir := writeTree(t, map[string]string{"a.go": serveFixtureInterface})
+	savedOptions, savedIndexes := options, serveIndexes
+	t.Cleanup(func() { options, serveIndexes = savedOptions, savedIndexes })
+
+	index := newScanIndex(dir, parseOptions([]string{"serve", dir}))
+	serveIndexes = []*scanIndex{index}
+	index.build()
+	<-index.ready
+
+	implementors := func() []string {
+		analysisMu.Lock()
+		response := handleServeRequest(serveRequest{Args: []string{"find-implementations", dir, "Run"}})
+		analysisMu.Unlock()
+		if response.Error != "" {
+			t.Fatal(response.Error)
+		}
+		types := receiverTypes(response.Result.(AnalysisResult).Implementations)
+		sort.Strings(types)
+		return types
+	}
+
+	if got, want := implementors(), []string{"A"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("warm index: got %v, want %v", got, want)
+	}
+	writeFile(t, filepath.Join(dir, "b.go"), "package p\n\ntype B struct{}\n\nfunc (B) Run() {}\n")
+	if got, want := implementors(), []string{"A", "B"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("after adding b.go: got %v, want %v", got, want)
+	}
+	if err := os.Remove(filepath.Join(dir, "b.go")); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := implementors(), []string{"A"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("after removing b.go: got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+)
+
+// 在方法体中调用了日志函数的接口实现方法
+type LoggingMethod struct {
+	ReceiverType    string   `json:"receiverType"`
+	MethodName      string   `json:"methodName"`
+	LogCallLocation Location `json:"logCallLocation"`
+	// 调用的日志函数，例如 log.Printf、s.logger.Info
+	LogFunc string `json:"logFunc"`
+}
+
+type LoggingResult struct {
+	Methods []LoggingMethod `json:"methods"`
+}
+
+// 日志函数名（不含 f、ln、w、Context 等后缀）；标准库 log、slog 以及 zap、logrus 等常见日志库都使用这些名字
+var logFuncNames = map[string]bool{
+	"Print": true, "Fatal": true, "Panic": true,
+	"Debug": true, "Info": true, "Warn": true, "Warning": true, "Error": true, "Log": true, "LogAttrs": true,
+}
+
+// 调用是否像日志调用：X.Printf、X.Info 这样的选择器调用，且至少有一个参数（排除 err.Error() 这类调用）。
+// fmt.Print 系列输出到标准输出，也算作日志
+func logCallName(call *ast.CallExpr) (string, bool) {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || len(call.Args) == 0 {
+		return "", false
+	}
+	name := selector.Sel.Name
+	for _, suffix := range []string{"Context", "f", "ln", "w", "S"} {
+		if base := strings.TrimSuffix(name, suffix); base != name && logFuncNames[base] {
+			name = base
+			break
+		}
+	}
+	if !logFuncNames[name] {
+		return "", false
+	}
+	// fmt.Errorf、errors.Join 之类构造错误的函数不是日志
+	if pkg, ok := selector.X.(*ast.Ident); ok && (pkg.Name == "fmt" || pkg.Name == "errors") && name != "Print" {
+		return "", false
+	}
+	return types.ExprString(call.Fun), true
+}
+
+// 查找方法体中调用了日志函数的接口实现方法，每个日志调用输出一条（嵌套的函数字面量也计入）
+func findLoggingMethods(directory string) []LoggingMethod {
+	results := []LoggingMethod{}
+
+	for _, method := range uniqueImplementedMethods(matchAllImplementations(directory)) {
+		if method.fset == nil || method.FuncDecl.Body == nil {
+			continue
+		}
+		ast.Inspect(method.FuncDecl.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			if logFunc, ok := logCallName(call); ok {
+				pos := method.fset.Position(call.Pos())
+				results = append(results, LoggingMethod{
+					ReceiverType:    method.ReceiverType,
+					MethodName:      method.Name,
+					LogCallLocation: Location{File: pos.Filename, Line: pos.Line - 1, Column: pos.Column - 1},
+					LogFunc:         logFunc,
+				})
+			}
+			return true
+		})
+	}
+
+	return results
+}
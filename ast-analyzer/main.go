@@ -1,28 +1,59 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"go/ast"
-	"go/parser"
 	"go/token"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 )
 
 type Location struct {
 	File   string `json:"file"`
 	Line   int    `json:"line"`
 	Column int    `json:"column"`
+	// 行列号是否从 1 开始（方法实现侧的位置），输出 LSP 位置时用于换算
+	oneBased bool
 }
 
 type InterfaceMethod struct {
 	Name          string   `json:"name"`
 	InterfaceName string   `json:"interfaceName"`
 	Location      Location `json:"location"`
-	// 添加结束位置
+	// 方法声明的结束位置
 	EndLocation Location `json:"endLocation"`
+	// CodeLens 的锚点，由 --lens-anchor 决定
+	Anchor Location `json:"anchor"`
+	// 方法名标识符的范围，用于跳转后的选区；通过嵌入获得的方法为接口名
+	NameLocation    Location `json:"nameLocation"`
+	NameEndLocation Location `json:"nameEndLocation"`
+	// 非精确名称匹配时的相似度（0~1）
+	Score float64 `json:"score,omitempty"`
+	// 泛型接口的类型参数
+	TypeParams []TypeParam `json:"typeParams,omitempty"`
+	// 方法来自嵌入接口时的真正声明位置
+	DeclaredIn *DeclarationRef `json:"declaredIn,omitempty"`
+	// --implementation-counts 时 find-implementations 对该方法会返回的实现数量
+	ImplementationCount *int `json:"implementationCount,omitempty"`
+	// find-file-interfaces：方法在接口中的序号和接口在文件中的序号，同一行的 CodeLens 按它们排序
+	Ordinal          *int `json:"ordinal,omitempty"`
+	InterfaceOrdinal *int `json:"interfaceOrdinal,omitempty"`
+}
+
+// 接口方法的声明位置：声明该方法的接口名和方法位置
+type DeclarationRef struct {
+	InterfaceName string   `json:"interfaceName"`
+	Location      Location `json:"location"`
+}
+
+// 类型参数及其约束（按源码原样渲染），例如 K comparable
+type TypeParam struct {
+	Name       string `json:"name"`
+	Constraint string `json:"constraint"`
 }
 
 type Implementation struct {
@@ -31,22 +62,65 @@ type Implementation struct {
 	Location     Location `json:"location"`
 	// 添加结束位置
 	EndLocation Location `json:"endLocation"`
+	// 方法名标识符的范围，用于跳转后的选区
+	NameLocation    Location `json:"nameLocation"`
+	NameEndLocation Location `json:"nameEndLocation"`
+	// 实现类型的定义形式：struct、func（函数类型适配器）、alias 等
+	Kind string `json:"kind,omitempty"`
+	// find-file-implementations：类型满足的、声明了该方法的全部接口
+	Interfaces []string `json:"interfaces,omitempty"`
+	// 源码中的接收者变量名；匿名接收者 (*T) 和 _ 接收者为空字符串
+	ReceiverName string `json:"receiverName"`
+	// 接收者字段（不含括号）的范围，例如 s *SimpleTokenManager
+	ReceiverLocation    Location `json:"receiverLocation"`
+	ReceiverEndLocation Location `json:"receiverEndLocation"`
+	// 非精确名称匹配时的相似度（0~1）
+	Score float64 `json:"score,omitempty"`
+	// 方法通过嵌入字段提升而来时，原始声明所在的类型
+	PromotedFrom string `json:"promotedFrom,omitempty"`
+	// 接口方法来自嵌入接口时的真正声明位置
+	DeclaredIn *DeclarationRef `json:"declaredIn,omitempty"`
+	// 实现类型位于接口所在包的外部测试包（package foo_test）中
+	ExternalTestPackage bool `json:"externalTestPackage,omitempty"`
+	// 实现方法的签名，例如 AddToken(token string) error
+	Signature string `json:"signature"`
+	// 方法所属的接口：find-implementations 中为查询到的接口，find-file-implementations 中为 Interfaces 的第一个
+	Interface string `json:"interface,omitempty"`
+	// 展示用的标签，例如 *SimpleTokenManager.AddToken(token string) error
+	Label string `json:"label"`
+	// find-implementations：实现类型所在包与接口所在包的导入关系，
+	// same-package、imports-interface-package、shared-importer 或 unrelated
+	ImportRelation string `json:"importRelation,omitempty"`
+	// 实现来自主模块以外的模块（依赖、go.work 中的其他模块）时，该模块的路径和版本
+	Module *ModuleRef `json:"module,omitempty"`
+}
+
+func implementationLabel(receiverType, signature string) string {
+	return receiverType + "." + signature
 }
 
 type AnalysisResult struct {
 	Interfaces      []InterfaceMethod `json:"interfaces"`
 	Implementations []Implementation  `json:"implementations"`
+	// --explain 时列出只因参数指针/值不一致而没有匹配的类型
+	NearMisses []NearMiss `json:"nearMisses,omitempty"`
+	// --group-by-type 时按实现类型分组的实现
+	ImplementationsByType map[string][]Implementation `json:"implementationsByType,omitempty"`
+	// 目录下没有结果、改为从模块根目录查找时为 "module"
+	Scope string `json:"scope,omitempty"`
 }
 
 type PackageAnalysisResult struct {
 	InterfaceImplementations map[string][]string `json:"interfaceImplementations"` // 接口名 -> 实现方法列表
-	MethodToInterface        map[string]string   `json:"methodToInterface"`        // 方法名 -> 接口名
+	MethodToInterface        map[string]string   `json:"methodToInterface"`        // 方法名 -> 接口名（多个时取名称最小的）
+	MethodToInterfaces       map[string][]string `json:"methodToInterfaces"`       // 方法名 -> 全部接口名
 }
 
 func analyzePackageInterfaces(packagePath string) PackageAnalysisResult {
 	result := PackageAnalysisResult{
 		InterfaceImplementations: make(map[string][]string),
 		MethodToInterface:        make(map[string]string),
+		MethodToInterfaces:       make(map[string][]string),
 	}
 
 	// 1. 扫描包中所有 .go 文件
@@ -79,78 +153,373 @@ func analyzePackageInterfaces(packagePath string) PackageAnalysisResult {
 			// 查找匹配的实现
 			for _, impls := range implementations {
 				for _, impl := range impls {
-					if impl.MethodName == method.Name {
-						// 这里可以添加更复杂的签名匹配逻辑
+					// 同一个类型可能同时满足多个接口，每个接口分别记录
+					if impl.MethodName == method.Name && slices.Contains(impl.Interfaces, interfaceName) {
 						result.InterfaceImplementations[interfaceName] = append(
 							result.InterfaceImplementations[interfaceName],
 							impl.MethodName,
 						)
-						result.MethodToInterface[impl.MethodName] = interfaceName
+						if !slices.Contains(result.MethodToInterfaces[impl.MethodName], interfaceName) {
+							result.MethodToInterfaces[impl.MethodName] = append(result.MethodToInterfaces[impl.MethodName], interfaceName)
+						}
 					}
 				}
 			}
 		}
 	}
 
+	// 结果与 map 的遍历顺序无关
+	for methodName, names := range result.MethodToInterfaces {
+		sort.Strings(names)
+		result.MethodToInterface[methodName] = names[0]
+	}
+
 	return result
 }
 
 func main() {
 	if len(os.Args) < 3 {
 		fmt.Fprintf(os.Stderr, "Usage: %s <command> <directory/file>\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "Commands: find-implementations, find-interfaces, find-file-interfaces, find-file-implementations\n")
+		fmt.Fprintf(os.Stderr, "Commands: find-implementations, find-interfaces, find-file-interfaces, find-file-implementations, analyze-package-interfaces, describe-interface, find-interface-method-body-size, find-interface-adoption-timeline, find-interface-method-naming-patterns,\n")
+		fmt.Fprintf(os.Stderr, "          find-interface-circular-dependencies, find-interface-method-return-count,\n")
+		fmt.Fprintf(os.Stderr, "          find-interface-methods-with-function-params, find-interface-method-generics-constraints,\n")
+		fmt.Fprintf(os.Stderr, "          find-type-interfaces, find-interface-init-order, find-interface-method-synchronization,\n")
+		fmt.Fprintf(os.Stderr, "          find-interface-type-parameter-constraints, mock-info, find-interface-cross-package-embedding,\n")
+		fmt.Fprintf(os.Stderr, "          find-interface-method-chaining, suggest-interfaces, find-interface-context-propagation,\n")
+		fmt.Fprintf(os.Stderr, "          find-interface-method-error-wrapping, find-interface-method-logging,\n")
+		fmt.Fprintf(os.Stderr, "          synthesize-interface, find-interface-method-io-operations, find-interface-method-network-operations,\n")
+		fmt.Fprintf(os.Stderr, "          find-interface-method-database-calls, find-interface-method-cache-usage,\n")
+		fmt.Fprintf(os.Stderr, "          find-method-interfaces-module, find-interface-method-retry-patterns, analyze-graph,\n")
+		fmt.Fprintf(os.Stderr, "          find-interface-test-coverage (with --include-tests), find-interface-method-default-values,\n")
+		fmt.Fprintf(os.Stderr, "          find-interface-method-delegation, find-interface-method-transaction-patterns,\n")
+		fmt.Fprintf(os.Stderr, "          find-interface-constrained-type-params, find-interface-implementation-complexity-ranking,\n")
+		fmt.Fprintf(os.Stderr, "          file-map, find-interface-method-struct-literal-returns, explain,\n")
+		fmt.Fprintf(os.Stderr, "          find-interface-satisfying-nil, find-interface-embedded-struct-method-conflicts,\n")
+		fmt.Fprintf(os.Stderr, "          compare-interfaces, generate-stub, find-interface-implementation-file-distribution,\n")
+		fmt.Fprintf(os.Stderr, "          find-interface-method-returns-self, find-interface-method-panic-on-not-implemented,\n")
+		fmt.Fprintf(os.Stderr, "          find-interface-multiple-inheritance, find-interface-method-access-pattern, serve\n")
+		fmt.Fprintf(os.Stderr, "Options: --goroot[=<dir>]  resolve embedded standard library interfaces from GOROOT\n")
+		fmt.Fprintf(os.Stderr, "         --std  find-type-interfaces/suggest-interfaces: also match exported standard library interfaces (GOROOT scan, cached on disk)\n")
+		fmt.Fprintf(os.Stderr, "         --env KEY=VAL  environment override for go toolchain invocations (repeatable)\n")
+		fmt.Fprintf(os.Stderr, "         --group-by-type  find-implementations/find-file-implementations: group implementations by receiver type\n")
+		fmt.Fprintf(os.Stderr, "         --implementation-counts  find-file-interfaces: add implementationCount per method (scans --root or the file's module)\n")
+		fmt.Fprintf(os.Stderr, "         --include-tests  also analyze _test.go files; package foo_test types may implement foo's interfaces\n")
+		fmt.Fprintf(os.Stderr, "         --include-ignored  also analyze files excluded by a //go:build ignore constraint (generators, standalone scripts)\n")
+		fmt.Fprintf(os.Stderr, "         --lens-anchor=method-line|above-method|interface-line  anchor reported for interface methods\n")
+		fmt.Fprintf(os.Stderr, "         --parse-timeout MS  give up on files over 1 MB whose parse takes longer than MS milliseconds (reported as abandoned)\n")
+		fmt.Fprintf(os.Stderr, "         --jobs N  number of files parsed in parallel (default: number of CPUs; 1 parses sequentially)\n")
+		fmt.Fprintf(os.Stderr, "         --min-interfaces N  find-interface-multiple-inheritance: minimum number of implemented interfaces (default 2)\n")
+		fmt.Fprintf(os.Stderr, "         --min-ratio R  suggest-interfaces: minimum fraction of interface methods already implemented (default 0.5)\n")
+		fmt.Fprintf(os.Stderr, "         --stdlib-embeddings  resolve embedded standard library interfaces from the built-in registry\n")
+		fmt.Fprintf(os.Stderr, "         --origin <file> --limit N  rank find-implementations/find-interfaces results by proximity and truncate\n")
+		fmt.Fprintf(os.Stderr, "         --match-name=exact|prefix|fuzzy|icase  method name matching for find-implementations/find-interfaces\n")
+		fmt.Fprintf(os.Stderr, "         --ignore-case  find-implementations/find-interfaces: compare method names case-insensitively (signatures stay case-sensitive)\n")
+		fmt.Fprintf(os.Stderr, "         --package-prefix <prefix>  find-implementations: only report implementations whose file path (relative to <directory>) or import path starts with prefix\n")
+		fmt.Fprintf(os.Stderr, "         --explain  find-implementations: list types that miss only by pointer/value parameter types\n")
+		fmt.Fprintf(os.Stderr, "         --lsp  emit locations as LSP { uri, range } objects\n")
+		fmt.Fprintf(os.Stderr, "         --max-depth N  do not descend more than N directory levels below the scan root\n")
+		fmt.Fprintf(os.Stderr, "         --max-dirs N  stop descending after visiting N directories (default 20000, 0 = unlimited); reports a scanTruncated diagnostic\n")
+		fmt.Fprintf(os.Stderr, "         --no-fallback  find-implementations: do not retry from the module root when the directory has no implementations\n")
+		fmt.Fprintf(os.Stderr, "         --stream  find-implementations: write each implementation as an NDJSON record as soon as it is confirmed, then a trailer with counts\n")
+		fmt.Fprintf(os.Stderr, "         --with-stubs  describe-interface: add defaultBody, a zero-value return statement for each method\n")
+		fmt.Fprintf(os.Stderr, "         --human  explain: print the matching steps as text instead of JSON\n")
+		fmt.Fprintf(os.Stderr, "         --format=json|dot  analyze-graph: output interface/implementation relationships as Graphviz DOT\n")
+		fmt.Fprintf(os.Stderr, "         --warn-large-interface N  add warnings for interfaces with more than N methods\n")
+		fmt.Fprintf(os.Stderr, "         --root <dir>  module root for import path arguments (analyze-package-interfaces ./pkg/... or example.com/mod/pkg)\n")
+		fmt.Fprintf(os.Stderr, "         (find-method-interfaces-module scans the whole module containing <directory>, or --root)\n")
+		fmt.Fprintf(os.Stderr, "         --per-package  analyze-package-interfaces: scan the whole tree and group results by package\n")
+		fmt.Fprintf(os.Stderr, "         --max-file-size N  skip files larger than N bytes during directory scans (default 10 MiB, 0 = no limit)\n")
+		fmt.Fprintf(os.Stderr, "         --output <file>  write the result to a file instead of stdout\n")
+		fmt.Fprintf(os.Stderr, "         --compress=gzip  gzip-compress the result\n")
+		fmt.Fprintf(os.Stderr, "         --warming=block|partial  serve: wait for the index or answer {\"warming\":true} while it is built\n")
 		os.Exit(1)
 	}
 
-	command := os.Args[1]
-	target := os.Args[2]
+	options = parseOptions(os.Args[1:])
+	if len(options.Args) < 2 {
+		fmt.Fprintf(os.Stderr, "Usage: %s <command> <directory/file>\n", os.Args[0])
+		os.Exit(1)
+	}
+
+	command := options.Args[0]
+	target := options.Args[1]
+	if command == "serve" {
+		// 常驻模式：从标准输入读取请求
+		if err := serve(target); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if err := validateOutputOptions(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	startMeta(target)
 
+	result, err := safeRunCommand(command, target)
+	if err != nil {
+		if panicErr, ok := err.(*panicError); ok {
+			printPanic(panicErr)
+		}
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := printResult(result); err != nil {
+		fmt.Fprintf(os.Stderr, "输出结果时出错: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// 命令参数错误，输出用法说明
+type usageError string
+
+func (e usageError) Error() string {
+	return fmt.Sprintf("Usage: %s %s", os.Args[0], string(e))
+}
+
+// 执行单个命令并返回结果
+func runCommand(command, target string) (interface{}, error) {
+	if err := options.validateNumbers(); err != nil {
+		return nil, err
+	}
 	switch command {
 	case "find-implementations":
-		if len(os.Args) < 4 {
-			fmt.Fprintf(os.Stderr, "Usage: %s find-implementations <directory> <method-name>\n", os.Args[0])
-			os.Exit(1)
+		if len(options.Args) < 3 {
+			return nil, usageError("find-implementations <directory> <method-name>")
 		}
-		methodName := os.Args[3]
-		implementations := findImplementations(target, methodName)
-		result := AnalysisResult{Implementations: implementations}
-		output, _ := json.Marshal(result)
-		fmt.Println(string(output))
+		methodName := options.Args[2]
+		if options.Has("stream") {
+			return streamImplementations(target, methodName), nil
+		}
+		return findImplementationsWithFallback(target, methodName), nil
 
 	case "find-interfaces":
-		if len(os.Args) < 4 {
-			fmt.Fprintf(os.Stderr, "Usage: %s find-interfaces <directory> <method-name>\n", os.Args[0])
-			os.Exit(1)
+		if len(options.Args) < 3 {
+			return nil, usageError("find-interfaces <directory> <method-name>")
 		}
-		methodName := os.Args[3]
+		methodName := options.Args[2]
 		interfaces := findInterfaces(target, methodName)
+		interfaces = rankResults(interfaces,
+			func(method InterfaceMethod) Location { return method.Location },
+			func(method InterfaceMethod) string { return method.InterfaceName + "." + method.Name })
 		result := AnalysisResult{Interfaces: interfaces}
-		output, _ := json.Marshal(result)
-		fmt.Println(string(output))
+		return result, nil
 
 	case "find-file-interfaces":
 		// 分析单个文件中的接口方法
 		interfaces := findFileInterfaces(target)
+		if options.Has("implementation-counts") {
+			countFileImplementations(target, interfaces)
+		}
 		result := AnalysisResult{Interfaces: interfaces}
-		output, _ := json.Marshal(result)
-		fmt.Println(string(output))
+		return result, nil
 
 	case "find-file-implementations":
 		// 分析单个文件中的方法实现
 		implementations := findFileImplementations(target)
 		result := AnalysisResult{Implementations: implementations}
-		output, _ := json.Marshal(result)
-		fmt.Println(string(output))
+		if options.Has("group-by-type") {
+			result.groupByType()
+		}
+		return result, nil
 	// 添加新的命令处理
 	case "analyze-package-interfaces":
+		// 导入路径或 /... 模式：逐个分析匹配的包
+		if isPackagePattern(target) {
+			result, err := analyzePackagePattern(target)
+			if err != nil {
+				return nil, err
+			}
+			return result, nil
+		}
+		// --per-package：分析整个目录树，按包分组输出
+		if options.Has("per-package") {
+			return analyzePackageTree(target), nil
+		}
 		// 分析整个包的接口实现关系
 		packagePath := target
 		result := analyzePackageInterfaces(packagePath)
-		output, _ := json.Marshal(result)
-		fmt.Println(string(output))
+		return result, nil
+	case "find-interface-method-body-size":
+		maxLines := options.Int("max-lines", 50)
+		result := BodySizeResult{LargeMethods: findLargeMethods(target, maxLines)}
+		return result, nil
+	case "find-interface-adoption-timeline":
+		var since time.Time
+		if value := options.String("since", ""); value != "" {
+			parsed, ok := parseSince(value)
+			if !ok {
+				return nil, fmt.Errorf("invalid --since date: %s", value)
+			}
+			since = parsed
+		}
+		result := TimelineResult{Timeline: findAdoptionTimeline(target, since)}
+		return result, nil
+	case "find-interface-method-naming-patterns":
+		result := NamingPatternResult{Patterns: findMethodNamingPatterns(target)}
+		return result, nil
+	case "find-interface-circular-dependencies":
+		result := CircularDependencyResult{Warnings: findInterfaceCircularDependencies(target)}
+		return result, nil
+	case "find-interface-method-return-count":
+		maxReturns := options.Int("max-returns", 3)
+		result := ReturnCountResult{Methods: findTooManyReturns(target, maxReturns)}
+		return result, nil
+	case "find-interface-methods-with-function-params":
+		result := HigherOrderResult{Methods: findHigherOrderMethods(target)}
+		return result, nil
+	case "find-interface-method-generics-constraints":
+		result := GenericConstraintResult{Constraints: findGenericConstraints(target)}
+		return result, nil
+	case "find-type-interfaces":
+		if len(options.Args) < 3 {
+			return nil, usageError("find-type-interfaces <directory> <type|pkg.Type|import/path.Type>")
+		}
+		result := TypeInterfacesResult{Interfaces: findTypeInterfaces(target, options.Args[2])}
+		return result, nil
+	case "find-interface-init-order":
+		result := InitOrderResult{Assignments: findInitAssignments(target)}
+		return result, nil
+	case "find-interface-method-synchronization":
+		result := SynchronizationResult{Methods: findSynchronizedMethods(target)}
+		return result, nil
+	case "find-interface-type-parameter-constraints":
+		result := GenericImplementationResult{Implementations: findGenericImplementations(target)}
+		return result, nil
+	case "find-interface-constrained-type-params":
+		result := ConstrainedTypeParamResult{TypeParams: findConstrainedTypeParams(target)}
+		return result, nil
+	case "find-interface-implementation-complexity-ranking":
+		if len(options.Args) < 3 {
+			return nil, usageError("find-interface-implementation-complexity-ranking <directory> <interface-name>")
+		}
+		result := ComplexityRankingResult{Methods: rankImplementationComplexity(target, options.Args[2])}
+		return result, nil
+	case "file-map":
+		result := buildFileMap(target)
+		return result, nil
+	case "find-interface-method-struct-literal-returns":
+		result := StructLiteralReturnResult{Returns: findStructLiteralReturns(target)}
+		return result, nil
+	case "find-interface-satisfying-nil":
+		result := NilSafeResult{Interfaces: findNilSafeInterfaces(target)}
+		return result, nil
+	case "find-interface-embedded-struct-method-conflicts":
+		result := EmbeddingConflictResult{Conflicts: findEmbeddingConflicts(target)}
+		return result, nil
+	case "compare-interfaces":
+		if len(options.Args) < 4 {
+			return nil, usageError("compare-interfaces <directory> <interface-a> <interface-b>")
+		}
+		return compareInterfaces(target, options.Args[2], options.Args[3])
+	case "generate-stub":
+		if len(options.Args) < 5 {
+			return nil, usageError("generate-stub <directory> <interface-name> <destination-file> <type-name>")
+		}
+		return generateStub(target, options.Args[2], options.Args[3], options.Args[4])
+	case "find-interface-implementation-file-distribution":
+		result := FileDistributionResult{Distributions: findFileDistribution(target)}
+		return result, nil
+	case "find-interface-multiple-inheritance":
+		result := MultipleInterfaceResult{Implementors: findMultipleInterfaceImplementors(target)}
+		return result, nil
+	case "find-interface-method-access-pattern":
+		result := AccessPatternResult{Interfaces: findInterfaceAccessPatterns(target)}
+		return result, nil
+	case "find-interface-method-returns-self":
+		result := SelfReturnResult{Violations: findSelfReturns(target)}
+		return result, nil
+	case "find-interface-method-panic-on-not-implemented":
+		result := StubImplementationResult{Stubs: findStubImplementations(target)}
+		return result, nil
+	case "explain":
+		if len(options.Args) < 4 {
+			return nil, usageError("explain <directory> <type-name> <interface-name>")
+		}
+		result := explainMatch(target, options.Args[2], options.Args[3])
+		return result, nil
+	case "mock-info":
+		if len(options.Args) < 3 {
+			return nil, usageError("mock-info <directory> <interface-name>")
+		}
+		result := MockInfoResult{Interfaces: findMockInfo(target, options.Args[2])}
+		return result, nil
+	case "find-interface-cross-package-embedding":
+		result := CrossPackageEmbeddingResult{Embeddings: findCrossPackageEmbeddings(target)}
+		return result, nil
+	case "find-interface-method-error-wrapping":
+		result := ErrorWrappingResult{Errors: findUnwrappedErrors(target)}
+		return result, nil
+	case "find-interface-method-logging":
+		result := LoggingResult{Methods: findLoggingMethods(target)}
+		return result, nil
+	case "find-interface-method-io-operations":
+		result := IOOperationResult{Operations: findIOOperations(target)}
+		return result, nil
+	case "find-interface-method-network-operations":
+		result := NetworkCallResult{Calls: findNetworkCalls(target)}
+		return result, nil
+	case "find-interface-method-database-calls":
+		result := DatabaseCallResult{Calls: findDatabaseCalls(target)}
+		return result, nil
+	case "find-interface-method-cache-usage":
+		result := CacheUsageResult{Usages: findCacheUsage(target)}
+		return result, nil
+	case "find-method-interfaces-module":
+		if len(options.Args) < 3 {
+			return nil, usageError("find-method-interfaces-module <directory> <method-name>")
+		}
+		result := findMethodInterfacesModule(target, options.Args[2])
+		return result, nil
+	case "find-interface-method-retry-patterns":
+		result := RetryPatternResult{Patterns: findRetryPatterns(target)}
+		return result, nil
+	case "analyze-graph":
+		result := analyzeGraph(target)
+		return result, nil
+	case "find-interface-test-coverage":
+		if !options.Has("include-tests") {
+			return nil, fmt.Errorf("find-interface-test-coverage requires --include-tests to scan _test.go files")
+		}
+		result := findTestCoverage(target)
+		return result, nil
+	case "find-interface-method-default-values":
+		result := DefaultValueResult{Implementations: findDefaultValueImplementations(target)}
+		return result, nil
+	case "find-interface-method-delegation":
+		result := DelegationResult{Methods: findDelegationMethods(target)}
+		return result, nil
+	case "find-interface-method-transaction-patterns":
+		result := TransactionResult{Methods: findTransactionMethods(target)}
+		return result, nil
+	case "find-interface-context-propagation":
+		result := ContextPropagationResult{Inconsistencies: findContextPropagation(target)}
+		return result, nil
+	case "suggest-interfaces":
+		if len(options.Args) < 3 {
+			return nil, usageError("suggest-interfaces <directory> <type|pkg.Type|import/path.Type>")
+		}
+		result := SuggestionResult{Suggestions: suggestInterfaces(target, options.Args[2])}
+		return result, nil
+	case "synthesize-interface":
+		if len(options.Args) < 3 {
+			return nil, usageError("synthesize-interface <directory> <type|pkg.Type|import/path.Type> [--name InterfaceName]")
+		}
+		result := SynthesizeResult{Interfaces: synthesizeInterface(target, options.Args[2])}
+		return result, nil
+	case "find-interface-method-chaining":
+		result := FluentInterfaceResult{Interfaces: findFluentInterfaces(target)}
+		return result, nil
+	case "describe-interface":
+		if len(options.Args) < 3 {
+			return nil, usageError("describe-interface <directory> <interface-name>")
+		}
+		interfaceName := options.Args[2]
+		result := DescribeResult{Interfaces: describeInterface(target, interfaceName)}
+		return result, nil
 	default:
-		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", command)
-		os.Exit(1)
+		return nil, fmt.Errorf("unknown command: %s", command)
 	}
 }
 
@@ -163,54 +532,64 @@ func findFileInterfaces(filePath string) []InterfaceMethod {
 		return interfaces
 	}
 
-	src, err := os.ReadFile(filePath)
+	f, err := parseGoFile(fset, filePath)
 	if err != nil {
+		reportParseError(err)
 		return interfaces
 	}
 
-	f, err := parser.ParseFile(fset, filePath, src, parser.ParseComments)
-	if err != nil {
-		return interfaces
+	// 只列出直接声明的方法，嵌入的接口不展开；输出按接口和方法在文件中的位置排序
+	infos := extractInterfaceInfos(f, fset, filePath, nil)
+	sort.SliceStable(infos, func(i, j int) bool {
+		return locationBefore(infos[i].Location, infos[j].Location)
+	})
+	for interfaceOrdinal, iface := range infos {
+		interfaceOrdinal := interfaceOrdinal
+		specs := append([]MethodSpec(nil), iface.Specs...)
+		sort.SliceStable(specs, func(i, j int) bool {
+			return locationBefore(specs[i].Location, specs[j].Location)
+		})
+		for ordinal, spec := range specs {
+			ordinal := ordinal
+			interfaces = append(interfaces, InterfaceMethod{
+				Name:             spec.Name,
+				InterfaceName:    iface.Name,
+				Location:         spec.Location,
+				EndLocation:      spec.EndLocation,
+				Anchor:           spec.anchor(&iface),
+				NameLocation:     spec.Location,
+				NameEndLocation:  spec.nameEnd,
+				TypeParams:       iface.TypeParams,
+				Ordinal:          &ordinal,
+				InterfaceOrdinal: &interfaceOrdinal,
+			})
+		}
 	}
 
-	// 遍历AST查找接口定义
-	ast.Inspect(f, func(n ast.Node) bool {
-		switch node := n.(type) {
-		case *ast.TypeSpec:
-			// 检查是否是接口类型
-			if interfaceType, ok := node.Type.(*ast.InterfaceType); ok {
-				interfaceName := node.Name.Name
-				// 遍历接口方法
-				for _, method := range interfaceType.Methods.List {
-					if len(method.Names) > 0 {
-						methodName := method.Names[0].Name
-						startPos := fset.Position(method.Pos())
-						// 不使用 method.End()，而是计算下一行的位置
-						nextLinePos := Location{
-							File:   filePath,
-							Line:   startPos.Line, // 下一行（因为我们已经减了1，所以这里不再减）
-							Column: 0,             // 行首
-						}
-						interfaces = append(interfaces, InterfaceMethod{
-							Name:          methodName,
-							InterfaceName: interfaceName,
-							Location: Location{
-								File:   filePath,
-								Line:   startPos.Line - 1,
-								Column: startPos.Column - 1,
-							},
-							// 将 CodeLens 放在方法定义的下一行
-							EndLocation: nextLinePos,
-						})
-					}
-				}
-			}
+	return interfaces
+}
 
-		}
-		return true
-	})
+func locationBefore(a, b Location) bool {
+	if a.Line != b.Line {
+		return a.Line < b.Line
+	}
+	return a.Column < b.Column
+}
 
-	return interfaces
+// 为文件中的接口方法统计实现数量。扫描范围是 --root、文件所在模块或文件所在目录，
+// 使用与 find-implementations 相同的匹配（扫描结果在 serve 模式下复用），保证两边的数量一致
+func countFileImplementations(filePath string, interfaces []InterfaceMethod) {
+	scan := scanDirectory(fileScanRoot(filePath))
+	counts := make(map[string]int)
+	for i := range interfaces {
+		name := interfaces[i].Name
+		count, ok := counts[name]
+		if !ok {
+			count = len(findImplementationsIn(scan, name))
+			counts[name] = count
+		}
+		interfaces[i].ImplementationCount = &count
+	}
 }
 
 // 分析单个文件中的方法实现
@@ -222,144 +601,157 @@ func findFileImplementations(filePath string) []Implementation {
 		return implementations
 	}
 
-	src, err := os.ReadFile(filePath)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "读取文件失败: %v\n", err)
-		return implementations
-	}
-
-	f, err := parser.ParseFile(fset, filePath, src, parser.ParseComments)
+	f, err := parseGoFile(fset, filePath)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "解析文件失败: %v\n", err)
+		reportParseError(err)
 		return implementations
 	}
 
 	// 获取文件所在目录，用于查找同目录下的所有接口
 	dir := filepath.Dir(filePath)
-	fmt.Fprintf(os.Stderr, "搜索目录: %s\n", dir)
 	allInterfaces := findAllInterfacesInDirectory(dir)
-	fmt.Fprintf(os.Stderr, "找到 %d 个接口\n", len(allInterfaces))
-	for i, methods := range allInterfaces {
-		fmt.Fprintf(os.Stderr, "接口 %d 的方法: %v\n", i+1, methods)
-	}
 	// 收集当前文件中所有类型的方法
-	typeMethods := make(map[string][]string)
-	ast.Inspect(f, func(n ast.Node) bool {
-		switch node := n.(type) {
-		case *ast.FuncDecl:
-			if node.Recv != nil {
-				methodName := node.Name.Name
-				receiverType := getReceiverType(node.Recv)
-				typeMethods[receiverType] = append(typeMethods[receiverType], methodName)
-			}
-		}
-		return true
-	})
-	fmt.Fprintf(os.Stderr, "类型方法映射: %v\n", typeMethods)
+	typeMethods := make(map[string]map[string]*MethodInfo)
+	collectTypeMethods(f, fset, typeMethods, nil)
+	types := packageTypeSpecs(filePath)
+	pkg := packageDir(filePath, f)
 
 	// 检查哪些类型完整且精确地实现了接口
-	for receiverType, methods := range typeMethods {
-		fmt.Fprintf(os.Stderr, "检查类型 %s 的方法: %v\n", receiverType, methods)
-		for i, interfaceMethods := range allInterfaces {
-			fmt.Fprintf(os.Stderr, "与接口 %d 的方法 %v 进行匹配\n", i+1, interfaceMethods)
-			if isExactMatch(methods, interfaceMethods) {
-				fmt.Fprintf(os.Stderr, "✅ 类型 %s 完全匹配接口 %d\n", receiverType, i+1)
-				// 这个类型完整且精确地实现了接口，添加其所有方法
-				ast.Inspect(f, func(n ast.Node) bool {
-					switch node := n.(type) {
-					case *ast.FuncDecl:
-						if node.Recv != nil {
-							currentReceiverType := getReceiverType(node.Recv)
-							if currentReceiverType == receiverType {
-								methodName := node.Name.Name
-								startPos := fset.Position(node.Pos())
-								endPos := fset.Position(node.End())
-
-								implementations = append(implementations, Implementation{
-									MethodName:   methodName,
-									ReceiverType: receiverType,
-									Location: Location{
-										File:   filePath,
-										Line:   startPos.Line - 1,
-										Column: startPos.Column - 1,
-									},
-									EndLocation: Location{
-										File:   filePath,
-										Line:   endPos.Line - 1,
-										Column: endPos.Column - 1,
-									},
-								})
-							}
-						}
+	for _, methods := range typeMethods {
+		var receiverType string
+		for _, info := range methods {
+			receiverType = info.ReceiverType
+		}
+		// 类型满足的全部接口，按方法名分别记录
+		satisfied := make(map[string][]string)
+		matched := false
+		for _, iface := range allInterfaces {
+			// 含未导出方法的接口只能由同包类型实现
+			if !iface.visibleTo(dir) {
+				continue
+			}
+			if isExactMatch(methods, &iface) {
+				matched = true
+				for _, name := range iface.Methods {
+					if !slices.Contains(satisfied[name], iface.Name) {
+						satisfied[name] = append(satisfied[name], iface.Name)
 					}
-					return true
-				})
-				break // 找到匹配的接口后跳出
-			} else {
-				fmt.Fprintf(os.Stderr, "❌ 类型 %s 不匹配接口 %d\n", receiverType, i+1)
+				}
 			}
 		}
+		if !matched {
+			continue
+		}
+		// 这个类型完整且精确地实现了至少一个接口，添加其所有方法
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.FuncDecl:
+				if node.Recv != nil {
+					currentReceiverType := getReceiverType(node.Recv)
+					if currentReceiverType == receiverType {
+						methodName := node.Name.Name
+						startPos := fset.Position(node.Pos())
+						endPos := fset.Position(node.End())
+						namePos := fset.Position(node.Name.Pos())
+						nameEnd := fset.Position(node.Name.End())
+						receiverName, receiverPos, receiverEnd := receiverSpan(fset, node)
+						signature := renderMethodSignature(fset, methodName, node.Type)
+						owner := ""
+						if len(satisfied[methodName]) > 0 {
+							owner = satisfied[methodName][0]
+						}
+
+						implementations = append(implementations, Implementation{
+							MethodName:   methodName,
+							ReceiverType: receiverType,
+							Kind:         typeKindOf(types, pkg, receiverType),
+							Interfaces:   satisfied[methodName],
+							Signature:    signature,
+							Interface:    owner,
+							Label:        implementationLabel(receiverType, signature),
+							Location: Location{
+								File:   filePath,
+								Line:   startPos.Line - 1,
+								Column: startPos.Column - 1,
+							},
+							EndLocation: Location{
+								File:   filePath,
+								Line:   endPos.Line - 1,
+								Column: endPos.Column - 1,
+							},
+							NameLocation: Location{
+								File:   filePath,
+								Line:   namePos.Line - 1,
+								Column: namePos.Column - 1,
+							},
+							NameEndLocation: Location{
+								File:   filePath,
+								Line:   nameEnd.Line - 1,
+								Column: nameEnd.Column - 1,
+							},
+							ReceiverName: receiverName,
+							ReceiverLocation: Location{
+								File:   filePath,
+								Line:   receiverPos.Line - 1,
+								Column: receiverPos.Column - 1,
+							},
+							ReceiverEndLocation: Location{
+								File:   filePath,
+								Line:   receiverEnd.Line - 1,
+								Column: receiverEnd.Column - 1,
+							},
+						})
+					}
+				}
+			}
+			return true
+		})
+
 	}
 
 	return implementations
 }
 
 // 查找目录中所有接口的方法列表（递归扫描子目录）
-func findAllInterfacesInDirectory(dir string) [][]string {
-	var allInterfaces [][]string
-	fmt.Fprintf(os.Stderr, "开始递归搜索目录: %s\n", dir)
+func findAllInterfacesInDirectory(dir string) []InterfaceInfo {
+	var allInterfaces []InterfaceInfo
+	var paths []string
+	limits := newWalkLimits(dir)
+	defer limits.report()
 
 	// 递归遍历目录及其子目录中的所有.go文件
-	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+	err := sourceFS.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "访问路径失败 %s: %v\n", path, err)
 			return nil // 忽略错误，继续处理其他文件
 		}
+		if info.IsDir() && !limits.enter(path) {
+			return filepath.SkipDir
+		}
 
 		// 只处理.go文件
 		if !strings.HasSuffix(path, ".go") {
 			return nil
 		}
 
-		// 跳过测试文件
-		if strings.HasSuffix(path, "_test.go") {
-			fmt.Fprintf(os.Stderr, "跳过测试文件: %s\n", path)
+		// 跳过测试文件和过大的文件
+		if strings.HasSuffix(path, "_test.go") || fileTooLarge(info.Size()) {
 			return nil
 		}
+		paths = append(paths, path)
+		return nil
+	})
 
+	// 通过符号链接重复出现的文件只解析一次
+	for _, i := range dedupeSymlinked(dir, paths) {
 		fset := token.NewFileSet()
-		src, err := os.ReadFile(path)
-		if err != nil {
-			return nil
-		}
-		fmt.Fprintf(os.Stderr, "分析文件: %s\n", path)
-
-		f, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+		f, err := parseGoFile(fset, paths[i])
 		if err != nil {
-			return nil
+			continue
 		}
 
 		// 查找接口定义
-		ast.Inspect(f, func(n ast.Node) bool {
-			switch node := n.(type) {
-			case *ast.TypeSpec:
-				if interfaceType, ok := node.Type.(*ast.InterfaceType); ok {
-					var methods []string
-					for _, method := range interfaceType.Methods.List {
-						if len(method.Names) > 0 {
-							methods = append(methods, method.Names[0].Name)
-						}
-					}
-					if len(methods) > 0 {
-						allInterfaces = append(allInterfaces, methods)
-					}
-				}
-			}
-			return true
-		})
-
-		return nil
-	})
+		allInterfaces = append(allInterfaces, extractInterfaceInfos(f, fset, paths[i], nil)...)
+	}
 
 	if err != nil {
 		// 如果递归遍历失败，回退到只扫描当前目录
@@ -375,52 +767,38 @@ func findAllInterfacesInDirectory(dir string) [][]string {
 			}
 
 			fset := token.NewFileSet()
-			src, err := os.ReadFile(file)
-			if err != nil {
-				continue
-			}
-
-			f, err := parser.ParseFile(fset, file, src, parser.ParseComments)
+			f, err := parseGoFile(fset, file)
 			if err != nil {
 				continue
 			}
 
 			// 查找接口定义
-			ast.Inspect(f, func(n ast.Node) bool {
-				switch node := n.(type) {
-				case *ast.TypeSpec:
-					if interfaceType, ok := node.Type.(*ast.InterfaceType); ok {
-						var methods []string
-						for _, method := range interfaceType.Methods.List {
-							if len(method.Names) > 0 {
-								methods = append(methods, method.Names[0].Name)
-							}
-						}
-						if len(methods) > 0 {
-							allInterfaces = append(allInterfaces, methods)
-						}
-					}
-				}
-				return true
-			})
+			allInterfaces = append(allInterfaces, extractInterfaceInfos(f, fset, file, nil)...)
 		}
 	}
 
-	return allInterfaces
+	// 展开嵌入接口后，只保留有方法的接口
+	var nonEmpty []InterfaceInfo
+	for _, iface := range flattenInterfaces(allInterfaces) {
+		if len(iface.Methods) > 0 {
+			nonEmpty = append(nonEmpty, iface)
+		}
+	}
+	return nonEmpty
 }
 
-// 检查方法列表是否完全匹配（顺序无关）
-// 修改 isExactMatch 函数
-func isExactMatch(typeMethods []string, interfaceMethods []string) bool {
-	// 创建类型方法的映射
-	typeMethodSet := make(map[string]bool)
-	for _, method := range typeMethods {
-		typeMethodSet[method] = true
+// 检查类型的方法是否完全实现了接口（顺序无关）
+// 方法名必须存在，且规范化后的签名一致
+func isExactMatch(typeMethods map[string]*MethodInfo, iface *InterfaceInfo) bool {
+	if iface.conflicting {
+		return false
 	}
-
-	// 检查接口的每个方法是否都在类型中存在
-	for _, interfaceMethod := range interfaceMethods {
-		if !typeMethodSet[interfaceMethod] {
+	for _, spec := range iface.Specs {
+		method, ok := typeMethods[spec.Name]
+		if !ok || method.hasTypeParams() {
+			return false
+		}
+		if spec.Key != "" && method.Key != "" && spec.Key != method.Key {
 			return false
 		}
 	}
@@ -428,50 +806,112 @@ func isExactMatch(typeMethods []string, interfaceMethods []string) bool {
 	return true
 }
 
+// 查询的目标接口方法
+type methodTarget struct {
+	iface      *InterfaceInfo
+	methodName string
+	score      float64
+	// 方法来自嵌入接口时的声明位置
+	declaredIn *DeclarationRef
+}
+
 // 完全重写 findImplementations 函数
 func findImplementations(directory, methodName string) []Implementation {
+	return findImplementationsIn(scanDirectory(directory), methodName)
+}
+
+// 在扫描结果中查找接口方法的实现
+func findImplementationsIn(scan *ScanResult, methodName string) []Implementation {
 	var implementations []Implementation
+	matcher := queryNameMatcher(methodName)
 
 	// 1. 首先找到包含该方法的接口
-	var targetInterface *InterfaceInfo
-	allInterfaces := findAllInterfacesWithMethods(directory)
-
-	for _, iface := range allInterfaces {
-		for _, method := range iface.Methods {
-			if method == methodName {
-				targetInterface = &iface
+	var targets []methodTarget
+	allInterfaces := scan.Interfaces
+
+	if matcher.exact() {
+		// 优先选择直接声明了该方法的接口，其次才是通过嵌入获得该方法的接口
+		for _, embedded := range []bool{false, true} {
+			for i := range allInterfaces {
+				for _, spec := range allInterfaces[i].Specs {
+					if spec.Name == methodName && spec.embedded == embedded {
+						targets = append(targets, methodTarget{iface: &allInterfaces[i], methodName: methodName, declaredIn: spec.declaration()})
+						break
+					}
+				}
+				if len(targets) > 0 {
+					break
+				}
+			}
+			if len(targets) > 0 {
 				break
 			}
 		}
-		if targetInterface != nil {
-			break
+	} else {
+		// 非精确匹配时，所有名称匹配的接口方法都是候选
+		for i := range allInterfaces {
+			for _, spec := range allInterfaces[i].Specs {
+				if score, ok := matcher.match(spec.Name); ok {
+					targets = append(targets, methodTarget{iface: &allInterfaces[i], methodName: spec.Name, score: score, declaredIn: spec.declaration()})
+				}
+			}
 		}
 	}
 
-	if targetInterface == nil {
+	if len(targets) == 0 {
 		return implementations
 	}
 
-	// 2. 收集所有类型的方法实现
-	allTypeMethods := collectAllTypeMethods(directory)
+	// 2. 所有类型的方法集
+	allTypeMethods := scan.TypeMethods
 
 	// 3. 检查每个类型是否完整且精确地实现了接口
-	for typeName, methods := range allTypeMethods {
-		methodNames := make([]string, 0, len(methods))
-		for name := range methods {
-			methodNames = append(methodNames, name)
-		}
+	seen := make(map[*MethodInfo]bool)
+	for _, target := range targets {
+		for _, methods := range allTypeMethods {
+			// 密封接口只在其所在包内匹配
+			if !target.iface.visibleTo(packageOf(methods)) {
+				continue
+			}
+
+			// 检查是否完整且精确实现
+			if !isExactMatch(methods, target.iface) {
+				continue
+			}
 
-		// 检查是否完整且精确实现
-		if isExactMatch(methodNames, targetInterface.Methods) {
 			// 只返回用户点击的特定方法的实现
-			if methodInfo, exists := methods[methodName]; exists {
-				implementations = append(implementations, Implementation{
-					MethodName:   methodName,
-					ReceiverType: typeName,
-					Location:     methodInfo.Location,
-					EndLocation:  methodInfo.EndLocation,
-				})
+			methodInfo, exists := methods[target.methodName]
+			if !exists || seen[methodInfo] {
+				continue
+			}
+			seen[methodInfo] = true
+			receiverName, receiverLocation, receiverEndLocation := methodInfo.receiver()
+			implementation := Implementation{
+				MethodName:          target.methodName,
+				ReceiverType:        methodInfo.ReceiverType,
+				Location:            methodInfo.Location,
+				EndLocation:         methodInfo.EndLocation,
+				NameLocation:        methodInfo.NameLocation,
+				NameEndLocation:     methodInfo.NameEndLocation,
+				ReceiverName:        receiverName,
+				ReceiverLocation:    receiverLocation,
+				ReceiverEndLocation: receiverEndLocation,
+				Kind:                typeKindOf(scan.Types, methodInfo.Package, methodInfo.ReceiverType),
+				PromotedFrom:        methodInfo.PromotedFrom,
+				DeclaredIn:          target.declaredIn,
+				ExternalTestPackage: inExternalTestPackage(target.iface.Package, methodInfo.Package),
+				Signature:           methodInfo.Signature(),
+				Interface:           target.iface.Name,
+				Label:               implementationLabel(methodInfo.ReceiverType, methodInfo.Signature()),
+				ImportRelation:      scan.importRelation(target.iface.Package, methodInfo.Package),
+				Module:              scan.moduleOf(methodInfo.Package),
+			}
+			if !matcher.exact() {
+				implementation.Score = target.score
+			}
+			implementations = append(implementations, implementation)
+			if onImplementation != nil {
+				onImplementation(implementation)
 			}
 		}
 	}
@@ -479,213 +919,570 @@ func findImplementations(directory, methodName string) []Implementation {
 	return implementations
 }
 
-// 接口信息结构
-type InterfaceInfo struct {
-	Name    string
-	Methods []string
+// 接口与实现类型的匹配结果
+type InterfaceMatch struct {
+	Interface *InterfaceInfo
+	// 实现类型的键（包目录:类型名）
+	TypeKey string
+	// 实现类型的全部方法
+	Methods map[string]*MethodInfo
 }
 
-// 查找所有接口及其方法
-func findAllInterfacesWithMethods(directory string) []InterfaceInfo {
-	var interfaces []InterfaceInfo
-	fset := token.NewFileSet()
-
-	err := filepath.Walk(directory, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
+// 实现了接口方法的那部分方法，按接口中的顺序排列
+func (m InterfaceMatch) ImplementedMethods() []*MethodInfo {
+	methods := make([]*MethodInfo, 0, len(m.Interface.Methods))
+	for _, name := range m.Interface.Methods {
+		if method, ok := m.Methods[name]; ok {
+			methods = append(methods, method)
 		}
+	}
+	return methods
+}
 
-		if info.IsDir() && (strings.Contains(path, "vendor") || strings.HasPrefix(info.Name(), ".")) {
-			return filepath.SkipDir
+// 所有匹配中实现接口的方法声明，按声明位置去重（提升方法与原方法是同一份代码），
+// 不包含来自嵌入接口字段、没有方法体的方法
+func uniqueImplementedMethods(matches []InterfaceMatch) []*MethodInfo {
+	var methods []*MethodInfo
+	seen := make(map[Location]bool)
+	for _, match := range matches {
+		for _, method := range match.ImplementedMethods() {
+			if method.FuncDecl == nil || seen[method.Location] {
+				continue
+			}
+			seen[method.Location] = true
+			methods = append(methods, method)
 		}
+	}
+	return methods
+}
 
-		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
-			return nil
-		}
+// 匹配目录中所有的接口和实现类型
+func matchAllImplementations(directory string) []InterfaceMatch {
+	return matchImplementations(scanDirectory(directory))
+}
 
-		src, err := os.ReadFile(path)
-		if err != nil {
-			return nil
-		}
+// 在扫描结果中匹配所有的接口和实现类型
+func matchImplementations(scan *ScanResult) []InterfaceMatch {
+	var matches []InterfaceMatch
+	allInterfaces, allTypeMethods := scan.Interfaces, scan.TypeMethods
 
-		f, err := parser.ParseFile(fset, path, src, parser.ParseComments)
-		if err != nil {
-			return nil
-		}
+	typeKeys := make([]string, 0, len(allTypeMethods))
+	for typeKey := range allTypeMethods {
+		typeKeys = append(typeKeys, typeKey)
+	}
+	sort.Strings(typeKeys)
 
-		ast.Inspect(f, func(n ast.Node) bool {
-			switch node := n.(type) {
-			case *ast.TypeSpec:
-				if interfaceType, ok := node.Type.(*ast.InterfaceType); ok {
-					interfaceName := node.Name.Name
-					var methods []string
-					for _, method := range interfaceType.Methods.List {
-						if len(method.Names) > 0 {
-							methods = append(methods, method.Names[0].Name)
-						}
-					}
-					interfaces = append(interfaces, InterfaceInfo{
-						Name:    interfaceName,
-						Methods: methods,
-					})
-				}
+	for i := range allInterfaces {
+		iface := &allInterfaces[i]
+		if len(iface.Methods) == 0 {
+			continue
+		}
+		for _, typeKey := range typeKeys {
+			methods := allTypeMethods[typeKey]
+			if !iface.visibleTo(packageOf(methods)) {
+				continue
 			}
-			return true
-		})
+			// 值类型已经实现时，指针类型必然也实现，只报告值类型
+			if valueMethods, ok := allTypeMethods[strings.Replace(typeKey, ":*", ":", 1)]; ok && strings.Contains(typeKey, ":*") && isExactMatch(valueMethods, iface) {
+				continue
+			}
+			if isExactMatch(methods, iface) {
+				matches = append(matches, InterfaceMatch{Interface: iface, TypeKey: typeKey, Methods: methods})
+			}
+		}
+	}
 
-		return nil
-	})
+	return matches
+}
 
-	if err != nil {
-		fmt.Printf("查找接口时出错: %v\n", err)
+// 方法集所属的包目录
+func packageOf(methods map[string]*MethodInfo) string {
+	for _, method := range methods {
+		return method.Package
 	}
-
-	return interfaces
+	return ""
 }
 
-// 收集所有类型的方法
-func collectAllTypeMethods(directory string) map[string]map[string]*MethodInfo {
-	allTypeMethods := make(map[string]map[string]*MethodInfo)
-	fset := token.NewFileSet()
+// 接口信息结构
+type InterfaceInfo struct {
+	Name    string
+	Methods []string
+	// 接口所在的包目录和包名
+	Package     string
+	PackageName string
+	Location    Location
+	// 类型声明（TypeSpec）的结束位置，分组声明中不包含其他类型
+	EndLocation Location
+	// 文档注释
+	Doc string
+	// 方法规格（包含签名），与 Methods 一一对应
+	Specs []MethodSpec
+	// 嵌入的接口引用，展开后其方法会合并进 Methods
+	Embeds []EmbedRef
+	// 展开嵌入后同名方法的签名冲突（编译错误），任何类型都不能实现它
+	conflicting bool
+	// 泛型接口的类型参数
+	TypeParams []TypeParam
+}
 
-	err := filepath.Walk(directory, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
+// 接口方法规格
+type MethodSpec struct {
+	Name string
+	// 按源码渲染的签名，例如 AddToken(token string) error
+	Signature string
+	// 规范化签名，用于和实现方法比较
+	Key         string
+	Location    Location
+	EndLocation Location
+	// 方法名标识符的结束位置（Location 即方法名的起始位置）
+	nameEnd Location
+	// 方法声明的起始位置，有文档注释时为注释的开头
+	declStart Location
+	// 方法的函数类型节点
+	Func *ast.FuncType
+	// 声明该方法的接口名，展开嵌入后仍指向原接口
+	DeclaredIn string
+	// 是否来自嵌入的接口
+	embedded bool
+	// 签名中引用的其他包（按声明接口的文件中的导入别名）
+	Imports []SignatureImport
+	// 声明该方法的接口所在的包目录和包名，签名中未限定的类型属于这个包
+	pkgDir  string
+	pkgName string
+}
 
-		if info.IsDir() && (strings.Contains(path, "vendor") || strings.HasPrefix(info.Name(), ".")) {
-			return filepath.SkipDir
-		}
+// CodeLens 锚点（--lens-anchor）：
+// method-line（默认）为方法名所在位置；above-method 为方法声明（含文档注释）上一行的行首；
+// interface-line 为接口名所在位置。通过嵌入获得的方法不在该接口中声明，锚点总是接口名
+func (spec MethodSpec) anchor(iface *InterfaceInfo) Location {
+	if spec.embedded {
+		return iface.Location
+	}
+	switch options.String("lens-anchor", "method-line") {
+	case "above-method":
+		return Location{File: spec.declStart.File, Line: max(spec.declStart.Line-1, 0)}
+	case "interface-line":
+		return iface.Location
+	}
+	return spec.Location
+}
 
-		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
-			return nil
-		}
+// 来自嵌入接口的方法返回其声明位置，直接声明的方法返回 nil
+func (spec MethodSpec) declaration() *DeclarationRef {
+	if !spec.embedded {
+		return nil
+	}
+	return &DeclarationRef{InterfaceName: spec.DeclaredIn, Location: spec.Location}
+}
 
-		src, err := os.ReadFile(path)
-		if err != nil {
-			return nil
-		}
+// 接口中嵌入的其他接口
+type EmbedRef struct {
+	Name string
+	// 限定符对应的导入路径，同包嵌入时为空
+	ImportPath string
+	// 嵌入项在接口中的位置（例如 io.Reader 这一行）
+	Location Location
+}
 
-		f, err := parser.ParseFile(fset, path, src, parser.ParseComments)
-		if err != nil {
-			return nil
+// 接口中包含未导出方法时，只有同一个包内的类型才能实现它
+func (iface *InterfaceInfo) Sealed() bool {
+	for _, method := range iface.Methods {
+		if !ast.IsExported(method) {
+			return true
 		}
+	}
+	return false
+}
 
-		collectTypeMethods(f, fset, allTypeMethods)
+// 判断某个包中的类型是否可能实现该接口
+func (iface *InterfaceInfo) visibleTo(pkg string) bool {
+	// 外部测试包中定义的接口只和同一测试包中的类型匹配
+	if strings.HasSuffix(iface.PackageName, "_test") {
+		return iface.Package == pkg
+	}
+	return !iface.Sealed() || iface.Package == pkg
+}
 
-		return nil
-	})
+// 接口名标识符的结束位置（Location 即接口名的起始位置）
+func (iface *InterfaceInfo) nameEnd() Location {
+	end := iface.Location
+	end.Column += len(iface.Name)
+	return end
+}
 
-	if err != nil {
-		fmt.Printf("收集方法时出错: %v\n", err)
+// 按名称查找方法规格，没有时返回 nil
+func (iface *InterfaceInfo) spec(name string) *MethodSpec {
+	for i := range iface.Specs {
+		if iface.Specs[i].Name == name {
+			return &iface.Specs[i]
+		}
 	}
+	return nil
+}
 
-	return allTypeMethods
+func (iface *InterfaceInfo) hasMethod(name string) bool {
+	for _, method := range iface.Methods {
+		if method == name {
+			return true
+		}
+	}
+	return false
 }
 
-// 方法信息结构
-type MethodInfo struct {
-	Location    Location
-	EndLocation Location
-	FuncDecl    *ast.FuncDecl
+func (iface *InterfaceInfo) addMethod(spec MethodSpec) {
+	iface.Methods = append(iface.Methods, spec.Name)
+	iface.Specs = append(iface.Specs, spec)
 }
 
-// 收集类型的所有方法
-func collectTypeMethods(f *ast.File, fset *token.FileSet, allTypeMethods map[string]map[string]*MethodInfo) {
+// 从单个文件的AST中提取所有接口定义
+func extractInterfaceInfos(f *ast.File, fset *token.FileSet, path string, interner stringInterner) []InterfaceInfo {
+	var interfaces []InterfaceInfo
+	qualifier := newTypeQualifier(f)
+	// TypeSpec 所在的 type 声明（可能是 type ( ... ) 分组）
+	var decl *ast.GenDecl
+
 	ast.Inspect(f, func(n ast.Node) bool {
 		switch node := n.(type) {
-		case *ast.FuncDecl:
-			if node.Recv != nil {
-				receiverType := getReceiverType(node.Recv)
-				if allTypeMethods[receiverType] == nil {
-					allTypeMethods[receiverType] = make(map[string]*MethodInfo)
-				}
-
+		case *ast.GenDecl:
+			decl = node
+		case *ast.TypeSpec:
+			if interfaceType, ok := node.Type.(*ast.InterfaceType); ok {
 				pos := fset.Position(node.Pos())
 				endPos := fset.Position(node.End())
-
-				allTypeMethods[receiverType][node.Name.Name] = &MethodInfo{
+				info := InterfaceInfo{
+					Name:        interner.intern(node.Name.Name),
+					Package:     interner.intern(packageDir(path, f)),
+					PackageName: interner.intern(f.Name.Name),
 					Location: Location{
-						File:   pos.Filename,
-						Line:   pos.Line,
-						Column: pos.Column,
+						File:   path,
+						Line:   pos.Line - 1,
+						Column: pos.Column - 1,
 					},
 					EndLocation: Location{
-						File:   endPos.Filename,
-						Line:   endPos.Line,
+						File:   path,
+						Line:   endPos.Line - 1,
 						Column: endPos.Column - 1,
 					},
-					FuncDecl: node,
+					Doc: typeSpecDoc(node, decl),
 				}
+				info.TypeParams = renderTypeParams(fset, node.TypeParams)
+				q := qualifier.withTypeParams(node.TypeParams)
+				for _, method := range interfaceType.Methods.List {
+					if len(method.Names) == 0 {
+						// 嵌入的接口
+						if embed, ok := embedRefOf(method.Type, q); ok {
+							embedPos := fset.Position(method.Type.Pos())
+							embed.Location = Location{
+								File:   path,
+								Line:   embedPos.Line - 1,
+								Column: embedPos.Column - 1,
+							}
+							info.Embeds = append(info.Embeds, embed)
+						}
+						continue
+					}
+					funcType, ok := method.Type.(*ast.FuncType)
+					if !ok {
+						continue
+					}
+					methodPos := fset.Position(method.Pos())
+					methodEnd := fset.Position(method.End())
+					nameEnd := fset.Position(method.Names[0].End())
+					declPos := methodPos
+					if method.Doc != nil {
+						declPos = fset.Position(method.Doc.Pos())
+					}
+					info.addMethod(MethodSpec{
+						Name:       interner.intern(method.Names[0].Name),
+						Signature:  renderMethodSignature(fset, method.Names[0].Name, funcType),
+						Key:        q.signatureKey(funcType),
+						Func:       funcType,
+						DeclaredIn: info.Name,
+						Imports:    q.signatureImports(funcType),
+						pkgDir:     info.Package,
+						pkgName:    info.PackageName,
+						Location: Location{
+							File:   path,
+							Line:   methodPos.Line - 1,
+							Column: methodPos.Column - 1,
+						},
+						EndLocation: Location{
+							File:   path,
+							Line:   methodEnd.Line - 1,
+							Column: methodEnd.Column - 1,
+						},
+						nameEnd: Location{
+							File:   path,
+							Line:   nameEnd.Line - 1,
+							Column: nameEnd.Column - 1,
+						},
+						declStart: Location{
+							File:   path,
+							Line:   declPos.Line - 1,
+							Column: declPos.Column - 1,
+						},
+					})
+				}
+				interfaces = append(interfaces, info)
 			}
 		}
 		return true
 	})
+	return interfaces
 }
 
-func findInterfaces(directory, methodName string) []InterfaceMethod {
-	var interfaces []InterfaceMethod
-	fset := token.NewFileSet()
+// 类型声明的文档注释：优先使用 TypeSpec 自己的注释；
+// 只有声明中只有这一个类型时，才使用写在 type 关键字上的注释（分组声明的注释不属于其中任何一个类型）
+func typeSpecDoc(spec *ast.TypeSpec, decl *ast.GenDecl) string {
+	if spec.Doc != nil {
+		return spec.Doc.Text()
+	}
+	if decl != nil && decl.Doc != nil && len(decl.Specs) == 1 && decl.Specs[0] == spec {
+		return decl.Doc.Text()
+	}
+	return ""
+}
 
-	err := filepath.Walk(directory, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
+// 解析接口中的嵌入项，只处理 Name 和 pkg.Name 两种形式
+func embedRefOf(expr ast.Expr, q *typeQualifier) (EmbedRef, bool) {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return EmbedRef{Name: t.Name}, true
+	case *ast.SelectorExpr:
+		if x, ok := t.X.(*ast.Ident); ok {
+			path, ok := q.importPath[x.Name]
+			if !ok {
+				path = x.Name
+			}
+			return EmbedRef{Name: t.Sel.Name, ImportPath: path}, true
 		}
+	}
+	return EmbedRef{}, false
+}
 
-		// 跳过vendor目录和隐藏目录
-		if info.IsDir() && (strings.Contains(path, "vendor") || strings.HasPrefix(info.Name(), ".")) {
-			return filepath.SkipDir
+// 查找所有接口及其方法
+func findAllInterfacesWithMethods(directory string) []InterfaceInfo {
+	var interfaces []InterfaceInfo
+
+	err := walkGoFiles(directory, func(path string, f *ast.File, fset *token.FileSet) {
+		interfaces = append(interfaces, extractInterfaceInfos(f, fset, path, nil)...)
+	})
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "查找接口时出错: %v\n", err)
+	}
+
+	return flattenInterfaces(interfaces)
+}
+
+// 方法信息结构
+type MethodInfo struct {
+	Location    Location
+	EndLocation Location
+	// 方法名标识符的范围
+	NameLocation    Location
+	NameEndLocation Location
+	FuncDecl        *ast.FuncDecl
+	Name            string
+	ReceiverType    string
+	// 方法所在的包目录
+	Package string
+	// 规范化签名，用于和接口方法比较
+	Key string
+	// 通过嵌入字段提升而来时，原始声明所在的接收者类型
+	PromotedFrom string
+	// 解析 FuncDecl 时使用的文件集，用于定位方法体中的语句
+	fset *token.FileSet
+	// 从嵌入接口提升的方法没有 FuncDecl，保留接口中的签名
+	signature string
+}
+
+// 方法是否声明了自己的类型参数（语法上可以解析，但不合法，也不能实现任何接口）
+func (m *MethodInfo) hasTypeParams() bool {
+	return m.FuncDecl != nil && m.FuncDecl.Type.TypeParams != nil && m.FuncDecl.Type.TypeParams.NumFields() > 0
+}
+
+// 接收者变量名（匿名和 _ 接收者为空）及接收者字段的范围。只在输出时从 FuncDecl 计算，
+// 被过滤掉的方法不必保存；从嵌入接口提升的方法没有接收者
+func (m *MethodInfo) receiver() (string, Location, Location) {
+	if m.FuncDecl == nil || m.fset == nil {
+		return "", Location{}, Location{}
+	}
+	name, pos, end := receiverSpan(m.fset, m.FuncDecl)
+	return name,
+		Location{File: pos.Filename, Line: pos.Line, Column: pos.Column, oneBased: true},
+		Location{File: end.Filename, Line: end.Line, Column: end.Column, oneBased: true}
+}
+
+// 按源码渲染的方法签名，例如 Get(key string) (string, error)
+func (m *MethodInfo) Signature() string {
+	if m.FuncDecl != nil && m.fset != nil {
+		return renderMethodSignature(m.fset, m.Name, m.FuncDecl.Type)
+	}
+	return m.signature
+}
+
+// 收集类型的所有方法
+func collectTypeMethods(f *ast.File, fset *token.FileSet, allTypeMethods map[string]map[string]*MethodInfo, interner stringInterner) {
+	qualifier := newTypeQualifier(f)
+	// 不同包中的同名类型需要区分开；同一文件中的方法都属于同一个包目录
+	pkg := interner.intern(packageDir(fset.Position(f.Package).Filename, f))
+	// 类型键和签名先写入同一块缓冲区再驻留，重复出现时不再分配
+	var buf []byte
+
+	// 带接收者的函数只能是顶层声明，无需遍历整个 AST
+	for _, decl := range f.Decls {
+		node, ok := decl.(*ast.FuncDecl)
+		if !ok || node.Recv == nil {
+			continue
 		}
 
-		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
-			return nil
+		receiverType := interner.intern(getReceiverType(node.Recv))
+		pos := fset.Position(node.Pos())
+		endPos := fset.Position(node.End())
+		namePos := fset.Position(node.Name.Pos())
+		nameEnd := fset.Position(node.Name.End())
+
+		buf = append(append(append(buf[:0], pkg...), ':'), receiverType...)
+		typeKey := interner.internBytes(buf)
+		methods := allTypeMethods[typeKey]
+		if methods == nil {
+			methods = make(map[string]*MethodInfo)
+			allTypeMethods[typeKey] = methods
 		}
 
-		src, err := os.ReadFile(path)
-		if err != nil {
-			return nil
+		name := interner.intern(node.Name.Name)
+		buf = qualifier.withTypeParams(receiverTypeParams(node.Recv)).appendSignatureKey(buf[:0], node.Type)
+		key := interner.internBytes(buf)
+		methods[name] = &MethodInfo{
+			Location: Location{
+				File:     pos.Filename,
+				Line:     pos.Line,
+				Column:   pos.Column,
+				oneBased: true,
+			},
+			EndLocation: Location{
+				File:     endPos.Filename,
+				Line:     endPos.Line,
+				Column:   endPos.Column - 1,
+				oneBased: true,
+			},
+			NameLocation: Location{
+				File:     namePos.Filename,
+				Line:     namePos.Line,
+				Column:   namePos.Column,
+				oneBased: true,
+			},
+			NameEndLocation: Location{
+				File:     nameEnd.Filename,
+				Line:     nameEnd.Line,
+				Column:   nameEnd.Column,
+				oneBased: true,
+			},
+			FuncDecl:     node,
+			Name:         name,
+			ReceiverType: receiverType,
+			Package:      pkg,
+			Key:          key,
+			fset:         fset,
 		}
+	}
+}
 
-		f, err := parser.ParseFile(fset, path, src, parser.ParseComments)
-		if err != nil {
-			return nil
+func findInterfaces(directory, methodName string) []InterfaceMethod {
+	var interfaces []InterfaceMethod
+	matcher := queryNameMatcher(methodName)
+
+	// 展开嵌入后，通过嵌入获得该方法的接口也会列出，位置指向该接口，declaredIn 指向真正的声明
+	for _, iface := range findAllInterfacesWithMethods(directory) {
+		for _, spec := range iface.Specs {
+			score, ok := matcher.match(spec.Name)
+			if !ok {
+				continue
+			}
+			interfaceMethod := InterfaceMethod{
+				Name:            spec.Name,
+				InterfaceName:   iface.Name,
+				Location:        spec.Location,
+				EndLocation:     spec.EndLocation,
+				Anchor:          spec.anchor(&iface),
+				NameLocation:    spec.Location,
+				NameEndLocation: spec.nameEnd,
+				DeclaredIn:      spec.declaration(),
+			}
+			if spec.embedded {
+				interfaceMethod.Location = iface.Location
+				interfaceMethod.EndLocation = iface.Location
+				interfaceMethod.NameLocation = iface.Location
+				interfaceMethod.NameEndLocation = iface.nameEnd()
+			}
+			if !matcher.exact() {
+				interfaceMethod.Score = score
+			}
+			interfaces = append(interfaces, interfaceMethod)
 		}
+	}
 
-		// 遍历AST查找接口定义
-		ast.Inspect(f, func(n ast.Node) bool {
-			switch node := n.(type) {
-			case *ast.TypeSpec:
-				// 检查是否是接口类型
-				if interfaceType, ok := node.Type.(*ast.InterfaceType); ok {
-					interfaceName := node.Name.Name
-					// 遍历接口方法
-					for _, method := range interfaceType.Methods.List {
-						if len(method.Names) > 0 && method.Names[0].Name == methodName {
-							pos := fset.Position(method.Pos())
-							interfaces = append(interfaces, InterfaceMethod{
-								Name:          methodName,
-								InterfaceName: interfaceName,
-								Location: Location{
-									File:   path,
-									Line:   pos.Line - 1,
-									Column: pos.Column - 1,
-								},
-							})
-						}
-					}
-				}
+	return interfaces
+}
+
+// 方法的接收者变量名和接收者字段的起止位置；匿名接收者 (*T) 和 _ 接收者的变量名为空
+func receiverSpan(fset *token.FileSet, decl *ast.FuncDecl) (string, token.Position, token.Position) {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return "", token.Position{}, token.Position{}
+	}
+	field := decl.Recv.List[0]
+	name := ""
+	if len(field.Names) > 0 && field.Names[0].Name != "_" {
+		name = field.Names[0].Name
+	}
+	return name, fset.Position(field.Pos()), fset.Position(field.End())
+}
+
+// 去掉接收者类型外层的括号和指针：(T)、*(T)、(*T) 都是合法写法，生成的代码中很常见；
+// 指针接收者返回前缀 "*"
+func receiverBase(expr ast.Expr) (ast.Expr, string) {
+	prefix := ""
+	for {
+		switch t := expr.(type) {
+		case *ast.ParenExpr:
+			expr = t.X
+			continue
+		case *ast.StarExpr:
+			if prefix == "" {
+				expr, prefix = t.X, "*"
+				continue
 			}
-			return true
-		})
+		}
+		return expr, prefix
+	}
+}
 
+// 泛型接收者 (s *Store[K, V]) 中的类型参数；不是泛型接收者时返回 nil
+func receiverTypeParams(recv *ast.FieldList) *ast.FieldList {
+	if recv == nil || len(recv.List) == 0 {
 		return nil
-	})
-
-	if err != nil {
-		fmt.Printf("Error walking directory: %v\n", err)
 	}
 
-	return interfaces
+	expr, _ := receiverBase(recv.List[0].Type)
+
+	var indices []ast.Expr
+	switch t := expr.(type) {
+	case *ast.IndexExpr:
+		indices = []ast.Expr{t.Index}
+	case *ast.IndexListExpr:
+		indices = t.Indices
+	}
+	if len(indices) == 0 {
+		return nil
+	}
+	params := &ast.FieldList{}
+	for _, index := range indices {
+		if ident, ok := index.(*ast.Ident); ok {
+			params.List = append(params.List, &ast.Field{Names: []*ast.Ident{ident}})
+		}
+	}
+	return params
 }
 
 func getReceiverType(recv *ast.FieldList) string {
@@ -693,14 +1490,16 @@ func getReceiverType(recv *ast.FieldList) string {
 		return ""
 	}
 
-	field := recv.List[0]
-	switch t := field.Type.(type) {
-	case *ast.Ident:
-		return t.Name
-	case *ast.StarExpr:
-		if ident, ok := t.X.(*ast.Ident); ok {
-			return "*" + ident.Name
-		}
+	expr, prefix := receiverBase(recv.List[0].Type)
+	// 泛型接收者 Store[K, V] 按类型名 Store 归类
+	switch t := expr.(type) {
+	case *ast.IndexExpr:
+		expr = t.X
+	case *ast.IndexListExpr:
+		expr = t.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return prefix + ident.Name
 	}
 	return ""
 }
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// 目录级命令附带的分析元数据
+type AnalysisMeta struct {
+	Module     string `json:"module"`
+	GoVersion  string `json:"goVersion"`
+	Packages   int    `json:"packages"`
+	Files      int    `json:"files"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// 本次运行的扫描统计
+var scanStats = struct {
+	directory string
+	started   time.Time
+	files     map[string]bool
+	packages  map[string]bool
+}{
+	files:    make(map[string]bool),
+	packages: make(map[string]bool),
+}
+
+// 记录命令开始时间；目标是目录时才会输出元数据
+func startMeta(target string) {
+	scanStats.directory = ""
+	scanStats.files = make(map[string]bool)
+	scanStats.packages = make(map[string]bool)
+	scanStats.started = time.Now()
+	resetDiagnostics()
+	if info, err := os.Stat(target); err == nil && info.IsDir() {
+		scanStats.directory = target
+	}
+}
+
+// 记录一个被解析的文件（多次遍历同一文件只计一次）
+func recordScannedFile(path string) {
+	scanStats.files[path] = true
+	scanStats.packages[filepath.Dir(path)] = true
+}
+
+func collectMeta() AnalysisMeta {
+	meta := AnalysisMeta{
+		Packages:   len(scanStats.packages),
+		Files:      len(scanStats.files),
+		DurationMs: time.Since(scanStats.started).Milliseconds(),
+	}
+	if root := findModuleRoot(scanStats.directory); root != "" {
+		module := parseGoMod(filepath.Join(root, "go.mod"))
+		meta.Module = module.Path
+		meta.GoVersion = module.GoVersion
+	}
+	return meta
+}
+
+// 在 JSON 对象结果中追加 meta 字段
+func appendMeta(output []byte) []byte {
+	if scanStats.directory == "" {
+		return output
+	}
+	return appendJSONField(output, "meta", collectMeta())
+}
+
+// 在 JSON 对象末尾追加一个字段，结果不是对象或字段编码失败时原样返回
+func appendJSONField(output []byte, name string, value interface{}) []byte {
+	if len(output) < 2 || output[0] != '{' {
+		return output
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return output
+	}
+
+	result := append([]byte{}, output[:len(output)-1]...)
+	if len(output) > 2 {
+		result = append(result, ',')
+	}
+	result = append(result, '"')
+	result = append(result, name...)
+	result = append(result, `":`...)
+	result = append(result, encoded...)
+	return append(result, '}')
+}
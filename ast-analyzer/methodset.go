@@ -0,0 +1,420 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"strings"
+)
+
+// 类型定义信息
+type TypeInfo struct {
+	Name        string
+	Package     string
+	PackageName string
+	Location    Location
+	// 类型定义的形式：struct、interface、func、alias、named（以其他具名类型定义）或 other
+	Kind string
+	// 以其他具名类型定义时，底层类型的引用
+	Underlying *FieldEmbed
+	// 结构体中的嵌入字段
+	Embeds []FieldEmbed
+	// 泛型类型的类型参数
+	TypeParams []TypeParam
+	// 嵌入字段的解析上下文
+	qualifier *typeQualifier
+	// 结构体字段的类型表达式，嵌入字段以类型名为字段名
+	fields map[string]ast.Expr
+}
+
+// 结构体的嵌入字段，例如 Base、*Base、pkg.Base
+type FieldEmbed struct {
+	Name       string
+	ImportPath string
+	Pointer    bool
+}
+
+// 收集文件中的类型定义，键为 包目录:类型名
+func collectTypeSpecs(f *ast.File, fset *token.FileSet, path string, types map[string]*TypeInfo) {
+	qualifier := newTypeQualifier(f)
+	pkg := packageDir(path, f)
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		spec, ok := n.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+
+		pos := fset.Position(spec.Pos())
+		info := &TypeInfo{
+			Name:        spec.Name.Name,
+			Package:     pkg,
+			PackageName: f.Name.Name,
+			Location: Location{
+				File:   path,
+				Line:   pos.Line - 1,
+				Column: pos.Column - 1,
+			},
+			TypeParams: renderTypeParams(fset, spec.TypeParams),
+			qualifier:  qualifier,
+		}
+
+		switch t := spec.Type.(type) {
+		case *ast.StructType:
+			info.Kind = "struct"
+			info.fields = make(map[string]ast.Expr)
+			for _, field := range t.Fields.List {
+				for _, name := range field.Names {
+					info.fields[name.Name] = field.Type
+				}
+				if len(field.Names) > 0 {
+					continue
+				}
+				if embed, ok := fieldEmbedOf(field.Type, qualifier); ok {
+					info.Embeds = append(info.Embeds, embed)
+					info.fields[embed.Name] = field.Type
+				}
+			}
+		case *ast.InterfaceType:
+			info.Kind = "interface"
+		case *ast.FuncType:
+			info.Kind = "func"
+		case *ast.Ident, *ast.SelectorExpr:
+			info.Kind = "named"
+			if underlying, ok := fieldEmbedOf(t, qualifier); ok {
+				info.Underlying = &underlying
+			}
+		default:
+			info.Kind = "other"
+		}
+		if spec.Assign.IsValid() {
+			info.Kind = "alias"
+		}
+
+		types[pkg+":"+info.Name] = info
+		return true
+	})
+}
+
+func fieldEmbedOf(expr ast.Expr, q *typeQualifier) (FieldEmbed, bool) {
+	pointer := false
+	if star, ok := expr.(*ast.StarExpr); ok {
+		pointer = true
+		expr = star.X
+	}
+
+	ref, ok := embedRefOf(expr, q)
+	if !ok {
+		return FieldEmbed{}, false
+	}
+	return FieldEmbed{Name: ref.Name, ImportPath: ref.ImportPath, Pointer: pointer}, true
+}
+
+// 按源码中的写法渲染嵌入字段，例如 *Base、io.Reader
+func (e FieldEmbed) String() string {
+	name := e.Name
+	if e.ImportPath != "" {
+		name = importPackageName(e.ImportPath) + "." + name
+	}
+	if e.Pointer {
+		name = "*" + name
+	}
+	return name
+}
+
+// 方法集中的一项，depth 为提升的深度（直接声明为 0）
+// sources 为提供该方法的嵌入字段（直接声明时为空字符串）
+type methodSetEntry struct {
+	info      *MethodInfo
+	depth     int
+	ambiguous bool
+	sources   []string
+}
+
+type methodSet map[string]*methodSetEntry
+
+// 加入方法：浅层的方法覆盖深层的同名方法，同一深度的不同方法互相冲突
+func (set methodSet) add(name string, info *MethodInfo, depth int, source string) {
+	existing, ok := set[name]
+	switch {
+	case !ok || depth < existing.depth:
+		set[name] = &methodSetEntry{info: info, depth: depth, sources: []string{source}}
+	case depth == existing.depth && existing.info.Location != info.Location:
+		existing.ambiguous = true
+		existing.sources = append(existing.sources, source)
+	}
+}
+
+// 方法集构建器：按 Go 的规则计算值类型和指针类型的方法集，包括嵌入字段提升的方法
+type methodSetBuilder struct {
+	typeMethods map[string]map[string]*MethodInfo
+	types       map[string]*TypeInfo
+	resolver    *interfaceResolver
+	valueSets   map[string]methodSet
+	pointerSets map[string]methodSet
+	visiting    map[string]bool
+	// 提升方法的副本，键为 外层类型|方法所在位置
+	promoted map[string]*MethodInfo
+}
+
+// 在声明的方法基础上计算完整方法集，直接写回 typeMethods：
+// 包目录:T 为值方法集，包目录:*T 为指针方法集。
+// 返回因同一深度的多个嵌入字段提供同名方法而有歧义、没有被提升的方法：类型键 -> 方法名 -> 嵌入字段
+func buildMethodSets(typeMethods map[string]map[string]*MethodInfo, types map[string]*TypeInfo, interfaces []InterfaceInfo) map[string]map[string][]string {
+	b := &methodSetBuilder{
+		typeMethods: typeMethods,
+		types:       types,
+		resolver:    newInterfaceResolver(),
+		valueSets:   make(map[string]methodSet),
+		pointerSets: make(map[string]methodSet),
+		visiting:    make(map[string]bool),
+		promoted:    make(map[string]*MethodInfo),
+	}
+	for i := range interfaces {
+		b.resolver.add(&interfaces[i])
+	}
+
+	// 所有具名类型：有类型定义的，以及只出现在接收者中的
+	baseKeys := make(map[string]bool)
+	for key := range types {
+		baseKeys[key] = true
+	}
+	for key := range typeMethods {
+		baseKeys[strings.Replace(key, ":*", ":", 1)] = true
+	}
+
+	ambiguous := make(map[string]map[string][]string)
+	for key := range baseKeys {
+		pkg, name := splitTypeKey(key)
+		// 接口以及以接口为底层类型的具名类型不是具体类型，方法集为空
+		if b.isInterfaceType(key, 0) {
+			delete(typeMethods, pkg+":"+name)
+			delete(typeMethods, pkg+":*"+name)
+			continue
+		}
+		value, pointer := b.sets(key)
+		b.store(pkg+":"+name, value)
+		b.store(pkg+":*"+name, pointer)
+
+		// 指针方法集包含值方法集，只需检查它
+		for methodName, entry := range pointer {
+			if !entry.ambiguous {
+				continue
+			}
+			if ambiguous[key] == nil {
+				ambiguous[key] = make(map[string][]string)
+			}
+			ambiguous[key][methodName] = entry.sources
+		}
+	}
+	return ambiguous
+}
+
+// 判断类型的底层类型是否为接口，例如 type MyHandler Handler
+func (b *methodSetBuilder) isInterfaceType(key string, depth int) bool {
+	info, ok := b.types[key]
+	if !ok || depth > 10 {
+		return false
+	}
+	switch {
+	case info.Kind == "interface":
+		return true
+	case info.Underlying == nil:
+		return false
+	case b.embeddedInterface(info, *info.Underlying) != nil:
+		return true
+	}
+	underlyingKey := b.resolveType(info, *info.Underlying)
+	return underlyingKey != "" && b.isInterfaceType(underlyingKey, depth+1)
+}
+
+func splitTypeKey(key string) (string, string) {
+	i := strings.LastIndex(key, ":")
+	return key[:i], key[i+1:]
+}
+
+func (b *methodSetBuilder) store(key string, set methodSet) {
+	methods := make(map[string]*MethodInfo)
+	for name, entry := range set {
+		if !entry.ambiguous {
+			methods[name] = entry.info
+		}
+	}
+	if len(methods) == 0 {
+		delete(b.typeMethods, key)
+		return
+	}
+	b.typeMethods[key] = methods
+}
+
+// 计算具名类型（键为 包目录:类型名）的值方法集和指针方法集
+func (b *methodSetBuilder) sets(key string) (methodSet, methodSet) {
+	if value, ok := b.valueSets[key]; ok {
+		return value, b.pointerSets[key]
+	}
+
+	value, pointer := make(methodSet), make(methodSet)
+	if b.visiting[key] {
+		// 非法的循环嵌入，不再继续展开
+		return value, pointer
+	}
+	b.visiting[key] = true
+	defer delete(b.visiting, key)
+
+	pkg, name := splitTypeKey(key)
+
+	// 直接声明的方法优先级最高
+	for methodName, info := range b.typeMethods[pkg+":"+name] {
+		value.add(methodName, info, 0, "")
+		pointer.add(methodName, info, 0, "")
+	}
+	for methodName, info := range b.typeMethods[pkg+":*"+name] {
+		pointer.add(methodName, info, 0, "")
+	}
+
+	if typeInfo, ok := b.types[key]; ok {
+		for _, embed := range typeInfo.Embeds {
+			b.promote(typeInfo, embed, value, pointer)
+		}
+	}
+
+	b.valueSets[key] = value
+	b.pointerSets[key] = pointer
+	return value, pointer
+}
+
+// 把嵌入字段的方法提升到外层类型
+func (b *methodSetBuilder) promote(outer *TypeInfo, embed FieldEmbed, value, pointer methodSet) {
+	// 嵌入的是接口：接口的方法全部提升
+	if iface := b.embeddedInterface(outer, embed); iface != nil {
+		for _, spec := range iface.Specs {
+			info := b.promotedCopy(outer, interfaceMethodInfo(iface, spec))
+			value.add(spec.Name, info, 1, embed.String())
+			pointer.add(spec.Name, info, 1, embed.String())
+		}
+		return
+	}
+	// 嵌入接口指针（*SomeInterface）是编译错误：嵌入字段只能是 T 或指向非接口类型的 *T，
+	// 不提升任何方法，只报告出来
+	if embed.Pointer {
+		if iface := b.embeddedInterface(outer, FieldEmbed{Name: embed.Name, ImportPath: embed.ImportPath}); iface != nil {
+			reportDiagnostic(Diagnostic{
+				Kind:       "invalidEmbed",
+				Message:    "type " + outer.PackageName + "." + outer.Name + ": embedded field *" + iface.PackageName + "." + iface.Name + " is a pointer to an interface",
+				Interfaces: []string{iface.PackageName + "." + iface.Name},
+				Location:   outer.Location,
+			})
+			return
+		}
+	}
+
+	embeddedKey := b.resolveType(outer, embed)
+	if embeddedKey == "" {
+		return
+	}
+	embeddedValue, embeddedPointer := b.sets(embeddedKey)
+
+	// 值类型的方法集只包含嵌入值的值方法，嵌入指针时包含全部方法
+	fromValue := embeddedValue
+	if embed.Pointer {
+		fromValue = embeddedPointer
+	}
+	for name, entry := range fromValue {
+		if !entry.ambiguous {
+			value.add(name, b.promotedCopy(outer, entry.info), entry.depth+1, embed.String())
+		}
+	}
+	for name, entry := range embeddedPointer {
+		if !entry.ambiguous {
+			pointer.add(name, b.promotedCopy(outer, entry.info), entry.depth+1, embed.String())
+		}
+	}
+}
+
+// 提升方法的副本：位置仍指向原始声明，所属类型和包换成外层类型
+func (b *methodSetBuilder) promotedCopy(outer *TypeInfo, info *MethodInfo) *MethodInfo {
+	// 同一文件中不同类型的同名方法是不同的声明，键中需要包含位置
+	key := fmt.Sprintf("%s:%s|%s:%d:%d:%s", outer.Package, outer.Name, info.Location.File, info.Location.Line, info.Location.Column, info.Name)
+	if copied, ok := b.promoted[key]; ok {
+		return copied
+	}
+	copied := *info
+	if copied.PromotedFrom == "" {
+		copied.PromotedFrom = info.ReceiverType
+	}
+	copied.ReceiverType = outer.Name
+	copied.Package = outer.Package
+	b.promoted[key] = &copied
+	return &copied
+}
+
+// 查找嵌入字段对应的接口
+func (b *methodSetBuilder) embeddedInterface(outer *TypeInfo, embed FieldEmbed) *InterfaceInfo {
+	from := &InterfaceInfo{Package: outer.Package}
+	ref := EmbedRef{Name: embed.Name, ImportPath: embed.ImportPath}
+	if embed.Pointer || (ref.ImportPath == "" && ref.Name == "error") {
+		return nil
+	}
+	return b.resolver.lookup(from, ref)
+}
+
+// 查找嵌入字段对应的具名类型，返回其键
+func (b *methodSetBuilder) resolveType(outer *TypeInfo, embed FieldEmbed) string {
+	if embed.ImportPath == "" {
+		key := outer.Package + ":" + embed.Name
+		if _, ok := b.types[key]; ok {
+			return key
+		}
+		if _, ok := b.typeMethods[outer.Package+":*"+embed.Name]; ok {
+			return key
+		}
+		if _, ok := b.typeMethods[key]; ok {
+			return key
+		}
+		return ""
+	}
+
+	name := importPackageName(embed.ImportPath)
+	for key, info := range b.types {
+		if info.Name == embed.Name && info.PackageName == name && filepath.Base(info.Package) == name {
+			return key
+		}
+	}
+	return ""
+}
+
+// 把接口方法包装成方法信息，供嵌入接口字段的提升使用
+func interfaceMethodInfo(iface *InterfaceInfo, spec MethodSpec) *MethodInfo {
+	return &MethodInfo{
+		Name: spec.Name,
+		Location: Location{
+			File:     spec.Location.File,
+			Line:     spec.Location.Line + 1,
+			Column:   spec.Location.Column + 1,
+			oneBased: true,
+		},
+		EndLocation: Location{
+			File:     spec.Location.File,
+			Line:     spec.Location.Line + 1,
+			Column:   spec.Location.Column + 1,
+			oneBased: true,
+		},
+		NameLocation: Location{
+			File:     spec.Location.File,
+			Line:     spec.Location.Line + 1,
+			Column:   spec.Location.Column + 1,
+			oneBased: true,
+		},
+		NameEndLocation: Location{
+			File:     spec.nameEnd.File,
+			Line:     spec.nameEnd.Line + 1,
+			Column:   spec.nameEnd.Column + 1,
+			oneBased: true,
+		},
+		ReceiverType: iface.Name,
+		Package:      iface.Package,
+		Key:          spec.Key,
+		signature:    spec.Signature,
+	}
+}
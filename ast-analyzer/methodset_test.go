@@ -0,0 +1,91 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const embedFixture = `package p
+
+type Runner interface{ Run() }
+
+type impl struct{}
+
+func (impl) Run() {}
+
+// 嵌入接口：Run 被提升
+type ByValue struct{ Runner }
+
+// 嵌入接口指针是编译错误，不提升任何方法
+type ByPointer struct{ *Runner }
+`
+
+func TestEmbeddedInterfacePromotesItsMethods(t *testing.T) {
+	dir := writeTree(t, map[string]string{"a.go": embedFixture})
+	result := runArgs(t, "find-implementations", dir, "Run").(AnalysisResult)
+	got := receiverTypes(result.Implementations)
+	sort.Strings(got)
+	if want := []string{"ByValue", "impl"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got implementations %v, want %v", got, want)
+	}
+}
+
+func TestPointerToInterfaceEmbedIsReportedNotPromoted(t *testing.T) {
+	dir := writeTree(t, map[string]string{"a.go": embedFixture})
+	result := runArgs(t, "find-implementations", dir, "Run").(AnalysisResult)
+	for _, receiver := range receiverTypes(result.Implementations) {
+		if strings.TrimPrefix(receiver, "*") == "ByPointer" {
+			t.Fatalf("ByPointer should not implement Runner, got %v", receiverTypes(result.Implementations))
+		}
+	}
+
+	found := diagnosticsOfKind("invalidEmbed")
+	if len(found) != 1 {
+		t.Fatalf("got %d invalidEmbed diagnostics, want 1: %v", len(found), found)
+	}
+	d := found[0]
+	if !strings.Contains(d.Message, "p.ByPointer") || !strings.Contains(d.Message, "*p.Runner") {
+		t.Errorf("diagnostic message %q does not name the type and the interface", d.Message)
+	}
+	if !reflect.DeepEqual(d.Interfaces, []string{"p.Runner"}) {
+		t.Errorf("diagnostic interfaces = %v, want [p.Runner]", d.Interfaces)
+	}
+}
+
+// 以接口为底层类型的具名类型没有方法，即使源码中（非法地）为它声明了方法也不算实现
+func TestNamedTypesOverInterfacesAreNotImplementers(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"go.mod": "module example.com/m\n",
+		"api/api.go": `package api
+
+type Handler interface{ Serve() }
+
+type MyHandler Handler
+
+type Wrapped MyHandler
+
+func (MyHandler) Serve() {}
+
+func (*Wrapped) Serve() {}
+
+type Server struct{}
+
+func (Server) Serve() {}
+`,
+		"other/other.go": `package other
+
+import "example.com/m/api"
+
+type Remote api.Handler
+
+func (Remote) Serve() {}
+`,
+	})
+
+	result := runArgs(t, "find-implementations", dir, "Serve").(AnalysisResult)
+	if got, want := receiverTypes(result.Implementations), []string{"Server"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got implementations %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import "go/ast"
+
+// 方法体过大的实现方法
+type LargeMethod struct {
+	ReceiverType string   `json:"receiverType"`
+	MethodName   string   `json:"methodName"`
+	LineCount    int      `json:"lineCount"`
+	Location     Location `json:"location"`
+}
+
+type BodySizeResult struct {
+	LargeMethods []LargeMethod `json:"largeMethods"`
+}
+
+// 查找行数超过阈值的接口实现方法
+func findLargeMethods(directory string, maxLines int) []LargeMethod {
+	largeMethods := []LargeMethod{}
+
+	for _, method := range uniqueImplementedMethods(matchAllImplementations(directory)) {
+		lineCount := method.EndLocation.Line - method.Location.Line
+		if lineCount > maxLines {
+			largeMethods = append(largeMethods, LargeMethod{
+				ReceiverType: method.ReceiverType,
+				MethodName:   method.Name,
+				LineCount:    lineCount,
+				Location:     method.Location,
+			})
+		}
+	}
+
+	return largeMethods
+}
+
+// 返回值过多的接口方法或实现方法
+type TooManyReturns struct {
+	InterfaceName string `json:"interfaceName"`
+	// 实现方法所属的类型，接口方法本身为空
+	ReceiverType string   `json:"receiverType,omitempty"`
+	MethodName   string   `json:"methodName"`
+	ReturnCount  int      `json:"returnCount"`
+	Location     Location `json:"location"`
+}
+
+type ReturnCountResult struct {
+	Methods []TooManyReturns `json:"methods"`
+}
+
+// 统计参数或返回值的个数，(a, b int) 算作两个
+func fieldCount(list *ast.FieldList) int {
+	if list == nil {
+		return 0
+	}
+	count := 0
+	for _, field := range list.List {
+		if len(field.Names) == 0 {
+			count++
+		} else {
+			count += len(field.Names)
+		}
+	}
+	return count
+}
+
+// 查找返回值个数超过阈值的接口方法，以及这些接口的实现方法
+func findTooManyReturns(directory string, maxReturns int) []TooManyReturns {
+	results := []TooManyReturns{}
+	scan := scanDirectory(directory)
+
+	for _, iface := range scan.Interfaces {
+		for _, spec := range iface.Specs {
+			if spec.embedded || spec.Func == nil {
+				continue
+			}
+			if count := fieldCount(spec.Func.Results); count > maxReturns {
+				results = append(results, TooManyReturns{
+					InterfaceName: iface.Name,
+					MethodName:    spec.Name,
+					ReturnCount:   count,
+					Location:      spec.Location,
+				})
+			}
+		}
+	}
+
+	seen := make(map[Location]bool)
+	for _, match := range matchImplementations(scan) {
+		for _, method := range match.ImplementedMethods() {
+			if method.FuncDecl == nil || seen[method.Location] {
+				continue
+			}
+			if count := fieldCount(method.FuncDecl.Type.Results); count > maxReturns {
+				seen[method.Location] = true
+				results = append(results, TooManyReturns{
+					InterfaceName: match.Interface.Name,
+					ReceiverType:  method.ReceiverType,
+					MethodName:    method.Name,
+					ReturnCount:   count,
+					Location:      method.Location,
+				})
+			}
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+)
+
+// 生成 mock 所需的接口信息：展开后的方法签名和已有的实现类型
+type MockInfo struct {
+	InterfaceDescription
+	// 接口所在包的导入路径，不在模块中时为空
+	ImportPath   string            `json:"importPath"`
+	Parameters   []MockMethodTypes `json:"parameters"`
+	Implementers []MockImplementer `json:"implementers"`
+}
+
+// 方法的参数和返回值类型（按源码书写），供生成代码直接使用
+type MockMethodTypes struct {
+	Name     string   `json:"name"`
+	Params   []string `json:"params"`
+	Results  []string `json:"results"`
+	Variadic bool     `json:"variadic"`
+}
+
+// 已有的实现类型；名称带 Mock/Fake/Stub 的类型通常是之前生成的 mock
+type MockImplementer struct {
+	Type     string   `json:"type"`
+	Package  string   `json:"package"`
+	Location Location `json:"location"`
+	IsMock   bool     `json:"isMock"`
+}
+
+type MockInfoResult struct {
+	Interfaces []MockInfo `json:"interfaces"`
+}
+
+func fieldTypeStrings(list *ast.FieldList) []string {
+	typeStrings := []string{}
+	for _, expr := range expandFields(list) {
+		typeStrings = append(typeStrings, types.ExprString(expr))
+	}
+	return typeStrings
+}
+
+func looksLikeMock(typeName string) bool {
+	name := strings.TrimPrefix(typeName, "*")
+	for _, marker := range []string{"Mock", "mock", "Fake", "fake", "Stub", "stub"} {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// 在 describe-interface 的基础上附加参数类型和已有实现
+func findMockInfo(directory, interfaceName string) []MockInfo {
+	infos := []MockInfo{}
+	scan := scanDirectory(directory)
+	matches := matchImplementations(scan)
+
+	for i := range scan.Interfaces {
+		iface := &scan.Interfaces[i]
+		if iface.Name != interfaceName {
+			continue
+		}
+
+		info := MockInfo{
+			InterfaceDescription: newInterfaceDescription(*iface),
+			ImportPath:           packageImportPath(iface.Package),
+			Parameters:           []MockMethodTypes{},
+			Implementers:         []MockImplementer{},
+		}
+		for j, spec := range iface.Specs {
+			info.Methods[j].ImplementedBy = countMethodProviders(scan.TypeMethods, iface, spec)
+			methodTypes := MockMethodTypes{Name: spec.Name, Params: []string{}, Results: []string{}}
+			if spec.Func != nil {
+				methodTypes.Params = fieldTypeStrings(spec.Func.Params)
+				methodTypes.Results = fieldTypeStrings(spec.Func.Results)
+				methodTypes.Variadic = len(methodTypes.Params) > 0 && strings.HasPrefix(methodTypes.Params[len(methodTypes.Params)-1], "...")
+			}
+			info.Parameters = append(info.Parameters, methodTypes)
+		}
+
+		for _, match := range matches {
+			if match.Interface != iface {
+				continue
+			}
+			dir, typeName := splitTypeKey(match.TypeKey)
+			implementer := MockImplementer{
+				Type:    typeName,
+				Package: dir,
+				IsMock:  looksLikeMock(typeName),
+			}
+			if typeInfo, ok := scan.Types[dir+":"+strings.TrimPrefix(typeName, "*")]; ok {
+				implementer.Location = typeInfo.Location
+			}
+			info.Implementers = append(info.Implementers, implementer)
+		}
+
+		infos = append(infos, info)
+	}
+
+	return infos
+}
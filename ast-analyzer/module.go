@@ -0,0 +1,84 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// 从给定目录向上查找包含 go.mod 的模块根目录，找不到时返回空字符串
+func findModuleRoot(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+
+	for {
+		if info, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil && !info.IsDir() {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// go.mod 中的模块信息
+type moduleInfo struct {
+	Path      string
+	GoVersion string
+	// require 指令：模块路径 -> 版本
+	Requires map[string]string
+}
+
+// 解析 go.mod 中的 module、go 和 require 指令，文件缺失或格式错误时返回已解析出的部分
+func parseGoMod(file string) moduleInfo {
+	info := moduleInfo{Requires: make(map[string]string)}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return info
+	}
+
+	inRequire := false
+	for _, line := range strings.Split(string(data), "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		switch {
+		case inRequire && len(fields) == 1 && fields[0] == ")":
+			inRequire = false
+			continue
+		case inRequire && len(fields) == 2:
+			info.Requires[unquoteModulePath(fields[0])] = fields[1]
+			continue
+		case len(fields) == 2 && fields[0] == "require" && fields[1] == "(":
+			inRequire = true
+			continue
+		case len(fields) == 3 && fields[0] == "require":
+			info.Requires[unquoteModulePath(fields[1])] = fields[2]
+			continue
+		}
+		if len(fields) != 2 {
+			continue
+		}
+		switch fields[0] {
+		case "module":
+			info.Path = unquoteModulePath(fields[1])
+		case "go":
+			info.GoVersion = fields[1]
+		}
+	}
+	return info
+}
+
+// go.mod 中的模块路径可以带引号
+func unquoteModulePath(path string) string {
+	if unquoted, err := strconv.Unquote(path); err == nil {
+		return unquoted
+	}
+	return path
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"path/filepath"
+)
+
+// 模块中声明了某个方法的接口，名称以导入路径限定
+type ModuleInterfaceMethod struct {
+	Name          string `json:"name"`
+	InterfaceName string `json:"interfaceName"`
+	// 以导入路径限定的接口名，例如 github.com/acme/app/auth.TokenStore
+	QualifiedName string   `json:"qualifiedName"`
+	Package       string   `json:"package"`
+	Signature     string   `json:"signature"`
+	Location      Location `json:"location"`
+	// 方法来自嵌入接口时的真正声明位置
+	DeclaredIn *DeclarationRef `json:"declaredIn,omitempty"`
+}
+
+type ModuleInterfacesResult struct {
+	// 模块根目录
+	Root       string                  `json:"root"`
+	Interfaces []ModuleInterfaceMethod `json:"interfaces"`
+}
+
+// 在目录所在的整个模块（--root 或向上查找 go.mod）中查找声明了该方法的接口，
+// 与 find-interfaces 相同，通过嵌入获得该方法的接口也会列出。找不到 go.mod 时只扫描该目录
+func findMethodInterfacesModule(directory, methodName string) ModuleInterfacesResult {
+	root := options.String("root", "")
+	if root == "" {
+		root = findModuleRoot(directory)
+	}
+	if root == "" {
+		root = directory
+	}
+	root, _ = filepath.Abs(root)
+
+	result := ModuleInterfacesResult{Root: root, Interfaces: []ModuleInterfaceMethod{}}
+	importPaths := make(map[string]string)
+	for _, iface := range findAllInterfacesWithMethods(root) {
+		for _, spec := range iface.Specs {
+			if spec.Name != methodName {
+				continue
+			}
+			importPath, ok := importPaths[iface.Package]
+			if !ok {
+				importPath = packageImportPath(iface.Package)
+				if importPath == "" {
+					importPath = iface.PackageName
+				}
+				importPaths[iface.Package] = importPath
+			}
+			method := ModuleInterfaceMethod{
+				Name:          spec.Name,
+				InterfaceName: iface.Name,
+				QualifiedName: importPath + "." + iface.Name,
+				Package:       importPath,
+				Signature:     spec.Signature,
+				Location:      spec.Location,
+				DeclaredIn:    spec.declaration(),
+			}
+			if spec.embedded {
+				method.Location = iface.Location
+			}
+			result.Interfaces = append(result.Interfaces, method)
+		}
+	}
+	return result
+}
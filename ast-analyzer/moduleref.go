@@ -0,0 +1,48 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// 实现所在的模块，与扫描目录所在的主模块不同时才记录
+type ModuleRef struct {
+	Path    string `json:"path"`
+	Version string `json:"version,omitempty"`
+	// 位于模块缓存中，文件只读，不应该对它提供编辑
+	ReadOnly bool `json:"readOnly,omitempty"`
+}
+
+// 包目录所在的模块。模块缓存中的模块目录名为 path@version，版本取自目录名；
+// 其他模块（go.work 中的模块、replace 指向的本地目录）的版本取自主模块 go.mod 的 require，没有时为空。
+// 扫描目录不在模块中时，只记录模块缓存中的模块
+func (s *ScanResult) moduleOf(pkgDir string) *ModuleRef {
+	root := findModuleRoot(pkgDir)
+	if root == "" {
+		return nil
+	}
+
+	s.modulesMu.Lock()
+	defer s.modulesMu.Unlock()
+	if s.modules == nil {
+		s.modules = make(map[string]*ModuleRef)
+		s.mainModule = findModuleRoot(s.directory)
+	}
+	if ref, ok := s.modules[root]; ok {
+		return ref
+	}
+
+	var ref *ModuleRef
+	base := filepath.Base(root)
+	if at := strings.LastIndex(base, "@"); at >= 0 {
+		ref = &ModuleRef{Path: parseGoMod(filepath.Join(root, "go.mod")).Path, Version: base[at+1:], ReadOnly: true}
+	} else if s.mainModule != "" && root != s.mainModule {
+		path := parseGoMod(filepath.Join(root, "go.mod")).Path
+		ref = &ModuleRef{Path: path, Version: parseGoMod(filepath.Join(s.mainModule, "go.mod")).Requires[path]}
+	}
+	if ref != nil && ref.Path == "" {
+		ref = nil
+	}
+	s.modules[root] = ref
+	return ref
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// SimpleTokenManager 同时满足 TokenManager 和更小的 TokenValidator
+const tokenManagerFixture = `package auth
+
+type TokenManager interface {
+	AddToken(token string) error
+	Validate(token string) bool
+}
+
+type TokenValidator interface {
+	Validate(token string) bool
+}
+
+type SimpleTokenManager struct{}
+
+func (s *SimpleTokenManager) AddToken(token string) error { return nil }
+
+func (s *SimpleTokenManager) Validate(token string) bool { return true }
+`
+
+func TestTypeSatisfyingTwoInterfacesReportsBoth(t *testing.T) {
+	dir := writeTree(t, map[string]string{"auth.go": tokenManagerFixture})
+	file := filepath.Join(dir, "auth.go")
+
+	// 多次运行，结果不能依赖 map 的遍历顺序
+	for run := 0; run < 10; run++ {
+		implementations := runArgs(t, "find-file-implementations", file).(AnalysisResult).Implementations
+		got := make(map[string][]string)
+		for _, impl := range implementations {
+			got[impl.MethodName] = impl.Interfaces
+		}
+		want := map[string][]string{
+			"AddToken": {"TokenManager"},
+			"Validate": {"TokenManager", "TokenValidator"},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("run %d: find-file-implementations interfaces = %v, want %v", run, got, want)
+		}
+
+		pkg := runArgs(t, "analyze-package-interfaces", dir).(PackageAnalysisResult)
+		validate := append([]string(nil), pkg.MethodToInterfaces["Validate"]...)
+		sort.Strings(validate)
+		if want := []string{"TokenManager", "TokenValidator"}; !reflect.DeepEqual(validate, want) {
+			t.Fatalf("run %d: methodToInterfaces[Validate] = %v, want %v", run, validate, want)
+		}
+		if got := pkg.MethodToInterface["Validate"]; got != "TokenManager" {
+			t.Fatalf("run %d: methodToInterface[Validate] = %q, want TokenManager", run, got)
+		}
+	}
+}
+
+// 逐个类型、逐个接口的匹配过程不再打印到标准错误
+func TestFindFileImplementationsIsQuiet(t *testing.T) {
+	dir := writeTree(t, map[string]string{"auth.go": tokenManagerFixture, "auth_test.go": "package auth\n"})
+	stderr := captureStderr(t, func() {
+		runArgs(t, "find-file-implementations", filepath.Join(dir, "auth.go"))
+	})
+	if stderr != "" {
+		t.Fatalf("find-file-implementations wrote to stderr:\n%s", stderr)
+	}
+}
+
+// --implementation-counts 按接口分别计数，两个接口的 Validate 都有一个实现
+func TestImplementationCountsArePerInterface(t *testing.T) {
+	dir := writeTree(t, map[string]string{"auth.go": tokenManagerFixture})
+	result := runArgs(t, "find-file-interfaces", filepath.Join(dir, "auth.go"), "--implementation-counts").(AnalysisResult)
+	got := make(map[string]int)
+	for _, method := range result.Interfaces {
+		got[method.InterfaceName+"."+method.Name] = *method.ImplementationCount
+	}
+	want := map[string]int{"TokenManager.AddToken": 1, "TokenManager.Validate": 1, "TokenValidator.Validate": 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got counts %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// 类型实现的接口
+type InterfaceRef struct {
+	InterfaceName string   `json:"interfaceName"`
+	Package       string   `json:"package"`
+	PackageName   string   `json:"packageName"`
+	Location      Location `json:"location"`
+	// 只有 *T 实现了该接口
+	PointerReceiver bool `json:"pointerReceiver,omitempty"`
+}
+
+// 同时实现多个接口的类型，可以考虑接口隔离
+type MultipleInterfaceImplementor struct {
+	ReceiverType          string         `json:"receiverType"`
+	Package               string         `json:"package"`
+	ImplementedInterfaces []InterfaceRef `json:"implementedInterfaces"`
+	Location              Location       `json:"location"`
+}
+
+type MultipleInterfaceResult struct {
+	Implementors []MultipleInterfaceImplementor `json:"implementors"`
+}
+
+// 找出实现了至少 --min-interfaces（默认 2）个接口的类型，按实现的接口数量降序排列。
+// T 和 *T 算同一个类型，同一个接口只记录一次
+func findMultipleInterfaceImplementors(directory string) []MultipleInterfaceImplementor {
+	minInterfaces := options.Int("min-interfaces", 2)
+	results := []MultipleInterfaceImplementor{}
+
+	scan := scanDirectory(directory)
+	byType := make(map[string]*MultipleInterfaceImplementor)
+	var order []string
+	// 类型 -> 接口 -> 在 ImplementedInterfaces 中的下标
+	seen := make(map[string]map[*InterfaceInfo]int)
+
+	for _, match := range matchImplementations(scan) {
+		pkg, receiver := splitTypeKey(match.TypeKey)
+		name := strings.TrimPrefix(receiver, "*")
+		baseKey := pkg + ":" + name
+		if i, ok := seen[baseKey][match.Interface]; ok {
+			// *T 排在 T 之前匹配，T 也实现时不再标记为指针接收者
+			if receiver == name {
+				byType[baseKey].ImplementedInterfaces[i].PointerReceiver = false
+			}
+			continue
+		}
+
+		implementor, ok := byType[baseKey]
+		if !ok {
+			location := Location{}
+			if info, ok := scan.Types[baseKey]; ok {
+				location = info.Location
+			} else if methods := match.ImplementedMethods(); len(methods) > 0 {
+				location = methods[0].Location
+			}
+			implementor = &MultipleInterfaceImplementor{
+				ReceiverType:          name,
+				Package:               pkg,
+				ImplementedInterfaces: []InterfaceRef{},
+				Location:              location,
+			}
+			byType[baseKey] = implementor
+			seen[baseKey] = make(map[*InterfaceInfo]int)
+			order = append(order, baseKey)
+		}
+		seen[baseKey][match.Interface] = len(implementor.ImplementedInterfaces)
+
+		implementor.ImplementedInterfaces = append(implementor.ImplementedInterfaces, InterfaceRef{
+			InterfaceName:   match.Interface.Name,
+			Package:         match.Interface.Package,
+			PackageName:     match.Interface.PackageName,
+			Location:        match.Interface.Location,
+			PointerReceiver: receiver != name,
+		})
+	}
+
+	for _, key := range order {
+		if implementor := byType[key]; len(implementor.ImplementedInterfaces) >= minInterfaces {
+			results = append(results, *implementor)
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return len(results[i].ImplementedInterfaces) > len(results[j].ImplementedInterfaces)
+	})
+	return results
+}
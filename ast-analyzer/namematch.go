@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// 方法名匹配器，支持 exact、prefix、fuzzy、icase 四种模式
+type nameMatcher struct {
+	mode  string
+	query string
+	// --ignore-case：prefix 模式也忽略大小写
+	ignoreCase bool
+}
+
+func newNameMatcher(mode, query string) nameMatcher {
+	switch mode {
+	case "exact", "prefix", "fuzzy", "icase":
+	default:
+		fmt.Fprintf(os.Stderr, "未知的名称匹配模式 %s，使用 exact\n", mode)
+		mode = "exact"
+	}
+	return nameMatcher{mode: mode, query: query}
+}
+
+// 按 --match-name 和 --ignore-case 创建查询方法名的匹配器；--ignore-case 下 exact 模式即 icase。
+// 只影响名称的比较，签名仍区分大小写
+func queryNameMatcher(query string) nameMatcher {
+	m := newNameMatcher(options.String("match-name", "exact"), query)
+	if options.Has("ignore-case") {
+		m.ignoreCase = true
+		if m.mode == "exact" {
+			m.mode = "icase"
+		}
+	}
+	return m
+}
+
+func (m nameMatcher) exact() bool {
+	return m.mode == "exact"
+}
+
+// 判断名称是否匹配，并返回相似度（0~1，完全相同为 1）
+func (m nameMatcher) match(name string) (float64, bool) {
+	if name == m.query {
+		return 1, true
+	}
+
+	switch m.mode {
+	case "icase":
+		if strings.EqualFold(name, m.query) {
+			return 1, true
+		}
+	case "prefix":
+		if strings.HasPrefix(name, m.query) || m.ignoreCase && strings.HasPrefix(strings.ToLower(name), strings.ToLower(m.query)) {
+			return float64(len(m.query)) / float64(len(name)), true
+		}
+	case "fuzzy":
+		return fuzzyScore(m.query, name)
+	}
+	return 0, false
+}
+
+// 子序列模糊匹配（忽略大小写）：query 的字符需按顺序出现在 name 中。
+// 得分综合了覆盖率、连续匹配比例以及是否从首字母开始匹配。
+// 匹配在逐字符转小写的副本上进行，单词边界（大写字母）按同一下标在原名称的字符上判断
+func fuzzyScore(query, name string) (float64, bool) {
+	q := lowerRunes(query)
+	original := []rune(name)
+	n := lowerRunes(name)
+	if len(q) == 0 || len(q) > len(n) {
+		return 0, false
+	}
+
+	matched, consecutive, first := 0, 0, -1
+	last := -2
+	for i := 0; i < len(n) && matched < len(q); i++ {
+		if n[i] != q[matched] {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		if i == last+1 {
+			consecutive++
+		}
+		last = i
+		matched++
+	}
+	if matched < len(q) {
+		return 0, false
+	}
+
+	coverage := float64(len(q)) / float64(len(n))
+	adjacency := 1.0
+	if len(q) > 1 {
+		adjacency = float64(consecutive) / float64(len(q)-1)
+	}
+	score := 0.5*coverage + 0.3*adjacency
+	if first == 0 || (first > 0 && unicode.IsUpper(original[first])) {
+		score += 0.2
+	}
+	if score > 1 {
+		score = 1
+	}
+	return score, true
+}
+
+// 逐个字符转小写，结果与原字符串的字符一一对应
+func lowerRunes(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
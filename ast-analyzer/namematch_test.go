@@ -0,0 +1,68 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		query, name string
+		score       float64
+		ok          bool
+	}{
+		{"get", "Get", 1, true},
+		{"gu", "GetUser", 0.5*2/7 + 0.2, true},
+		{"user", "GetUser", 0.5*4/7 + 0.3 + 0.2, true},
+		{"ser", "GetUser", 0.5*3/7 + 0.3, true},
+		{"ug", "GetUser", 0, false},
+		{"getusers", "GetUser", 0, false},
+		{"", "GetUser", 0, false},
+		// 非 ASCII 名称：下标按字符而不是字节计算，F 和 Ö 是单词边界，İ 与 i 忽略大小写相同
+		{"foo", "İxFoo", 0.5*3/5 + 0.3 + 0.2, true},
+		{"ifoo", "İxFoo", 0.5*4/5 + 0.3*2/3 + 0.2, true},
+		{"xö", "İxÖo", 0.5*2/4 + 0.3, true},
+		{"öo", "İxÖo", 0.5*2/4 + 0.3 + 0.2, true},
+	}
+	for _, tt := range tests {
+		score, ok := fuzzyScore(tt.query, tt.name)
+		if ok != tt.ok || math.Abs(score-tt.score) > 1e-9 {
+			t.Errorf("fuzzyScore(%q, %q) = %v, %v; want %v, %v", tt.query, tt.name, score, ok, tt.score, tt.ok)
+		}
+	}
+}
+
+func TestIgnoreCaseMatchesMethodNames(t *testing.T) {
+	dir := writeTree(t, map[string]string{"a.go": serveFixtureInterface})
+	interfaceNames := func(args ...string) []string {
+		var names []string
+		for _, method := range runArgs(t, append([]string{"find-interfaces", dir}, args...)...).(AnalysisResult).Interfaces {
+			names = append(names, method.InterfaceName+"."+method.Name)
+		}
+		return names
+	}
+
+	for _, tc := range []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"run"}, nil},
+		{[]string{"run", "--ignore-case"}, []string{"A"}},
+		{[]string{"RUN", "--ignore-case"}, []string{"A"}},
+		{[]string{"RU", "--match-name=prefix"}, nil},
+		{[]string{"RU", "--match-name=prefix", "--ignore-case"}, []string{"A"}},
+	} {
+		args := append([]string{"find-implementations", dir}, tc.args...)
+		if got := receiverTypes(runArgs(t, args...).(AnalysisResult).Implementations); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("find-implementations %v: got %v, want %v", tc.args, got, tc.want)
+		}
+		var want []string
+		if tc.want != nil {
+			want = []string{"Runner.Run"}
+		}
+		if got := interfaceNames(tc.args...); !reflect.DeepEqual(got, want) {
+			t.Errorf("find-interfaces %v: got %v, want %v", tc.args, got, want)
+		}
+	}
+}
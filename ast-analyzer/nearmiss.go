@@ -0,0 +1,133 @@
+package main
+
+import (
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// 几乎实现了接口的类型：只有参数的指针/值形式与接口方法不一致，或者实现方法带有类型参数
+type NearMiss struct {
+	InterfaceName string `json:"interfaceName"`
+	ReceiverType  string `json:"receiverType"`
+	MethodName    string `json:"methodName"`
+	// pointerValue 或 methodTypeParams
+	Reason string `json:"reason"`
+	// 不一致的参数位置（从 0 开始）和两侧的类型；methodTypeParams 时为 -1，两侧是完整签名
+	ParameterIndex int      `json:"parameterIndex"`
+	Expected       string   `json:"expected"`
+	Actual         string   `json:"actual"`
+	Explanation    string   `json:"explanation,omitempty"`
+	Location       Location `json:"location"`
+}
+
+// 比较接口方法和实现方法的参数，只有指针/值不一致时返回这些参数；
+// 还有其他差异（参数个数、返回值或其他类型）时不算 near-miss
+func pointerValueDiffs(spec MethodSpec, method *MethodInfo) ([]int, bool) {
+	if spec.Func == nil || method.FuncDecl == nil {
+		return nil, false
+	}
+	want, got := expandFields(spec.Func.Params), expandFields(method.FuncDecl.Type.Params)
+	wantResults, gotResults := expandFields(spec.Func.Results), expandFields(method.FuncDecl.Type.Results)
+	if len(want) != len(got) || len(wantResults) != len(gotResults) {
+		return nil, false
+	}
+	for i := range wantResults {
+		if types.ExprString(wantResults[i]) != types.ExprString(gotResults[i]) {
+			return nil, false
+		}
+	}
+
+	var diffs []int
+	for i := range want {
+		wantType, gotType := types.ExprString(want[i]), types.ExprString(got[i])
+		if wantType == gotType {
+			continue
+		}
+		if strings.TrimPrefix(wantType, "*") != strings.TrimPrefix(gotType, "*") {
+			return nil, false
+		}
+		diffs = append(diffs, i)
+	}
+	return diffs, len(diffs) > 0
+}
+
+// 类型的方法集是否只因指针/值参数差异或方法自带的类型参数而没能实现接口
+func nearMissesFor(iface *InterfaceInfo, methods map[string]*MethodInfo) []NearMiss {
+	var misses []NearMiss
+	for _, spec := range iface.Specs {
+		method, ok := methods[spec.Name]
+		if !ok {
+			return nil
+		}
+		if method.hasTypeParams() {
+			misses = append(misses, NearMiss{
+				InterfaceName:  iface.Name,
+				ReceiverType:   method.ReceiverType,
+				MethodName:     spec.Name,
+				Reason:         "methodTypeParams",
+				ParameterIndex: -1,
+				Expected:       spec.Signature,
+				Actual:         method.Signature(),
+				Explanation:    "method " + spec.Name + " declares its own type parameters; methods with type parameters cannot satisfy an interface",
+				Location:       method.Location,
+			})
+			continue
+		}
+		if spec.Key == "" || method.Key == "" || spec.Key == method.Key {
+			continue
+		}
+		diffs, ok := pointerValueDiffs(spec, method)
+		if !ok {
+			return nil
+		}
+		want, got := expandFields(spec.Func.Params), expandFields(method.FuncDecl.Type.Params)
+		for _, i := range diffs {
+			misses = append(misses, NearMiss{
+				InterfaceName:  iface.Name,
+				ReceiverType:   method.ReceiverType,
+				MethodName:     spec.Name,
+				Reason:         "pointerValue",
+				ParameterIndex: i,
+				Expected:       types.ExprString(want[i]),
+				Actual:         types.ExprString(got[i]),
+				Location:       method.Location,
+			})
+		}
+	}
+	return misses
+}
+
+// --explain：列出声明了该方法的接口中，只因参数指针/值不一致而没有匹配上的类型
+func findNearMisses(directory, methodName string) []NearMiss {
+	misses := []NearMiss{}
+	scan := scanDirectory(directory)
+
+	typeKeys := make([]string, 0, len(scan.TypeMethods))
+	for typeKey := range scan.TypeMethods {
+		typeKeys = append(typeKeys, typeKey)
+	}
+	sort.Strings(typeKeys)
+
+	for i := range scan.Interfaces {
+		iface := &scan.Interfaces[i]
+		if !iface.hasMethod(methodName) {
+			continue
+		}
+		reported := make(map[string]bool)
+		for _, typeKey := range typeKeys {
+			methods := scan.TypeMethods[typeKey]
+			// T 和 *T 是同一个类型，只报告一次
+			baseKey := strings.Replace(typeKey, ":*", ":", 1)
+			if reported[baseKey] || !iface.visibleTo(packageOf(methods)) {
+				continue
+			}
+			if found := nearMissesFor(iface, methods); len(found) > 0 {
+				reported[baseKey] = true
+				misses = append(misses, found...)
+			}
+		}
+	}
+
+	return misses
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// 在方法体中直接发起网络调用的接口实现方法，测试时通常需要 mock
+type NetworkCall struct {
+	ReceiverType string `json:"receiverType"`
+	MethodName   string `json:"methodName"`
+	// 调用的函数，例如 http.Get、net.Dial、http.DefaultClient.Do
+	NetworkFunc string   `json:"networkFunc"`
+	Location    Location `json:"location"`
+}
+
+type NetworkCallResult struct {
+	Calls []NetworkCall `json:"calls"`
+}
+
+// 按调用时的包名识别的网络函数
+var networkFuncs = map[string]map[string]bool{
+	"http": {"Get": true, "Post": true, "PostForm": true, "Head": true, "ListenAndServe": true, "ListenAndServeTLS": true, "Serve": true},
+	"net": {"Dial": true, "DialTimeout": true, "DialTCP": true, "DialUDP": true, "DialUnix": true, "DialIP": true,
+		"Listen": true, "ListenPacket": true, "ListenTCP": true, "ListenUDP": true,
+		"LookupHost": true, "LookupIP": true, "LookupAddr": true, "LookupCNAME": true, "LookupMX": true, "LookupTXT": true},
+	"grpc": {"Dial": true, "DialContext": true, "NewClient": true, "NewServer": true},
+	"tls":  {"Dial": true, "DialWithDialer": true, "Listen": true},
+}
+
+// 调用的目标是否为网络函数：pkg.Func，或者 http.DefaultClient 上的方法
+func networkFuncOf(call *ast.CallExpr) (string, bool) {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	switch x := selector.X.(type) {
+	case *ast.Ident:
+		if networkFuncs[x.Name][selector.Sel.Name] {
+			return x.Name + "." + selector.Sel.Name, true
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := x.X.(*ast.Ident); ok && pkg.Name == "http" && x.Sel.Name == "DefaultClient" {
+			return types.ExprString(call.Fun), true
+		}
+	}
+	return "", false
+}
+
+// 查找方法体中直接调用了网络函数的接口实现方法，每个调用输出一条
+func findNetworkCalls(directory string) []NetworkCall {
+	results := []NetworkCall{}
+
+	for _, method := range uniqueImplementedMethods(matchAllImplementations(directory)) {
+		if method.fset == nil || method.FuncDecl.Body == nil {
+			continue
+		}
+		ast.Inspect(method.FuncDecl.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			if networkFunc, ok := networkFuncOf(call); ok {
+				pos := method.fset.Position(call.Pos())
+				results = append(results, NetworkCall{
+					ReceiverType: method.ReceiverType,
+					MethodName:   method.Name,
+					NetworkFunc:  networkFunc,
+					Location:     Location{File: pos.Filename, Line: pos.Line - 1, Column: pos.Column - 1},
+				})
+			}
+			return true
+		})
+	}
+
+	return results
+}
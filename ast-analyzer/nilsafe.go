@@ -0,0 +1,121 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// 指针实现类型在接收者为 nil 时调用接口方法是否安全。
+// 包装了 nil 指针的接口值本身不为 nil（见 Go FAQ），方法仍会被调用
+type NilSafeInterface struct {
+	InterfaceName   string   `json:"interfaceName"`
+	ImplementorType string   `json:"implementorType"`
+	Location        Location `json:"location"`
+	// 所有接口方法都能在 nil 接收者上调用而不 panic
+	AllMethodsNilSafe bool `json:"allMethodsNilSafe"`
+	// 接收者为 nil 时会 panic 的方法
+	UnsafeMethods []string `json:"unsafeMethods,omitempty"`
+}
+
+type NilSafeResult struct {
+	Interfaces []NilSafeInterface `json:"interfaces"`
+}
+
+// 方法在 nil 指针接收者上调用是否安全：接收者未被使用，或第一条语句是 if r == nil { ... return }。
+// 值接收者方法（需要解引用）、提升方法和来自嵌入接口字段的方法都不安全
+func nilSafeMethod(method *MethodInfo) bool {
+	if method.FuncDecl == nil || method.FuncDecl.Body == nil || method.PromotedFrom != "" {
+		return false
+	}
+	if !strings.HasPrefix(method.ReceiverType, "*") {
+		return false
+	}
+	receiver, _, _ := method.receiver()
+	if receiver == "" || !identUsed(method.FuncDecl.Body, receiver) {
+		return true
+	}
+
+	body := method.FuncDecl.Body.List
+	if len(body) == 0 {
+		return true
+	}
+	guard, ok := body[0].(*ast.IfStmt)
+	if !ok || guard.Init != nil || !nilCheckOf(guard.Cond, receiver) || len(guard.Body.List) == 0 {
+		return false
+	}
+	_, returns := guard.Body.List[len(guard.Body.List)-1].(*ast.ReturnStmt)
+	return returns
+}
+
+// 条件是否为 r == nil（或 nil == r），也接受 r == nil || ... 的形式
+func nilCheckOf(cond ast.Expr, receiver string) bool {
+	if paren, ok := cond.(*ast.ParenExpr); ok {
+		return nilCheckOf(paren.X, receiver)
+	}
+	binary, ok := cond.(*ast.BinaryExpr)
+	if !ok {
+		return false
+	}
+	if binary.Op == token.LOR {
+		return nilCheckOf(binary.X, receiver)
+	}
+	if binary.Op != token.EQL {
+		return false
+	}
+	isReceiver := func(expr ast.Expr) bool {
+		ident, ok := expr.(*ast.Ident)
+		return ok && ident.Name == receiver
+	}
+	isNil := func(expr ast.Expr) bool {
+		ident, ok := expr.(*ast.Ident)
+		return ok && ident.Name == "nil"
+	}
+	return isReceiver(binary.X) && isNil(binary.Y) || isNil(binary.X) && isReceiver(binary.Y)
+}
+
+// 方法体中是否引用了该标识符
+func identUsed(body *ast.BlockStmt, name string) bool {
+	used := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && ident.Name == name {
+			used = true
+		}
+		return !used
+	})
+	return used
+}
+
+// 查找指针类型的接口实现，判断接口变量持有该类型的 nil 指针时调用每个接口方法是否会 panic
+func findNilSafeInterfaces(directory string) []NilSafeInterface {
+	results := []NilSafeInterface{}
+
+	scan := scanDirectory(directory)
+	for _, match := range matchImplementations(scan) {
+		pkg, receiver := splitTypeKey(match.TypeKey)
+		if !strings.HasPrefix(receiver, "*") {
+			continue
+		}
+
+		entry := NilSafeInterface{
+			InterfaceName:     match.Interface.Name,
+			ImplementorType:   receiver,
+			AllMethodsNilSafe: true,
+		}
+		if info, ok := scan.Types[pkg+":"+strings.TrimPrefix(receiver, "*")]; ok {
+			entry.Location = info.Location
+		}
+		for _, method := range match.ImplementedMethods() {
+			if entry.Location.File == "" {
+				entry.Location = method.Location
+			}
+			if !nilSafeMethod(method) {
+				entry.AllMethodsNilSafe = false
+				entry.UnsafeMethods = append(entry.UnsafeMethods, method.Name)
+			}
+		}
+		results = append(results, entry)
+	}
+
+	return results
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+const nilSafeFixture = `package p
+
+type Named interface {
+	Name() string
+	Close() error
+}
+
+type Guarded struct{ name string }
+
+func (g *Guarded) Name() string {
+	if g == nil {
+		return ""
+	}
+	return g.name
+}
+
+func (*Guarded) Close() error { return nil }
+
+type Unsafe struct{ name string }
+
+func (u *Unsafe) Name() string { return u.name }
+
+func (u *Unsafe) Close() error {
+	u.name = ""
+	return nil
+}
+
+type Mixed struct{ name string }
+
+func (m *Mixed) Name() string {
+	if nil == m || m.name == "" {
+		return "none"
+	}
+	return m.name
+}
+
+func (m Mixed) Close() error { return nil }
+`
+
+func TestSatisfyingNilClassifiesMethods(t *testing.T) {
+	dir := writeTree(t, map[string]string{"p.go": nilSafeFixture})
+	got := make(map[string][]string)
+	for _, entry := range runArgs(t, "find-interface-satisfying-nil", dir).(NilSafeResult).Interfaces {
+		if entry.InterfaceName != "Named" {
+			t.Fatalf("unexpected interface %s", entry.InterfaceName)
+		}
+		if entry.AllMethodsNilSafe != (len(entry.UnsafeMethods) == 0) {
+			t.Fatalf("%s: allMethodsNilSafe %v with unsafe methods %v", entry.ImplementorType, entry.AllMethodsNilSafe, entry.UnsafeMethods)
+		}
+		got[entry.ImplementorType] = entry.UnsafeMethods
+	}
+
+	// 值类型 Mixed 不满足接口，*Mixed 的 Close 是值接收者方法，需要解引用；方法按接口中的顺序列出
+	want := map[string][]string{
+		"*Guarded": nil,
+		"*Unsafe":  {"Name", "Close"},
+		"*Mixed":   {"Close"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got unsafe methods %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 命令行选项：位置参数，以及 --name、--name=value、--name value 形式的开关
+type Options struct {
+	Args  []string
+	flags map[string]string
+	// 可重复的开关（如 --env）按出现顺序保留所有值
+	values map[string][]string
+}
+
+// 不接受单独参数值的布尔开关（需要值时使用 --name=value）
+var boolFlags = map[string]bool{
+	"explain":               true,
+	"goroot":                true,
+	"group-by-type":         true,
+	"human":                 true,
+	"ignore-case":           true,
+	"implementation-counts": true,
+	"include-ignored":       true,
+	"include-tests":         true,
+	"lsp":                   true,
+	"no-fallback":           true,
+	"per-package":           true,
+	"std":                   true,
+	"stdlib-embeddings":     true,
+	"stream":                true,
+	"with-stubs":            true,
+}
+
+// 取整数值和小数值的开关（由 Int、Float 读取）；值无法解析时命令报错，而不是悄悄使用默认值
+var intFlags = map[string]bool{
+	"jobs":                 true,
+	"limit":                true,
+	"max-depth":            true,
+	"max-dirs":             true,
+	"max-file-size":        true,
+	"max-lines":            true,
+	"max-returns":          true,
+	"min-interfaces":       true,
+	"parse-timeout":        true,
+	"warn-large-interface": true,
+}
+
+var floatFlags = map[string]bool{
+	"min-ratio": true,
+}
+
+// 当前命令的选项，在 main 中解析
+var options = &Options{flags: make(map[string]string)}
+
+func parseOptions(args []string) *Options {
+	opts := &Options{flags: make(map[string]string), values: make(map[string][]string)}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "--") || arg == "--" {
+			opts.Args = append(opts.Args, arg)
+			continue
+		}
+
+		name := strings.TrimPrefix(arg, "--")
+		if eq := strings.Index(name, "="); eq >= 0 {
+			opts.set(name[:eq], name[eq+1:])
+			continue
+		}
+
+		if !boolFlags[name] && i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+			opts.set(name, args[i+1])
+			i++
+			continue
+		}
+		opts.flags[name] = ""
+	}
+
+	return opts
+}
+
+func (o *Options) set(name, value string) {
+	o.flags[name] = value
+	o.values[name] = append(o.values[name], value)
+}
+
+func (o *Options) Values(name string) []string {
+	return o.values[name]
+}
+
+func (o *Options) Has(name string) bool {
+	_, ok := o.flags[name]
+	return ok
+}
+
+func (o *Options) String(name, def string) string {
+	if value, ok := o.flags[name]; ok && value != "" {
+		return value
+	}
+	return def
+}
+
+func (o *Options) Int(name string, def int) int {
+	value, err := strconv.Atoi(o.flags[name])
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+func (o *Options) Float(name string, def float64) float64 {
+	value, err := strconv.ParseFloat(o.flags[name], 64)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// 检查数值开关的取值，多个开关有误时按名称顺序报告第一个
+func (o *Options) validateNumbers() error {
+	names := make([]string, 0, len(o.flags))
+	for name := range o.flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := o.flags[name]
+		switch {
+		case intFlags[name]:
+			if _, err := strconv.Atoi(value); err != nil {
+				return fmt.Errorf("invalid --%s value: %q (want an integer)", name, value)
+			}
+		case floatFlags[name]:
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return fmt.Errorf("invalid --%s value: %q (want a number)", name, value)
+			}
+		}
+	}
+	return nil
+}
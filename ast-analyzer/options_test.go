@@ -0,0 +1,76 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"testing"
+)
+
+func TestInvalidNumericOptionIsAnError(t *testing.T) {
+	dir := writeTree(t, map[string]string{"a.go": serveFixtureInterface})
+	saved := options
+	t.Cleanup(func() { options = saved })
+
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--limit", "ten"}, `invalid --limit value: "ten" (want an integer)`},
+		{[]string{"--jobs=1.5"}, `invalid --jobs value: "1.5" (want an integer)`},
+		{[]string{"--max-lines="}, `invalid --max-lines value: "" (want an integer)`},
+		{[]string{"--min-ratio", "half"}, `invalid --min-ratio value: "half" (want a number)`},
+		// 多个开关有误时按名称顺序报告
+		{[]string{"--min-ratio=x", "--limit=y"}, `invalid --limit value: "y" (want an integer)`},
+	} {
+		options = parseOptions(append([]string{"find-implementations", dir, "Run"}, tc.args...))
+		startMeta(dir)
+		result, err := runCommand("find-implementations", dir)
+		if err == nil || err.Error() != tc.want || result != nil {
+			t.Errorf("%v: got %v, %v; want error %q", tc.args, result, err, tc.want)
+		}
+	}
+
+	options = parseOptions([]string{"find-implementations", dir, "Run", "--limit", "1", "--min-ratio=0.25"})
+	startMeta(dir)
+	if _, err := runCommand("find-implementations", dir); err != nil {
+		t.Fatalf("valid numeric options rejected: %v", err)
+	}
+}
+
+// options.Int 和 options.Float 读取的开关都要登记到 intFlags、floatFlags，否则无效取值不会报错
+func TestEveryNumericOptionIsRegistered(t *testing.T) {
+	pkgs, err := parser.ParseDir(token.NewFileSet(), ".", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, f := range pkgs["main"].Files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || (sel.Sel.Name != "Int" && sel.Sel.Name != "Float") {
+				return true
+			}
+			if x, ok := sel.X.(*ast.Ident); !ok || x.Name != "options" {
+				return true
+			}
+			lit, ok := call.Args[0].(*ast.BasicLit)
+			if !ok {
+				return true
+			}
+			name, _ := strconv.Unquote(lit.Value)
+			registered := intFlags
+			if sel.Sel.Name == "Float" {
+				registered = floatFlags
+			}
+			if !registered[name] {
+				t.Errorf("%s: --%s is read with options.%s but not registered", path, name, sel.Sel.Name)
+			}
+			return true
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 输出命令结果（JSON）：--output 写入文件，--compress=gzip 时压缩输出
+func printResult(result interface{}) error {
+	var out io.Writer = os.Stdout
+	if path := options.String("output", ""); path != "" {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	var output []byte
+	if options.String("format", "json") == "dot" {
+		graph, ok := result.(interface{ DOT() string })
+		if !ok {
+			return fmt.Errorf("--format=dot is only supported by analyze-graph")
+		}
+		output = []byte(graph.DOT())
+	} else if options.Has("human") {
+		text, ok := result.(interface{ Human() string })
+		if !ok {
+			return fmt.Errorf("--human is only supported by explain")
+		}
+		output = []byte(text.Human())
+	} else {
+		output = append(formatResult(result), '\n')
+	}
+	if options.String("compress", "") != "gzip" {
+		_, err := out.Write(output)
+		return err
+	}
+
+	gz := gzip.NewWriter(out)
+	if _, err := gz.Write(output); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// 检查输出相关选项
+func validateOutputOptions() error {
+	switch anchor := options.String("lens-anchor", "method-line"); anchor {
+	case "method-line", "above-method", "interface-line":
+	default:
+		return fmt.Errorf("unsupported --lens-anchor value: %q (supported: method-line, above-method, interface-line)", anchor)
+	}
+	switch format := options.String("format", "json"); format {
+	case "json", "dot":
+	default:
+		return fmt.Errorf("unsupported --format value: %q (supported: json, dot)", format)
+	}
+	if options.Has("stream") && (options.Has("compress") || options.Has("output")) {
+		return fmt.Errorf("--stream writes to stdout and cannot be combined with --compress or --output")
+	}
+	if !options.Has("compress") {
+		return nil
+	}
+	if compress := options.String("compress", ""); compress != "gzip" {
+		return fmt.Errorf("unsupported --compress value: %q (supported: gzip)", compress)
+	}
+	return nil
+}
+
+// 把命令结果编码为 JSON，--lsp 模式下把位置转换为 LSP 格式
+func formatResult(result interface{}) []byte {
+	output, _ := json.Marshal(result)
+	output = appendDiagnostics(appendWarnings(output))
+	if options.Has("lsp") {
+		output = toLSPLocations(output)
+	}
+	return appendMeta(output)
+}
+
+// LSP 模式下位置先编码为带 uri 和 0 基位置的中间形式，再由 toLSPLocations 组合成范围
+func (l Location) MarshalJSON() ([]byte, error) {
+	type plainLocation Location
+	if !options.Has("lsp") {
+		return json.Marshal(plainLocation(l))
+	}
+
+	line, character := l.Line, l.Column
+	if l.oneBased {
+		line, character = line-1, character-1
+	}
+	return json.Marshal(map[string]interface{}{
+		"uri":      fileURI(l.File),
+		"position": lspPosition{Line: max(line, 0), Character: max(character, 0)},
+	})
+}
+
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type lspRange struct {
+	Start lspPosition `json:"start"`
+	End   lspPosition `json:"end"`
+}
+
+type lspLocation struct {
+	URI   string   `json:"uri"`
+	Range lspRange `json:"range"`
+}
+
+// 把文件路径转换为 file:// URI
+func fileURI(file string) string {
+	if file == "" {
+		return ""
+	}
+	if abs, err := filepath.Abs(file); err == nil {
+		file = abs
+	}
+	path := filepath.ToSlash(file)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+// 把 location/endLocation（以及 xxxLocation/xxxEndLocation）成对组合为 LSP 的 { uri, range }
+func toLSPLocations(output []byte) []byte {
+	var tree interface{}
+	if err := json.Unmarshal(output, &tree); err != nil {
+		return output
+	}
+	converted, err := json.Marshal(convertLSPNode(tree))
+	if err != nil {
+		return output
+	}
+	return converted
+}
+
+func convertLSPNode(node interface{}) interface{} {
+	switch value := node.(type) {
+	case []interface{}:
+		for i := range value {
+			value[i] = convertLSPNode(value[i])
+		}
+	case map[string]interface{}:
+		for key, child := range value {
+			start, ok := lspIntermediate(child)
+			if !ok || isEndLocationKey(key) {
+				value[key] = convertLSPNode(child)
+				continue
+			}
+
+			uri, _ := start["uri"].(string)
+			location := lspLocation{URI: uri}
+			location.Range.Start = positionOf(start)
+			location.Range.End = location.Range.Start
+
+			endKey := endLocationKey(key)
+			if end, ok := lspIntermediate(value[endKey]); ok {
+				location.Range.End = positionOf(end)
+				delete(value, endKey)
+			}
+			value[key] = location
+		}
+		// 没有对应起始位置的结束位置单独转换
+		for key, child := range value {
+			if end, ok := lspIntermediate(child); ok {
+				uri, _ := end["uri"].(string)
+				position := positionOf(end)
+				value[key] = lspLocation{URI: uri, Range: lspRange{Start: position, End: position}}
+			}
+		}
+	}
+	return node
+}
+
+func endLocationKey(key string) string {
+	if key == "location" {
+		return "endLocation"
+	}
+	return strings.TrimSuffix(key, "Location") + "EndLocation"
+}
+
+func isEndLocationKey(key string) bool {
+	return key == "endLocation" || strings.HasSuffix(key, "EndLocation")
+}
+
+// 判断节点是否为 Location.MarshalJSON 输出的中间形式
+func lspIntermediate(node interface{}) (map[string]interface{}, bool) {
+	object, ok := node.(map[string]interface{})
+	if !ok || len(object) != 2 {
+		return nil, false
+	}
+	_, hasURI := object["uri"]
+	_, hasPosition := object["position"].(map[string]interface{})
+	return object, hasURI && hasPosition
+}
+
+func positionOf(object map[string]interface{}) lspPosition {
+	position := object["position"].(map[string]interface{})
+	line, _ := position["line"].(float64)
+	character, _ := position["character"].(float64)
+	return lspPosition{Line: int(line), Character: int(character)}
+}
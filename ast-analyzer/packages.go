@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// 由导入路径模式解析出的包
+type resolvedPackage struct {
+	ImportPath string
+	Dir        string
+}
+
+// 按包路径分组的分析结果
+type PackagesAnalysisResult struct {
+	Packages map[string]PackageAnalysisResult `json:"packages"`
+}
+
+// 目标是否按导入路径模式解析：已存在的目录（不带 /... 通配）仍按目录处理
+func isPackagePattern(target string) bool {
+	if strings.HasSuffix(target, "...") {
+		return true
+	}
+	info, err := os.Stat(target)
+	return err != nil || !info.IsDir()
+}
+
+// 目录中是否有参与分析的 Go 文件
+func hasGoFiles(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go") {
+			return true
+		}
+	}
+	return false
+}
+
+// 解析包模式：./internal/storage、github.com/acme/app/storage 或带 /... 的子树。
+// 相对路径和导入路径都相对于模块根目录（--root 或从当前目录向上查找 go.mod）
+func resolvePackagePattern(pattern string) ([]resolvedPackage, error) {
+	root := options.String("root", "")
+	if root == "" {
+		root = findModuleRoot(".")
+	}
+	if root == "" {
+		return nil, fmt.Errorf("cannot resolve %s: no go.mod found from the current directory (use --root)", pattern)
+	}
+	root, _ = filepath.Abs(root)
+	modulePath := parseGoMod(filepath.Join(root, "go.mod")).Path
+
+	base, recursive := pattern, false
+	if base == "..." || strings.HasSuffix(base, "/...") {
+		base, recursive = strings.TrimSuffix(strings.TrimSuffix(base, "..."), "/"), true
+	}
+
+	var dir string
+	switch {
+	case base == "" || base == ".":
+		dir = root
+	case base == modulePath:
+		dir = root
+	case modulePath != "" && strings.HasPrefix(base, modulePath+"/"):
+		dir = filepath.Join(root, filepath.FromSlash(strings.TrimPrefix(base, modulePath+"/")))
+	case strings.HasPrefix(base, "./") || strings.HasPrefix(base, "../"):
+		dir = filepath.Join(root, filepath.FromSlash(base))
+	default:
+		return nil, fmt.Errorf("package %s is not in module %s (%s)", pattern, modulePath, root)
+	}
+
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("package %s not found: %s does not exist", pattern, dir)
+	}
+
+	importPathOf := func(dir string) string {
+		rel, err := filepath.Rel(root, dir)
+		if err != nil || rel == "." {
+			return modulePath
+		}
+		return path.Join(modulePath, filepath.ToSlash(rel))
+	}
+
+	if !recursive {
+		if !hasGoFiles(dir) {
+			return nil, fmt.Errorf("package %s not found: no Go files in %s", pattern, dir)
+		}
+		return []resolvedPackage{{ImportPath: importPathOf(dir), Dir: dir}}, nil
+	}
+
+	var packages []resolvedPackage
+	limits := newWalkLimits(dir)
+	defer limits.report()
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return nil
+		}
+		name := info.Name()
+		if p != dir && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+			return filepath.SkipDir
+		}
+		if !limits.enter(p) {
+			return filepath.SkipDir
+		}
+		if hasGoFiles(p) {
+			packages = append(packages, resolvedPackage{ImportPath: importPathOf(p), Dir: p})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(packages) == 0 {
+		return nil, fmt.Errorf("pattern %s matched no packages", pattern)
+	}
+	return packages, nil
+}
+
+// 按包模式分析多个包，结果以导入路径为键
+func analyzePackagePattern(pattern string) (PackagesAnalysisResult, error) {
+	result := PackagesAnalysisResult{Packages: make(map[string]PackageAnalysisResult)}
+	packages, err := resolvePackagePattern(pattern)
+	if err != nil {
+		return result, err
+	}
+	for _, pkg := range packages {
+		result.Packages[pkg.ImportPath] = analyzePackageInterfaces(pkg.Dir)
+	}
+	return result, nil
+}
@@ -0,0 +1,117 @@
+package main
+
+import "strings"
+
+// 按包组织的接口关系，供工作区级别的视图一次性获取
+type PackageTreeResult struct {
+	Packages map[string]*PackageInterfaces `json:"packages"`
+}
+
+// 单个包中的接口、实现以及无法解析的嵌入引用
+type PackageInterfaces struct {
+	Dir        string                `json:"dir"`
+	Name       string                `json:"name"`
+	Interfaces []PackageInterface    `json:"interfaces"`
+	Unresolved []UnresolvedReference `json:"unresolved"`
+}
+
+type PackageInterface struct {
+	Name            string                  `json:"name"`
+	Location        Location                `json:"location"`
+	Methods         []string                `json:"methods"`
+	Implementations []PackageImplementation `json:"implementations"`
+}
+
+// 接口的实现类型；实现类型位于其他包时 external 为 true
+type PackageImplementation struct {
+	Type     string   `json:"type"`
+	Package  string   `json:"package"`
+	Location Location `json:"location"`
+	External bool     `json:"external"`
+}
+
+// 无法解析的嵌入接口引用
+type UnresolvedReference struct {
+	Interface string   `json:"interface"`
+	Reference string   `json:"reference"`
+	Location  Location `json:"location"`
+}
+
+// 分析目录树，结果以包路径（模块内为导入路径，否则为目录）为键
+func analyzePackageTree(directory string) PackageTreeResult {
+	result := PackageTreeResult{Packages: make(map[string]*PackageInterfaces)}
+	scan := scanDirectory(directory)
+
+	paths := make(map[string]string)
+	packagePath := func(dir string) string {
+		if p, ok := paths[dir]; ok {
+			return p
+		}
+		p := packageImportPath(dir)
+		if p == "" {
+			p = dir
+		}
+		paths[dir] = p
+		return p
+	}
+	entry := func(dir, name string) *PackageInterfaces {
+		key := packagePath(dir)
+		if result.Packages[key] == nil {
+			result.Packages[key] = &PackageInterfaces{
+				Dir:        dir,
+				Name:       name,
+				Interfaces: []PackageInterface{},
+				Unresolved: []UnresolvedReference{},
+			}
+		}
+		return result.Packages[key]
+	}
+
+	implementations := make(map[*InterfaceInfo][]PackageImplementation)
+	for _, match := range matchImplementations(scan) {
+		dir, typeName := splitTypeKey(match.TypeKey)
+		implementation := PackageImplementation{
+			Type:     typeName,
+			Package:  packagePath(dir),
+			External: dir != match.Interface.Package,
+		}
+		if info, ok := scan.Types[dir+":"+strings.TrimPrefix(typeName, "*")]; ok {
+			implementation.Location = info.Location
+		}
+		implementations[match.Interface] = append(implementations[match.Interface], implementation)
+	}
+
+	resolver := newInterfaceResolver()
+	for i := range scan.Interfaces {
+		resolver.add(&scan.Interfaces[i])
+	}
+
+	for i := range scan.Interfaces {
+		iface := &scan.Interfaces[i]
+		pkg := entry(iface.Package, iface.PackageName)
+
+		pkg.Interfaces = append(pkg.Interfaces, PackageInterface{
+			Name:            iface.Name,
+			Location:        iface.Location,
+			Methods:         append([]string{}, iface.Methods...),
+			Implementations: append([]PackageImplementation{}, implementations[iface]...),
+		})
+
+		for _, embed := range iface.Embeds {
+			if resolver.lookup(iface, embed) != nil || registryInterface(embed.ImportPath, embed.Name) != nil {
+				continue
+			}
+			reference := embed.Name
+			if embed.ImportPath != "" {
+				reference = embed.ImportPath + "." + embed.Name
+			}
+			pkg.Unresolved = append(pkg.Unresolved, UnresolvedReference{
+				Interface: iface.Name,
+				Reference: reference,
+				Location:  iface.Location,
+			})
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,85 @@
+package main
+
+import "unicode"
+
+// 接口方法命名模式的分类结果
+type InterfacePattern struct {
+	InterfaceName    string   `json:"interfaceName"`
+	Pattern          string   `json:"pattern"`
+	MatchedMethods   []string `json:"matchedMethods"`
+	UnmatchedMethods []string `json:"unmatchedMethods"`
+}
+
+type NamingPatternResult struct {
+	Patterns []InterfacePattern `json:"patterns"`
+}
+
+// 各命名模式对应的方法名前缀，按优先级排列
+var namingPatterns = []struct {
+	name     string
+	prefixes []string
+}{
+	{"CRUD", []string{"Create", "Insert", "Save", "Read", "Get", "Find", "List", "Update", "Delete", "Remove"}},
+	{"Lifecycle", []string{"Start", "Stop", "Reset", "Init", "Initialize", "Close", "Shutdown", "Open", "Run"}},
+	{"EventListener", []string{"On", "Handle"}},
+	{"Factory", []string{"New", "Make", "Build"}},
+	{"Observer", []string{"Subscribe", "Unsubscribe", "Notify", "Register", "Unregister", "Attach", "Detach", "Publish"}},
+}
+
+// 判断方法名是否以某个单词开头（前缀之后必须是新单词或结尾），例如 GetUser 匹配 Get，Getter 不匹配
+func hasWordPrefix(name, prefix string) bool {
+	if len(name) < len(prefix) || name[:len(prefix)] != prefix {
+		return false
+	}
+	if len(name) == len(prefix) {
+		return true
+	}
+	next := rune(name[len(prefix)])
+	return unicode.IsUpper(next) || unicode.IsDigit(next) || next == '_'
+}
+
+func matchesAnyPrefix(name string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if hasWordPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// 按命名模式对每个接口的方法进行分类，取匹配方法最多的模式，都不匹配时为 Custom
+func findMethodNamingPatterns(directory string) []InterfacePattern {
+	patterns := []InterfacePattern{}
+
+	for _, iface := range findAllInterfacesWithMethods(directory) {
+		if len(iface.Methods) == 0 {
+			continue
+		}
+
+		best := InterfacePattern{
+			InterfaceName:    iface.Name,
+			Pattern:          "Custom",
+			MatchedMethods:   []string{},
+			UnmatchedMethods: iface.Methods,
+		}
+		for _, pattern := range namingPatterns {
+			matched, unmatched := []string{}, []string{}
+			for _, method := range iface.Methods {
+				if matchesAnyPrefix(method, pattern.prefixes) {
+					matched = append(matched, method)
+				} else {
+					unmatched = append(unmatched, method)
+				}
+			}
+			if len(matched) > len(best.MatchedMethods) {
+				best.Pattern = pattern.name
+				best.MatchedMethods = matched
+				best.UnmatchedMethods = unmatched
+			}
+		}
+
+		patterns = append(patterns, best)
+	}
+
+	return patterns
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// --package-prefix：只保留文件路径或包导入路径以该前缀开头的实现。
+// 文件路径按相对于扫描目录的 / 分隔路径比较（也接受绝对路径），导入路径在模块中才有。
+// 在匹配之后过滤，接口的满足关系仍然基于整个扫描范围
+func filterByPackagePrefix(implementations []Implementation, directory string) []Implementation {
+	matches := packagePrefixMatcher(directory)
+	if matches == nil {
+		return implementations
+	}
+
+	filtered := implementations[:0]
+	for _, impl := range implementations {
+		if matches(impl.Location.File) {
+			filtered = append(filtered, impl)
+		}
+	}
+	return filtered
+}
+
+// 判断文件是否满足 --package-prefix；未指定前缀时返回 nil
+func packagePrefixMatcher(directory string) func(file string) bool {
+	prefix := options.String("package-prefix", "")
+	if prefix == "" {
+		return nil
+	}
+	prefix = filepath.ToSlash(strings.TrimPrefix(prefix, "./"))
+	root, _ := filepath.Abs(directory)
+
+	importPaths := make(map[string]string)
+	return func(file string) bool {
+		abs, _ := filepath.Abs(file)
+		if strings.HasPrefix(filepath.ToSlash(abs), prefix) {
+			return true
+		}
+		if rel, err := filepath.Rel(root, abs); err == nil && strings.HasPrefix(filepath.ToSlash(rel), prefix) {
+			return true
+		}
+		dir := filepath.Dir(abs)
+		importPath, ok := importPaths[dir]
+		if !ok {
+			importPath = packageImportPath(dir)
+			importPaths[dir] = importPath
+		}
+		return importPath != "" && strings.HasPrefix(importPath+"/", prefix)
+	}
+}
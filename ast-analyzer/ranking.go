@@ -0,0 +1,70 @@
+package main
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// 结果相对于查询来源文件的接近程度，数值越小越靠前
+func proximityRank(origin, file string) int {
+	originAbs, err1 := filepath.Abs(origin)
+	fileAbs, err2 := filepath.Abs(file)
+	if err1 != nil || err2 != nil {
+		return 3
+	}
+
+	switch {
+	case originAbs == fileAbs:
+		return 0
+	case filepath.Dir(originAbs) == filepath.Dir(fileAbs):
+		return 1
+	}
+
+	root := findModuleRoot(filepath.Dir(originAbs))
+	if root != "" && strings.HasPrefix(fileAbs, root+string(filepath.Separator)) {
+		return 2
+	}
+	return 3
+}
+
+// 按 --origin 指定的来源文件排序（同文件、同包、同模块，其余按路径字母顺序），
+// 再按 --limit 截断，保证截断后留下的是最相关的结果。提升的方法和 T/*T 的方法共用同一个位置，
+// 输入又来自 map 遍历，位置相同时按 name（例如接收者类型和方法名）排序，保证每次截断的结果一致
+func rankResults[T any](items []T, location func(T) Location, name func(T) string) []T {
+	origin := options.String("origin", "")
+	ranks := make(map[string]int)
+	rankOf := func(file string) int {
+		if origin == "" {
+			return 0
+		}
+		rank, ok := ranks[file]
+		if !ok {
+			rank = proximityRank(origin, file)
+			ranks[file] = rank
+		}
+		return rank
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := location(items[i]), location(items[j])
+		if rankA, rankB := rankOf(a.File), rankOf(b.File); rankA != rankB {
+			return rankA < rankB
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		return name(items[i]) < name(items[j])
+	})
+
+	if limit := options.Int("limit", 0); limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Outer 和 Inner 通过嵌入 Base 获得的 Bar 与 Base.Bar 位置相同，--limit 截断后的结果每次都应一致
+func TestRankResultsIsDeterministicForSharedLocations(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"a.go": `package p
+
+type Barer interface{ Bar() }
+
+type Base struct{}
+
+func (*Base) Bar() {}
+
+type Outer struct{ *Base }
+
+type Inner struct{ *Base }
+`,
+	})
+
+	var first []string
+	for i := 0; i < 20; i++ {
+		result := runArgs(t, "find-implementations", dir, "Bar", "--origin", dir+"/a.go", "--limit", "2").(AnalysisResult)
+		got := receiverTypes(result.Implementations)
+		if len(got) != 2 {
+			t.Fatalf("run %d: got %v, want 2 results", i, got)
+		}
+		if first == nil {
+			first = got
+			continue
+		}
+		if !reflect.DeepEqual(got, first) {
+			t.Fatalf("run %d: got %v, first run returned %v", i, got, first)
+		}
+	}
+}
+
+func TestRankResultsTieBreaks(t *testing.T) {
+	at := func(line, column int) Location { return Location{File: "/m/a.go", Line: line, Column: column} }
+	items := []Implementation{
+		{ReceiverType: "Outer", MethodName: "Bar", Location: at(3, 0)},
+		{ReceiverType: "*Base", MethodName: "Bar", Location: at(3, 0)},
+		{ReceiverType: "Late", MethodName: "Bar", Location: at(3, 9)},
+		{ReceiverType: "Base", MethodName: "Bar", Location: at(3, 0)},
+		{ReceiverType: "First", MethodName: "Bar", Location: at(1, 4)},
+	}
+	ranked := rankResults(items,
+		func(impl Implementation) Location { return impl.Location },
+		func(impl Implementation) string { return impl.ReceiverType + "." + impl.MethodName })
+
+	want := []string{"First", "*Base", "Base", "Outer", "Late"}
+	if got := receiverTypes(ranked); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
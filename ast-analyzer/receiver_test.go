@@ -0,0 +1,87 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"testing"
+)
+
+func TestGetReceiverType(t *testing.T) {
+	tests := []struct {
+		receiver, want string
+	}{
+		{"T", "T"},
+		{"*T", "*T"},
+		{"(T)", "T"},
+		{"(*T)", "*T"},
+		{"*(T)", "*T"},
+		{"((T))", "T"},
+		{"T[K]", "T"},
+		{"*T[K]", "*T"},
+		{"*T[K, V]", "*T"},
+		{"(*T[K])", "*T"},
+	}
+	for _, tt := range tests {
+		src := "package p\n\nfunc (r " + tt.receiver + ") M() {}\n"
+		f, err := parser.ParseFile(token.NewFileSet(), "p.go", src, 0)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.receiver, err)
+		}
+		if got := getReceiverType(f.Decls[0].(*ast.FuncDecl).Recv); got != tt.want {
+			t.Errorf("getReceiverType(%s) = %q, want %q", tt.receiver, got, tt.want)
+		}
+	}
+}
+
+// 每种接收者写法的方法都能作为接口的实现被找到
+func TestFindImplementationsForEveryReceiverForm(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"a.go": `package p
+
+type Runner interface{ Run() }
+
+type Value struct{}
+
+func (Value) Run() {}
+
+type Pointer struct{}
+
+func (*Pointer) Run() {}
+
+type ParenValue struct{}
+
+func (v (ParenValue)) Run() {}
+
+type ParenPointer struct{}
+
+func (p (*ParenPointer)) Run() {}
+
+type StarParen struct{}
+
+func (s *(StarParen)) Run() {}
+
+type Generic[K comparable] struct{}
+
+func (g Generic[K]) Run() {}
+
+type GenericPointer[K comparable, V any] struct{}
+
+func (g *GenericPointer[K, V]) Run() {}
+`,
+	})
+
+	result := runArgs(t, "find-implementations", dir, "Run").(AnalysisResult)
+	got := make(map[string]bool)
+	for _, receiver := range receiverTypes(result.Implementations) {
+		got[receiver] = true
+	}
+	want := map[string]bool{
+		"Value": true, "*Pointer": true, "ParenValue": true, "*ParenPointer": true,
+		"*StarParen": true, "Generic": true, "*GenericPointer": true,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got implementations %v, want %v", got, want)
+	}
+}
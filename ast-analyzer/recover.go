@@ -0,0 +1,35 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime/debug"
+)
+
+// 命令执行中发生的 panic（通常是遇到了意料之外的 AST 结构）
+type panicError struct {
+	value interface{}
+}
+
+func (e *panicError) Error() string {
+	return fmt.Sprintf("internal error: %v", e.value)
+}
+
+// 执行命令，把 panic 转换为 *panicError，堆栈输出到标准错误。
+// 只能恢复当前 goroutine 中的 panic；并行解析的 worker 中 go/parser 自己会恢复解析错误
+func safeRunCommand(command, target string) (result interface{}, err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			fmt.Fprintf(os.Stderr, "命令 %s 执行时发生 panic: %v\n%s", command, value, debug.Stack())
+			result, err = nil, &panicError{value: value}
+		}
+	}()
+	return runCommand(command, target)
+}
+
+// 命令行模式下 panic 的输出：标准输出仍是一个 JSON 对象，便于插件统一处理
+func printPanic(err *panicError) {
+	output, _ := json.Marshal(map[string]interface{}{"error": err.Error(), "panic": true})
+	os.Stdout.Write(append(output, '\n'))
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// 把 broken.go 中 Broken 方法的名称置空：解析器从不产生这样的 AST，分析代码遇到时会 panic
+func malformBrokenMethods(t *testing.T) {
+	saved := parseSource
+	parseSource = func(fset *token.FileSet, path string, src interface{}, mode parser.Mode) (*ast.File, error) {
+		f, err := saved(fset, path, src, mode)
+		if f != nil && filepath.Base(path) == "broken.go" {
+			for _, decl := range f.Decls {
+				if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name == "Broken" {
+					fn.Name = nil
+				}
+			}
+		}
+		return f, err
+	}
+	t.Cleanup(func() { parseSource = saved })
+}
+
+const brokenSource = "package p\n\ntype B struct{}\n\nfunc (B) Broken() {}\n"
+
+func TestMalformedASTBecomesPanicError(t *testing.T) {
+	dir := writeTree(t, map[string]string{"a.go": serveFixtureInterface, "broken.go": brokenSource})
+	malformBrokenMethods(t)
+
+	saved := options
+	t.Cleanup(func() { options = saved })
+	options = parseOptions([]string{"find-implementations", dir, "Run"})
+	startMeta(dir)
+
+	result, err := safeRunCommand("find-implementations", dir)
+	var panicErr *panicError
+	if !errors.As(err, &panicErr) || result != nil {
+		t.Fatalf("got %v, %v; want a *panicError and no result", result, err)
+	}
+}
+
+// 命令行模式：标准输出是 {"error": ..., "panic": true}，退出码非零
+func TestMalformedASTExitsWithJSONError(t *testing.T) {
+	if dir := os.Getenv("AST_ANALYZER_PANIC_DIR"); dir != "" {
+		malformBrokenMethods(t)
+		os.Args = []string{"ast-analyzer", "find-implementations", dir, "Run"}
+		main()
+		return
+	}
+
+	dir := writeTree(t, map[string]string{"a.go": serveFixtureInterface, "broken.go": brokenSource})
+	cmd := exec.Command(os.Args[0], "-test.run=^TestMalformedASTExitsWithJSONError$")
+	cmd.Env = append(os.Environ(), "AST_ANALYZER_PANIC_DIR="+dir)
+	stdout, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		t.Fatalf("got exit error %v, want exit status 1", err)
+	}
+
+	var output struct {
+		Error string `json:"error"`
+		Panic bool   `json:"panic"`
+	}
+	if err := json.Unmarshal(stdout, &output); err != nil {
+		t.Fatalf("stdout is not a JSON object: %v\n%s", err, stdout)
+	}
+	if !output.Panic || !strings.HasPrefix(output.Error, "internal error: ") {
+		t.Fatalf("got %+v, want a panic error", output)
+	}
+}
+
+// serve 模式：请求中的 panic 变成带 panic 标记的响应
+func TestServeRequestPanicBecomesResponse(t *testing.T) {
+	dir := writeTree(t, map[string]string{"a.go": serveFixtureInterface, "broken.go": brokenSource})
+	malformBrokenMethods(t)
+	saved := options
+	t.Cleanup(func() { options = saved })
+
+	response := handleServeRequest(serveRequest{ID: json.RawMessage("1"), Args: []string{"find-implementations", dir, "Run"}})
+	if !response.Panic || !strings.HasPrefix(response.Error, "internal error: ") || response.Result != nil {
+		t.Fatalf("got %+v, want a panic response", response)
+	}
+}
+
+// serve 模式：建立索引和处理请求时的 panic 都变成响应，之后的请求照常处理
+func TestServeSurvivesPanics(t *testing.T) {
+	dir := writeTree(t, map[string]string{"a.go": serveFixtureInterface, "broken.go": brokenSource})
+	malformBrokenMethods(t)
+	client := startServe(t, dir)
+	query := []string{"find-implementations", dir, "Run"}
+
+	for i := 0; i < 2; i++ {
+		client.send(map[string]interface{}{"args": query})
+		response := client.receive()
+		if !response.Panic || !strings.HasPrefix(response.Error, "internal error: ") {
+			t.Fatalf("request %d: got %+v, want a panic response", i, response)
+		}
+	}
+
+	if err := os.Remove(filepath.Join(dir, "broken.go")); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := client.implementors(query...), []string{"A"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("after removing broken.go: got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// 循环中既等待又检查错误的接口实现方法，通常是重试逻辑
+type RetryPattern struct {
+	ReceiverType string   `json:"receiverType"`
+	MethodName   string   `json:"methodName"`
+	Location     Location `json:"location"`
+	// 从循环条件中提取的重试次数，例如 i < 3 中的 3、attempt <= maxRetries 中的 maxRetries；无法提取时为空
+	MaxRetries string `json:"maxRetries"`
+}
+
+type RetryPatternResult struct {
+	Patterns []RetryPattern `json:"patterns"`
+}
+
+// 是否为 time.Sleep(...) 或 time.After(...) 调用
+func isSleepCall(n ast.Node) bool {
+	call, ok := n.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := selector.X.(*ast.Ident)
+	return ok && pkg.Name == "time" && (selector.Sel.Name == "Sleep" || selector.Sel.Name == "After")
+}
+
+// 是否为错误检查：err != nil、err == nil 这类与 nil 比较的错误变量
+func isErrorCheck(n ast.Node) bool {
+	binary, ok := n.(*ast.BinaryExpr)
+	if !ok || (binary.Op != token.NEQ && binary.Op != token.EQL) {
+		return false
+	}
+	isNil := func(expr ast.Expr) bool {
+		ident, ok := expr.(*ast.Ident)
+		return ok && ident.Name == "nil"
+	}
+	isErr := func(expr ast.Expr) bool {
+		ident, ok := expr.(*ast.Ident)
+		return ok && strings.Contains(strings.ToLower(ident.Name), "err")
+	}
+	return (isErr(binary.X) && isNil(binary.Y)) || (isNil(binary.X) && isErr(binary.Y))
+}
+
+// 从循环头中提取重试次数：for i := 0; i < N; i++ 取 N，for i := range N 取 N（仅限字面量和标识符）
+func maxRetriesOf(loop ast.Node) string {
+	switch l := loop.(type) {
+	case *ast.ForStmt:
+		if binary, ok := l.Cond.(*ast.BinaryExpr); ok {
+			switch binary.Op {
+			case token.LSS, token.LEQ:
+				return types.ExprString(binary.Y)
+			case token.GTR, token.GEQ:
+				return types.ExprString(binary.X)
+			}
+		}
+	case *ast.RangeStmt:
+		switch l.X.(type) {
+		case *ast.BasicLit, *ast.Ident:
+			return types.ExprString(l.X)
+		}
+	}
+	return ""
+}
+
+// 查找方法体中包含重试循环的接口实现方法：for 或 range 循环体中既有 time.Sleep 又有错误检查。
+// 匹配的循环内部不再查找，嵌套循环只输出最外层
+func findRetryPatterns(directory string) []RetryPattern {
+	results := []RetryPattern{}
+
+	for _, method := range uniqueImplementedMethods(matchAllImplementations(directory)) {
+		if method.fset == nil || method.FuncDecl.Body == nil {
+			continue
+		}
+		ast.Inspect(method.FuncDecl.Body, func(n ast.Node) bool {
+			var body *ast.BlockStmt
+			switch loop := n.(type) {
+			case *ast.ForStmt:
+				body = loop.Body
+			case *ast.RangeStmt:
+				body = loop.Body
+			default:
+				return true
+			}
+
+			sleeps, checksError := false, false
+			ast.Inspect(body, func(inner ast.Node) bool {
+				sleeps = sleeps || isSleepCall(inner)
+				checksError = checksError || isErrorCheck(inner)
+				return !(sleeps && checksError)
+			})
+			if !sleeps || !checksError {
+				return true
+			}
+
+			pos := method.fset.Position(n.Pos())
+			results = append(results, RetryPattern{
+				ReceiverType: method.ReceiverType,
+				MethodName:   method.Name,
+				Location:     Location{File: pos.Filename, Line: pos.Line - 1, Column: pos.Column - 1},
+				MaxRetries:   maxRetriesOf(n),
+			})
+			return false
+		})
+	}
+
+	return results
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"go/ast"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// 流式接口的方法返回接口自身，而同名的具体方法返回具体的接收者类型：
+// 签名不一致，该类型不能替代接口使用
+type SelfReturnViolation struct {
+	ReceiverType string `json:"receiverType"`
+	MethodName   string `json:"methodName"`
+	// 具体方法返回的类型，例如 *Builder
+	ReturnType string `json:"returnType"`
+	// 同名方法返回自身的接口
+	Interfaces []string `json:"interfaces"`
+	Location   Location `json:"location"`
+}
+
+type SelfReturnResult struct {
+	Violations []SelfReturnViolation `json:"violations"`
+}
+
+// 结果列表中是否有名为 name 的类型（同包的 T 或 *T）
+func returnsNamed(results *ast.FieldList, name string) (ast.Expr, bool) {
+	if results == nil {
+		return nil, false
+	}
+	for _, field := range results.List {
+		expr := field.Type
+		if star, ok := expr.(*ast.StarExpr); ok {
+			expr = star.X
+		}
+		if ident, ok := expr.(*ast.Ident); ok && ident.Name == name {
+			return field.Type, true
+		}
+	}
+	return nil, false
+}
+
+// 查找返回接收者具体类型的方法，而接口中同名方法返回的是接口自身（合法的流式接口写法）
+func findSelfReturns(directory string) []SelfReturnViolation {
+	results := []SelfReturnViolation{}
+
+	scan := scanDirectory(directory)
+	// 方法名 -> 返回接口自身的接口
+	fluent := make(map[string][]string)
+	for i := range scan.Interfaces {
+		iface := &scan.Interfaces[i]
+		for _, spec := range iface.Specs {
+			if spec.Func == nil || spec.embedded {
+				continue
+			}
+			if _, ok := returnsNamed(spec.Func.Results, iface.Name); ok {
+				fluent[spec.Name] = append(fluent[spec.Name], iface.PackageName+"."+iface.Name)
+			}
+		}
+	}
+	if len(fluent) == 0 {
+		return results
+	}
+
+	// T 和 *T 的方法集中是同一份声明，按位置去重
+	seen := make(map[Location]bool)
+	for _, methods := range scan.TypeMethods {
+		for name, method := range methods {
+			interfaces := fluent[name]
+			if len(interfaces) == 0 || method.FuncDecl == nil || method.fset == nil || method.PromotedFrom != "" || seen[method.Location] {
+				continue
+			}
+			seen[method.Location] = true
+
+			receiver := strings.TrimPrefix(method.ReceiverType, "*")
+			returned, ok := returnsNamed(method.FuncDecl.Type.Results, receiver)
+			if !ok {
+				continue
+			}
+			pos := method.fset.Position(returned.Pos())
+			results = append(results, SelfReturnViolation{
+				ReceiverType: method.ReceiverType,
+				MethodName:   name,
+				ReturnType:   types.ExprString(returned),
+				Interfaces:   interfaces,
+				Location:     Location{File: pos.Filename, Line: pos.Line - 1, Column: pos.Column - 1},
+			})
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i].Location, results[j].Location
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	return results
+}
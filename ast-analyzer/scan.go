@@ -0,0 +1,325 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Go 文件被扫描排除的原因
+const (
+	excludedTest     = "test"
+	excludedTooLarge = "tooLarge"
+)
+
+// 按扫描规则遍历目录：跳过 vendor、隐藏目录，遵守 --max-depth 和 --max-dirs；跟随指向目录的符号链接，
+// 但只进入尚未进入过的真实目录，不会循环。通过符号链接重复出现的文件按 dedupeSymlinked 只保留一个路径。
+// 参与分析的 Go 文件按遍历顺序交给 include；被排除的 Go 文件（超过 --max-file-size 的文件、
+// 未指定 --include-tests 时的测试文件）连同原因交给 exclude。这里只用到 FileInfo，不读取任何文件
+func walkSourceFiles(directory string, include func(path string), exclude func(path string, info os.FileInfo, reason string)) error {
+	type entry struct {
+		path   string
+		info   os.FileInfo
+		reason string
+	}
+	var entries []entry
+	limits := newWalkLimits(directory)
+	defer limits.report()
+	// 已进入目录的真实路径
+	entered := make(map[string]bool)
+
+	var walk func(root string) error
+	walk = func(root string) error {
+		return sourceFS.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			// 请求被取消后不再遍历
+			if requestCtx.Err() != nil {
+				return filepath.SkipAll
+			}
+
+			if info.Mode()&os.ModeSymlink != 0 {
+				target, ok := symlinkTarget(path)
+				if !ok {
+					return nil
+				}
+				if !target.IsDir() {
+					// 文件大小按链接指向的文件判断
+					info = target
+				} else if !strings.Contains(path, "vendor") && !strings.HasPrefix(info.Name(), ".") && !entered[canonicalPath(path)] {
+					// 末尾的分隔符让遍历从链接指向的目录开始
+					return walk(path + string(filepath.Separator))
+				} else {
+					return nil
+				}
+			}
+
+			if info.IsDir() && path != directory && (strings.Contains(path, "vendor") || strings.HasPrefix(info.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			if info.IsDir() {
+				if !limits.enter(path) {
+					return filepath.SkipDir
+				}
+				entered[canonicalPath(path)] = true
+				return nil
+			}
+
+			if !strings.HasSuffix(path, ".go") {
+				return nil
+			}
+			switch {
+			// 先检查大小，过大的文件不读入内存
+			case fileTooLarge(info.Size()):
+				entries = append(entries, entry{path, info, excludedTooLarge})
+			case strings.HasSuffix(path, "_test.go") && !options.Has("include-tests"):
+				entries = append(entries, entry{path, info, excludedTest})
+			default:
+				entries = append(entries, entry{path: path, info: info})
+			}
+			return nil
+		})
+	}
+	if err := walk(directory); err != nil {
+		return err
+	}
+
+	paths := make([]string, len(entries))
+	for i, e := range entries {
+		paths[i] = e.path
+	}
+	for _, i := range dedupeSymlinked(directory, paths) {
+		if e := entries[i]; e.reason == "" {
+			include(e.path)
+		} else {
+			exclude(e.path, e.info, e.reason)
+		}
+	}
+	return nil
+}
+
+// 符号链接指向的文件或目录的信息，链接失效时返回 false
+func symlinkTarget(path string) (os.FileInfo, bool) {
+	file, err := sourceFS.Open(path)
+	if err != nil {
+		return nil, false
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	return info, err == nil
+}
+
+// 遍历目录中参与分析的 Go 文件（规则见 walkSourceFiles），解析后按遍历顺序逐个回调。
+// 解析由 --jobs 个 worker 并行完成（默认 runtime.NumCPU()），回调始终在调用方的 goroutine 中顺序执行；
+// --jobs 1 时不启动 goroutine，遍历后依次解析
+func walkGoFiles(directory string, visit func(path string, f *ast.File, fset *token.FileSet)) error {
+	fset := token.NewFileSet()
+	jobs := parseJobs()
+
+	var paths []string
+	err := walkSourceFiles(directory, func(path string) {
+		if jobs == 1 {
+			if f, err := parseGoFile(fset, path); err == nil {
+				recordScannedFile(path)
+				visit(path, f, fset)
+			} else {
+				reportParseError(err)
+			}
+			return
+		}
+		paths = append(paths, path)
+	}, func(path string, info os.FileInfo, reason string) {
+		if reason == excludedTooLarge {
+			fmt.Fprintf(os.Stderr, "跳过过大的文件: %s (%d 字节)\n", path, info.Size())
+		}
+	})
+	if err != nil || len(paths) == 0 {
+		return err
+	}
+
+	parseParallel(fset, paths, jobs, func(path string, f *ast.File) {
+		recordScannedFile(path)
+		visit(path, f, fset)
+	})
+	return nil
+}
+
+// 路径解析符号链接后的真实路径，解析失败时返回原路径。EvalSymlinks 需要逐级 lstat，结果在进程内缓存
+var canonicalPaths sync.Map
+
+func canonicalPath(path string) string {
+	if canonical, ok := canonicalPaths.Load(path); ok {
+		return canonical.(string)
+	}
+	canonical, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		canonical = path
+	}
+	if abs, err := filepath.Abs(canonical); err == nil {
+		canonical = abs
+	}
+	canonicalPaths.Store(path, canonical)
+	return canonical
+}
+
+// 去掉通过符号链接重复出现的文件，同一个真实文件只保留一个路径：优先保留不经过符号链接的路径
+// （root 的真实路径加上相对路径就是文件的真实路径），没有这样的路径时保留字典序最小的。
+// 结果与遍历顺序无关，返回保留的下标，维持 paths 中的顺序
+func dedupeSymlinked(root string, paths []string) []int {
+	rootCanonical := canonicalPath(root)
+	direct := func(path, canonical string) bool {
+		rel, err := filepath.Rel(root, path)
+		return err == nil && filepath.Join(rootCanonical, rel) == canonical
+	}
+
+	// 真实路径 -> 保留的下标
+	kept := make(map[string]int)
+	for i, path := range paths {
+		canonical := canonicalPath(path)
+		j, seen := kept[canonical]
+		if !seen {
+			kept[canonical] = i
+			continue
+		}
+		if d, dj := direct(path, canonical), direct(paths[j], canonical); d && !dj || d == dj && path < paths[j] {
+			kept[canonical] = i
+		}
+	}
+
+	indexes := make([]int, 0, len(kept))
+	for i, path := range paths {
+		if kept[canonicalPath(path)] == i {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// 文件所属包的标识：包目录；外部测试包（package foo_test）与 foo 同目录但是不同的包，标识为 目录_test
+func packageDir(path string, f *ast.File) string {
+	dir := filepath.Dir(path)
+	if strings.HasSuffix(f.Name.Name, "_test") && strings.HasSuffix(path, "_test.go") {
+		return dir + "_test"
+	}
+	return dir
+}
+
+// 实现类型是否位于接口所在包的外部测试包中
+func inExternalTestPackage(ifacePkg, typePkg string) bool {
+	return typePkg == ifacePkg+"_test"
+}
+
+// 并行解析时的 worker 数量：--jobs N，默认 runtime.NumCPU()
+func parseJobs() int {
+	return max(options.Int("jobs", runtime.NumCPU()), 1)
+}
+
+// 单个文件的解析结果
+type parseOutcome struct {
+	file *ast.File
+	err  error
+}
+
+// 用 jobs 个 worker 解析文件，按 paths 的顺序回调（解析失败的文件跳过，编码错误记为诊断）。
+// 已解析但尚未回调的文件最多 4*jobs 个，避免大目录的 AST 全部堆在内存里
+func parseParallel(fset *token.FileSet, paths []string, jobs int, visit func(path string, f *ast.File)) {
+	parsed := make([]chan parseOutcome, len(paths))
+	for i := range parsed {
+		parsed[i] = make(chan parseOutcome, 1)
+	}
+	window := make(chan struct{}, 4*jobs)
+	queue := make(chan int)
+
+	go func() {
+		for i := range paths {
+			window <- struct{}{}
+			queue <- i
+		}
+		close(queue)
+	}()
+	for w := 0; w < min(jobs, len(paths)); w++ {
+		go func() {
+			for i := range queue {
+				f, err := parseGoFile(fset, paths[i])
+				parsed[i] <- parseOutcome{file: f, err: err}
+			}
+		}()
+	}
+
+	for i, path := range paths {
+		// 诊断只在当前 goroutine 中记录
+		outcome := <-parsed[i]
+		if outcome.err != nil {
+			reportParseError(outcome.err)
+		} else {
+			visit(path, outcome.file)
+		}
+		<-window
+	}
+}
+
+// 扫描解析过的文件；同一次扫描的文件共用一个 FileSet
+type scannedFile struct {
+	path string
+	file *ast.File
+	fset *token.FileSet
+}
+
+// 一次遍历的扫描结果：展开后的接口和所有类型的方法
+type ScanResult struct {
+	Interfaces  []InterfaceInfo
+	TypeMethods map[string]map[string]*MethodInfo
+	Types       map[string]*TypeInfo
+	// 嵌入字段之间有歧义、没有被提升的方法：类型键 -> 方法名 -> 提供该方法的嵌入字段
+	ambiguous map[string]map[string][]string
+	// 扫描时解析的文件（按遍历顺序），需要语法树的分析直接复用，不必重新遍历目录
+	files []scannedFile
+	// 本次扫描的字符串驻留表，不与其他扫描共享
+	interner stringInterner
+	// 扫描的目录，以及包所在的模块（模块根目录 -> 模块，在第一次使用时查找）
+	directory  string
+	mainModule string
+	modules    map[string]*ModuleRef
+	modulesMu  sync.Mutex
+	// 包目录 -> 导入路径；导入关系图在第一次使用时构建
+	imports         map[string]map[string]bool
+	importGraph     *importGraph
+	importGraphOnce sync.Once
+}
+
+// 遍历一次目录，同时收集接口定义和类型方法
+func scanDirectory(directory string) *ScanResult {
+	if scan := cachedScan(directory); scan != nil {
+		return scan
+	}
+
+	scan := &ScanResult{
+		TypeMethods: make(map[string]map[string]*MethodInfo),
+		Types:       make(map[string]*TypeInfo),
+		imports:     make(map[string]map[string]bool),
+		interner:    make(stringInterner),
+		directory:   directory,
+	}
+
+	err := walkGoFiles(directory, func(path string, f *ast.File, fset *token.FileSet) {
+		scan.Interfaces = append(scan.Interfaces, extractInterfaceInfos(f, fset, path, scan.interner)...)
+		collectTypeMethods(f, fset, scan.TypeMethods, scan.interner)
+		collectTypeSpecs(f, fset, path, scan.Types)
+		recordImports(scan.imports, packageDir(path, f), f)
+		scan.files = append(scan.files, scannedFile{path: path, file: f, fset: fset})
+	})
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "扫描目录时出错: %v\n", err)
+	}
+
+	scan.Interfaces = flattenInterfaces(scan.Interfaces)
+	scan.ambiguous = buildMethodSets(scan.TypeMethods, scan.Types, scan.Interfaces)
+	return scan
+}
@@ -0,0 +1,398 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// serve 模式的请求：每行一个 JSON 对象，args 与命令行参数相同；
+// method 为 initialize 时协商后续响应的编码，为 cancel 时取消 params.id 指定的请求（没有响应）
+type serveRequest struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Args   []string        `json:"args"`
+	Params struct {
+		Encoding string          `json:"encoding"`
+		ID       json.RawMessage `json:"id"`
+	} `json:"params"`
+}
+
+// serve 模式的响应；索引仍在建立且使用 partial 策略时 warming 为 true、result 为空。
+// Result 是命令的原始结果，由 responseEncoder 决定如何编码
+type serveResponse struct {
+	ID      json.RawMessage `json:"id,omitempty"`
+	Warming bool            `json:"warming,omitempty"`
+	Result  interface{}     `json:"result"`
+	Error   string          `json:"error,omitempty"`
+	// 命令执行中发生了 panic，Error 为其描述
+	Panic bool `json:"panic,omitempty"`
+}
+
+// 后台建立的目录索引
+type scanIndex struct {
+	directory string
+	// 建立索引时使用的选项，以及其中影响扫描结果的部分（scanOptionsKey）
+	options *Options
+	key     string
+	ready   chan struct{}
+	scan    *ScanResult
+	files   []string
+	// 建立索引时产生的诊断，每次命中缓存时重新报告
+	diagnostics []Diagnostic
+	// 建立索引前各目录的列表摘要，用于发现新增、删除和修改的文件
+	digests map[string]uint64
+}
+
+// 影响扫描结果的选项：选项不同的请求不能共用同一个索引
+var scanOptionNames = []string{
+	"env", "goroot", "include-ignored", "include-tests", "max-depth",
+	"max-dirs", "max-file-size", "parse-timeout", "stdlib-embeddings",
+}
+
+func scanOptionsKey(opts *Options) string {
+	var key strings.Builder
+	for _, name := range scanOptionNames {
+		if opts.Has(name) {
+			fmt.Fprintf(&key, "--%s=%q", name, opts.Values(name))
+		}
+	}
+	return key.String()
+}
+
+// serve 模式下的索引，按扫描选项区分；第一个是启动时按 serve 自身的选项建立的，
+// 其他的在出现新的选项组合时建立，最多保留 maxServeIndexes 个。普通命令行调用时为空
+var serveIndexes []*scanIndex
+
+const maxServeIndexes = 4
+
+// options、diagnostics 和 scanStats 是全局状态。serve 模式下后台建立索引、处理请求和编码响应
+// 都要持有这把锁，避免请求循环替换它们时建立索引的 goroutine 正在读写
+var analysisMu sync.Mutex
+
+// 正在执行的 serve 请求的上下文，请求被取消时 Done；不在请求中时永远不会结束。同样由 analysisMu 保护
+var requestCtx = context.Background()
+
+func newScanIndex(directory string, opts *Options) *scanIndex {
+	return &scanIndex{directory: directory, options: opts, key: scanOptionsKey(opts), ready: make(chan struct{})}
+}
+
+// 在后台扫描目录，完成后关闭 ready
+func (index *scanIndex) build() {
+	go func() {
+		analysisMu.Lock()
+		defer analysisMu.Unlock()
+		index.run()
+	}()
+}
+
+// 以索引自己的选项扫描目录，调用方需要持有 analysisMu；完成后恢复调用方的选项
+func (index *scanIndex) run() {
+	defer close(index.ready)
+	saved := options
+	defer func() { options = saved }()
+	// 扫描中的 panic 不能让 serve 退出：索引留空，由请求自己扫描，panic 经 safeRunCommand 报告给客户端
+	defer func() {
+		if value := recover(); value != nil {
+			fmt.Fprintf(os.Stderr, "建立索引时发生 panic: %v\n%s", value, debug.Stack())
+			index.scan, index.digests = nil, nil
+		}
+	}()
+	options = index.options
+
+	startMeta(index.directory)
+	// 先取摘要：扫描期间发生的修改会在下一次请求时被发现
+	index.digests = listingDigests(index.directory)
+	scan := scanDirectory(index.directory)
+	for file := range scanStats.files {
+		index.files = append(index.files, file)
+	}
+	index.diagnostics = diagnostics.list
+	index.scan = scan
+}
+
+func (index *scanIndex) isReady() bool {
+	select {
+	case <-index.ready:
+		return true
+	default:
+		return false
+	}
+}
+
+// 目录列表与建立索引时不同（文件被新增、删除或修改）
+func (index *scanIndex) stale() bool {
+	return !sameDigests(index.digests, listingDigests(index.directory))
+}
+
+// 以索引自己的选项同步重建，调用方需要持有 analysisMu
+func (index *scanIndex) refresh() {
+	index.ready = make(chan struct{})
+	index.scan, index.files, index.diagnostics = nil, nil, nil
+	index.run()
+}
+
+// 与当前选项对应的索引，没有时返回 nil
+func serveIndexFor(opts *Options) *scanIndex {
+	key := scanOptionsKey(opts)
+	for _, index := range serveIndexes {
+		if index.key == key {
+			return index
+		}
+	}
+	return nil
+}
+
+// 为当前请求准备索引：请求的目标是 serve 目录、选项组合第一次出现时同步建立（超过上限时丢弃
+// 最早建立的非默认索引），目录列表变化时以索引自己的选项重建。调用方需要持有 analysisMu
+func prepareServeIndex(target string) {
+	if len(serveIndexes) == 0 {
+		return
+	}
+	index := serveIndexFor(options)
+	if index == nil {
+		if !sameDirectory(target, serveIndexes[0].directory) {
+			return
+		}
+		index = newScanIndex(serveIndexes[0].directory, options)
+		if len(serveIndexes) >= maxServeIndexes {
+			serveIndexes = append(serveIndexes[:1], serveIndexes[2:]...)
+		}
+		serveIndexes = append(serveIndexes, index)
+		index.run()
+	} else if index.isReady() && index.stale() {
+		index.refresh()
+	}
+	// 请求被取消时扫描结果不完整，清空摘要使下一次请求重建
+	if requestCtx.Err() != nil {
+		index.digests = nil
+	}
+}
+
+// 索引已建立、目录和扫描选项都相同时返回缓存的扫描结果
+func cachedScan(directory string) *ScanResult {
+	index := serveIndexFor(options)
+	if index == nil || !index.isReady() || !sameDirectory(index.directory, directory) {
+		return nil
+	}
+	for _, file := range index.files {
+		recordScannedFile(file)
+	}
+	for _, d := range index.diagnostics {
+		reportDiagnostic(d)
+	}
+	return index.scan
+}
+
+func sameDirectory(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
+
+// 运行 serve 模式：--warming=block 时请求等待索引就绪，--warming=partial 时立即返回 warming 响应
+func serve(directory string) error {
+	return serveStream(directory, os.Stdin, os.Stdout)
+}
+
+// 从 in 逐行读取请求，把响应写到 out。in 归 serveStream 所有，返回时如果可以关闭就会被关闭
+func serveStream(directory string, in io.Reader, out io.Writer) error {
+	warming := options.String("warming", "block")
+	if warming != "block" && warming != "partial" {
+		return fmt.Errorf("invalid --warming value: %s (expected block or partial)", warming)
+	}
+
+	startMeta(directory)
+	warm := newScanIndex(directory, options)
+	serveIndexes = []*scanIndex{warm}
+	warm.build()
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	var encoder responseEncoder = newJSONResponseEncoder(out)
+
+	// 请求在单独的 goroutine 中读取，命令执行期间也能收到 cancel。
+	// 提前返回时通知它退出，并关闭输入（如果可以关闭）让阻塞中的读取返回
+	lines := make(chan []byte)
+	stop := make(chan struct{})
+	defer func() {
+		close(stop)
+		if closer, ok := in.(io.Closer); ok {
+			closer.Close()
+		}
+	}()
+	go func() {
+		defer close(lines)
+		for scanner.Scan() {
+			if len(scanner.Bytes()) == 0 {
+				continue
+			}
+			select {
+			case lines <- append([]byte(nil), scanner.Bytes()...):
+			case <-stop:
+				return
+			}
+		}
+	}()
+	// 命令执行期间收到的其他请求，按顺序排队；queued 记录排队中各个 ID 的请求数。
+	// 只有排队中的请求可以被取消（记入 cancelled），轮到它时直接返回错误并删除记录；
+	// 针对已经完成或者还没有到达的请求的 cancel 被忽略，不会留下记录
+	var pending [][]byte
+	queued := make(map[string]int)
+	cancelled := make(map[string]bool)
+
+	// 写响应失败说明客户端已经关闭了管道，不再继续处理请求
+	for {
+		var line []byte
+		dequeued := len(pending) > 0
+		if dequeued {
+			line, pending = pending[0], pending[1:]
+		} else {
+			var ok bool
+			if lines == nil {
+				break
+			}
+			if line, ok = <-lines; !ok {
+				break
+			}
+		}
+
+		var request serveRequest
+		invalid := json.Unmarshal(line, &request)
+		if dequeued {
+			if id := string(request.ID); queued[id] > 0 {
+				if queued[id]--; queued[id] == 0 {
+					delete(queued, id)
+				}
+			}
+		}
+		if err := invalid; err != nil {
+			if err := encoder.Encode(serveResponse{Error: fmt.Sprintf("invalid request: %v", err)}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch request.Method {
+		case "cancel":
+			// 没有命令在执行，排队的请求也已处理完：要取消的请求已经完成或者还没有到达
+			continue
+		case "initialize":
+			// 协商结果本身总是用 JSON 返回，之后的响应才切换编码
+			encoding := request.Params.Encoding
+			if encoding != "gob" {
+				encoding = "json"
+			}
+			if err := encoder.Encode(serveResponse{ID: request.ID, Result: json.RawMessage(`{"encoding":"` + encoding + `"}`)}); err != nil {
+				return err
+			}
+			if encoding == "gob" {
+				encoder = newGobResponseEncoder(out)
+			}
+			continue
+		}
+
+		if len(request.ID) > 0 && cancelled[string(request.ID)] {
+			delete(cancelled, string(request.ID))
+			if err := encoder.Encode(serveResponse{ID: request.ID, Error: errRequestCancelled.Error()}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !warm.isReady() {
+			if warming == "partial" {
+				// warming 响应没有结果，编码时不读取全局状态
+				if err := encoder.Encode(serveResponse{ID: request.ID, Warming: true}); err != nil {
+					return err
+				}
+				continue
+			}
+			<-warm.ready
+		}
+
+		// 命令在单独的 goroutine 中执行；编码会读取 meta 和诊断，和命令一起在锁内完成
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() {
+			// 先恢复 requestCtx 并释放锁再通知，serveStream 返回后这个 goroutine 不再访问全局状态
+			var err error
+			defer func() { done <- err }()
+			analysisMu.Lock()
+			defer analysisMu.Unlock()
+			requestCtx = ctx
+			defer func() { requestCtx = context.Background() }()
+			err = encoder.Encode(handleServeRequest(request))
+		}()
+
+		var err error
+	wait:
+		for {
+			select {
+			case err = <-done:
+				break wait
+			case next, ok := <-lines:
+				if !ok {
+					lines = nil
+					continue
+				}
+				var message serveRequest
+				if json.Unmarshal(next, &message) == nil && message.Method == "cancel" {
+					if target := string(message.Params.ID); len(request.ID) > 0 && target == string(request.ID) {
+						cancel()
+					} else if queued[target] > 0 {
+						cancelled[target] = true
+					}
+					continue
+				}
+				pending = append(pending, next)
+				if len(message.ID) > 0 {
+					queued[string(message.ID)]++
+				}
+			}
+		}
+		cancel()
+		if err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// 以请求自己的参数和选项执行一条命令，调用方需要持有 analysisMu
+func handleServeRequest(request serveRequest) serveResponse {
+	response := serveResponse{ID: request.ID}
+
+	options = parseOptions(request.Args)
+	if len(options.Args) < 2 {
+		response.Error = "request needs a command and a target"
+		return response
+	}
+
+	if options.Has("stream") {
+		response.Error = "--stream is not supported in serve mode"
+		return response
+	}
+	prepareServeIndex(options.Args[1])
+
+	startMeta(options.Args[1])
+	result, err := safeRunCommand(options.Args[0], options.Args[1])
+	if requestCtx.Err() != nil {
+		response.Error = errRequestCancelled.Error()
+		return response
+	}
+	if err != nil {
+		_, response.Panic = err.(*panicError)
+		response.Error = err.Error()
+		return response
+	}
+	response.Result = result
+	return response
+}
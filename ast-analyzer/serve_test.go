@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 通过管道驱动 serve 的客户端
+type serveClient struct {
+	t    *testing.T
+	in   *io.PipeWriter
+	out  *bufio.Scanner
+	done chan error
+}
+
+func startServe(t *testing.T, directory string, args ...string) *serveClient {
+	t.Helper()
+	savedOptions, savedIndexes := options, serveIndexes
+	options = parseOptions(append([]string{"serve", directory}, args...))
+
+	inReader, inWriter := io.Pipe()
+	outReader, outWriter := io.Pipe()
+	client := &serveClient{t: t, in: inWriter, out: bufio.NewScanner(outReader), done: make(chan error, 1)}
+	client.out.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	go func() {
+		client.done <- serveStream(directory, inReader, outWriter)
+		outWriter.Close()
+	}()
+	t.Cleanup(func() {
+		inWriter.Close()
+		<-client.done
+		options, serveIndexes = savedOptions, savedIndexes
+	})
+	return client
+}
+
+type serveTestResponse struct {
+	Warming bool            `json:"warming"`
+	Result  json.RawMessage `json:"result"`
+	Error   string          `json:"error"`
+	Panic   bool            `json:"panic"`
+}
+
+func (c *serveClient) send(message map[string]interface{}) {
+	c.t.Helper()
+	line, _ := json.Marshal(message)
+	if _, err := c.in.Write(append(line, '\n')); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func (c *serveClient) receive() serveTestResponse {
+	c.t.Helper()
+	if !c.out.Scan() {
+		c.t.Fatalf("no response: %v", c.out.Err())
+	}
+	var response serveTestResponse
+	if err := json.Unmarshal(c.out.Bytes(), &response); err != nil {
+		c.t.Fatal(err)
+	}
+	return response
+}
+
+// 在限定时间内等待响应，超时时测试失败而不是一直阻塞
+func (c *serveClient) receiveWithin(timeout time.Duration) serveTestResponse {
+	c.t.Helper()
+	received := make(chan serveTestResponse, 1)
+	go func() {
+		if c.out.Scan() {
+			var response serveTestResponse
+			json.Unmarshal(c.out.Bytes(), &response)
+			received <- response
+		}
+	}()
+	select {
+	case response := <-received:
+		return response
+	case <-time.After(timeout):
+		c.t.Fatalf("no response within %v", timeout)
+		return serveTestResponse{}
+	}
+}
+
+func (c *serveClient) request(args ...string) serveTestResponse {
+	c.t.Helper()
+	c.send(map[string]interface{}{"args": args})
+	response := c.receive()
+	if response.Error != "" {
+		c.t.Fatalf("%v: %s", args, response.Error)
+	}
+	return response
+}
+
+// find-implementations 返回的接收者类型，排序后比较
+func (c *serveClient) implementors(args ...string) []string {
+	c.t.Helper()
+	var result AnalysisResult
+	if err := json.Unmarshal(c.request(args...).Result, &result); err != nil {
+		c.t.Fatal(err)
+	}
+	types := receiverTypes(result.Implementations)
+	sort.Strings(types)
+	return types
+}
+
+func TestServeRefreshesWhenFilesAreAddedAndRemoved(t *testing.T) {
+	dir := writeTree(t, map[string]string{"a.go": serveFixtureInterface})
+	client := startServe(t, dir)
+	query := []string{"find-implementations", dir, "Run"}
+
+	if got, want := client.implementors(query...), []string{"A"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("before adding: got %v, want %v", got, want)
+	}
+
+	added := filepath.Join(dir, "b.go")
+	writeFile(t, added, "package p\n\ntype B struct{}\n\nfunc (B) Run() {}\n")
+	if got, want := client.implementors(query...), []string{"A", "B"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("after adding b.go: got %v, want %v", got, want)
+	}
+
+	if err := os.Remove(added); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := client.implementors(query...), []string{"A"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("after removing b.go: got %v, want %v", got, want)
+	}
+}
+
+// 一个请求发现目录变化触发的重建只能使用该索引自己的选项，不能把请求的 --include-tests 带给其他请求
+// 每次重建索引都使用新的驻留表：类型名和方法名每轮都不同，驻留表的大小保持不变
+func TestServeRebuildsDoNotAccumulateInternedStrings(t *testing.T) {
+	dir := writeTree(t, map[string]string{"a.go": serveFixtureInterface})
+	client := startServe(t, dir)
+	query := []string{"find-implementations", dir, "Run"}
+
+	size := -1
+	for round := 0; round < 10; round++ {
+		if round > 0 {
+			if err := os.Remove(filepath.Join(dir, fmt.Sprintf("b%d.go", round-1))); err != nil {
+				t.Fatal(err)
+			}
+		}
+		writeFile(t, filepath.Join(dir, fmt.Sprintf("b%d.go", round)),
+			fmt.Sprintf("package p\n\ntype T%03d struct{}\n\nfunc (T%03d) Run() {}\n\nfunc (T%03d) Extra%03d() {}\n", round, round, round, round))
+		if got, want := client.implementors(query...), []string{"A", fmt.Sprintf("T%03d", round)}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("round %d: got %v, want %v", round, got, want)
+		}
+
+		analysisMu.Lock()
+		got := len(serveIndexes[0].scan.interner)
+		analysisMu.Unlock()
+		if size >= 0 && got != size {
+			t.Fatalf("round %d: interner holds %d strings, %d after the first rebuild", round, got, size)
+		}
+		size = got
+	}
+}
+
+func TestServeIndexesAreKeyedByScanOptions(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"a.go":      serveFixtureInterface,
+		"a_test.go": "package p\n\ntype Fake struct{}\n\nfunc (Fake) Run() {}\n",
+	})
+	client := startServe(t, dir)
+	query := []string{"find-implementations", dir, "Run"}
+	withTests := append(append([]string{}, query...), "--include-tests")
+
+	if got, want := client.implementors(query...), []string{"A"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("default index: got %v, want %v", got, want)
+	}
+	if got, want := client.implementors(withTests...), []string{"A", "Fake"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("--include-tests: got %v, want %v", got, want)
+	}
+	if got, want := client.implementors(query...), []string{"A"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("default index after --include-tests request: got %v, want %v", got, want)
+	}
+
+	// --include-tests 的请求先发现新文件并重建自己的索引
+	writeFile(t, filepath.Join(dir, "b.go"), "package p\n\ntype B struct{}\n\nfunc (B) Run() {}\n")
+	if got, want := client.implementors(withTests...), []string{"A", "B", "Fake"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("--include-tests after adding b.go: got %v, want %v", got, want)
+	}
+	if got, want := client.implementors(query...), []string{"A", "B"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("default index after adding b.go: got %v, want %v", got, want)
+	}
+}
+
+func TestServeStopsWhenClientGoesAway(t *testing.T) {
+	dir := writeTree(t, map[string]string{"a.go": serveFixtureInterface})
+	savedOptions, savedIndexes := options, serveIndexes
+	defer func() { options, serveIndexes = savedOptions, savedIndexes }()
+	options = parseOptions([]string{"serve", dir})
+
+	outReader, outWriter := io.Pipe()
+	outReader.Close()
+	in := strings.NewReader(`{"args":["find-implementations","` + dir + `","Run"]}` + "\n" + `{"args":["find-implementations","` + dir + `","Run"]}` + "\n")
+	if err := serveStream(dir, in, outWriter); err == nil {
+		t.Fatal("serveStream returned nil after the output pipe was closed")
+	}
+}
+
+// serveStream 返回后不再读取输入：客户端继续写入时得到错误，而不是一直阻塞
+func TestServeClosesInputWhenItStops(t *testing.T) {
+	dir := writeTree(t, map[string]string{"a.go": serveFixtureInterface})
+	savedOptions, savedIndexes := options, serveIndexes
+	defer func() { options, serveIndexes = savedOptions, savedIndexes }()
+	options = parseOptions([]string{"serve", dir})
+
+	outReader, outWriter := io.Pipe()
+	outReader.Close()
+	inReader, inWriter := io.Pipe()
+	request := `{"args":["find-implementations","` + dir + `","Run"]}` + "\n"
+	go inWriter.Write([]byte(request + request))
+	if err := serveStream(dir, inReader, outWriter); err == nil {
+		t.Fatal("serveStream returned nil after the output pipe was closed")
+	}
+
+	written := make(chan error, 1)
+	go func() {
+		_, err := inWriter.Write([]byte(request))
+		written <- err
+	}()
+	select {
+	case err := <-written:
+		if err != io.ErrClosedPipe {
+			t.Fatalf("write after serveStream returned: got %v, want %v", err, io.ErrClosedPipe)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("input is still being read after serveStream returned")
+	}
+}
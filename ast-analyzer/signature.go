@@ -0,0 +1,311 @@
+package main
+
+import (
+	"bytes"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 使用 go/printer 按源码原样渲染节点
+func renderNode(fset *token.FileSet, node ast.Node) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, node); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// 渲染方法签名，例如 AddToken(token string) error；
+// 参数中的匿名结构体或接口压成一行，例如 Configure(opts struct { Verbose bool; Level int }) error
+func renderMethodSignature(fset *token.FileSet, name string, funcType *ast.FuncType) string {
+	return name + strings.TrimPrefix(singleLine(renderNode(fset, funcType)), "func")
+}
+
+// 把 go/printer 输出的多行签名合并为一行：类型字面量的成员之间用 ; 分隔，
+// 换行书写的参数列表去掉换行和末尾的逗号
+func singleLine(source string) string {
+	if !strings.Contains(source, "\n") {
+		return source
+	}
+	out := ""
+	for i, line := range strings.Split(source, "\n") {
+		line = strings.ReplaceAll(strings.TrimLeft(line, "\t"), "\t", " ")
+		switch {
+		case i == 0:
+		case strings.HasPrefix(line, ")"):
+			out = strings.TrimSuffix(out, ",")
+		case strings.HasSuffix(out, "("):
+		case strings.HasSuffix(out, "{") || strings.HasSuffix(out, ",") || strings.HasPrefix(line, "}"):
+			out += " "
+		default:
+			out += "; "
+		}
+		out += line
+	}
+	return out
+}
+
+// 渲染类型参数列表，约束表达式（包括内联接口和联合类型）按源码原样输出
+func renderTypeParams(fset *token.FileSet, list *ast.FieldList) []TypeParam {
+	if list == nil {
+		return nil
+	}
+	var params []TypeParam
+	for _, field := range list.List {
+		constraint := renderNode(fset, field.Type)
+		for _, name := range field.Names {
+			params = append(params, TypeParam{Name: name.Name, Constraint: constraint})
+		}
+	}
+	return params
+}
+
+// 预声明类型不需要包名限定
+var predeclaredTypes = map[string]bool{
+	"bool": true, "byte": true, "complex64": true, "complex128": true,
+	"error": true, "float32": true, "float64": true, "int": true,
+	"int8": true, "int16": true, "int32": true, "int64": true,
+	"rune": true, "string": true, "uint": true, "uint8": true,
+	"uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+	"any": true, "comparable": true,
+}
+
+// 类型限定器：把类型表达式规范化为与导入别名无关的形式，
+// 同包类型 User 和其他包中的 model.User 会得到相同的结果
+type typeQualifier struct {
+	pkgName    string
+	imports    map[string]string // 导入别名 -> 包名
+	importPath map[string]string // 导入别名 -> 导入路径
+	typeParams map[string]bool
+}
+
+func newTypeQualifier(f *ast.File) *typeQualifier {
+	q := &typeQualifier{
+		pkgName:    f.Name.Name,
+		imports:    make(map[string]string),
+		importPath: make(map[string]string),
+	}
+
+	for _, spec := range f.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := importPackageName(path)
+		alias := name
+		if spec.Name != nil {
+			alias = spec.Name.Name
+		}
+		if alias == "_" || alias == "." {
+			continue
+		}
+		q.imports[alias] = name
+		q.importPath[alias] = path
+	}
+
+	return q
+}
+
+// 根据导入路径推断包名，忽略 /v2 这样的版本后缀
+func importPackageName(path string) string {
+	parts := strings.Split(path, "/")
+	name := parts[len(parts)-1]
+	if len(parts) > 1 && len(name) > 1 && name[0] == 'v' {
+		if _, err := strconv.Atoi(name[1:]); err == nil {
+			name = parts[len(parts)-2]
+		}
+	}
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// 签名中引用的其他包的类型所需的导入
+type SignatureImport struct {
+	// 声明接口的文件中使用的名称（导入别名或包名）
+	Alias string `json:"alias"`
+	Path  string `json:"path"`
+}
+
+// 收集签名中 pkg.Type 形式的类型引用对应的导入，按出现顺序去重
+func (q *typeQualifier) signatureImports(funcType *ast.FuncType) []SignatureImport {
+	var imports []SignatureImport
+	seen := make(map[string]bool)
+	ast.Inspect(funcType, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if x, ok := sel.X.(*ast.Ident); ok {
+			if path, ok := q.importPath[x.Name]; ok && !seen[x.Name] {
+				seen[x.Name] = true
+				imports = append(imports, SignatureImport{Alias: x.Name, Path: path})
+			}
+		}
+		return false
+	})
+	return imports
+}
+
+// 返回带有额外类型参数的限定器（没有新参数时返回自身）
+func (q *typeQualifier) withTypeParams(fields ...*ast.FieldList) *typeQualifier {
+	// 没有新的类型参数时直接复用，避免为每个方法复制一份表
+	empty := true
+	for _, list := range fields {
+		if list != nil && list.NumFields() > 0 {
+			empty = false
+		}
+	}
+	if empty {
+		return q
+	}
+
+	copied := *q
+	copied.typeParams = make(map[string]bool)
+	for name := range q.typeParams {
+		copied.typeParams[name] = true
+	}
+	for _, list := range fields {
+		if list == nil {
+			continue
+		}
+		for _, field := range list.List {
+			for _, name := range field.Names {
+				copied.typeParams[name.Name] = true
+			}
+		}
+	}
+	return &copied
+}
+
+// 规范化类型表达式
+func (q *typeQualifier) typeString(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if predeclaredTypes[t.Name] || q.typeParams[t.Name] || q.pkgName == "" {
+			return t.Name
+		}
+		return q.pkgName + "." + t.Name
+	case *ast.SelectorExpr:
+		if x, ok := t.X.(*ast.Ident); ok {
+			if name, ok := q.imports[x.Name]; ok {
+				return name + "." + t.Sel.Name
+			}
+			return x.Name + "." + t.Sel.Name
+		}
+	case *ast.StarExpr:
+		return "*" + q.typeString(t.X)
+	case *ast.ParenExpr:
+		return q.typeString(t.X)
+	case *ast.Ellipsis:
+		return "..." + q.typeString(t.Elt)
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return "[]" + q.typeString(t.Elt)
+		}
+		return "[" + types.ExprString(t.Len) + "]" + q.typeString(t.Elt)
+	case *ast.MapType:
+		return "map[" + q.typeString(t.Key) + "]" + q.typeString(t.Value)
+	case *ast.ChanType:
+		switch t.Dir {
+		case ast.SEND:
+			return "chan<- " + q.typeString(t.Value)
+		case ast.RECV:
+			return "<-chan " + q.typeString(t.Value)
+		}
+		return "chan " + q.typeString(t.Value)
+	case *ast.FuncType:
+		return "func" + q.signatureKey(t)
+	case *ast.IndexExpr:
+		return q.typeString(t.X) + "[" + q.typeString(t.Index) + "]"
+	case *ast.IndexListExpr:
+		args := make([]string, 0, len(t.Indices))
+		for _, index := range t.Indices {
+			args = append(args, q.typeString(index))
+		}
+		return q.typeString(t.X) + "[" + strings.Join(args, ",") + "]"
+	case *ast.StructType:
+		return q.structString(t)
+	case *ast.InterfaceType:
+		return q.interfaceString(t)
+	}
+	return types.ExprString(expr)
+}
+
+// 匿名结构体：字段名、类型和标签按顺序参与比较，嵌入字段只有类型
+func (q *typeQualifier) structString(t *ast.StructType) string {
+	var fields []string
+	for _, field := range t.Fields.List {
+		typeStr := q.typeString(field.Type)
+		tag := ""
+		if field.Tag != nil {
+			if value, err := strconv.Unquote(field.Tag.Value); err == nil {
+				tag = " " + strconv.Quote(value)
+			}
+		}
+		if len(field.Names) == 0 {
+			fields = append(fields, typeStr+tag)
+		}
+		for _, name := range field.Names {
+			fields = append(fields, name.Name+" "+typeStr+tag)
+		}
+	}
+	return "struct{" + strings.Join(fields, ";") + "}"
+}
+
+// 匿名接口：方法集与声明顺序无关，方法和嵌入项排序后比较
+func (q *typeQualifier) interfaceString(t *ast.InterfaceType) string {
+	var elems []string
+	for _, field := range t.Methods.List {
+		if funcType, ok := field.Type.(*ast.FuncType); ok {
+			for _, name := range field.Names {
+				elems = append(elems, name.Name+q.signatureKey(funcType))
+			}
+			continue
+		}
+		elems = append(elems, q.typeString(field.Type))
+	}
+	sort.Strings(elems)
+	return "interface{" + strings.Join(elems, ";") + "}"
+}
+
+// 规范化函数签名：只保留参数和返回值的类型，例如 (string)(error)
+func (q *typeQualifier) signatureKey(funcType *ast.FuncType) string {
+	return string(q.appendSignatureKey(nil, funcType))
+}
+
+// 把规范化签名追加到 buf 后返回，收集方法时复用同一块缓冲区
+func (q *typeQualifier) appendSignatureKey(buf []byte, funcType *ast.FuncType) []byte {
+	buf = append(buf, '(')
+	buf = q.appendFieldTypes(buf, funcType.Params)
+	buf = append(buf, ')')
+	if funcType.Results.NumFields() > 0 {
+		buf = append(buf, '(')
+		buf = q.appendFieldTypes(buf, funcType.Results)
+		buf = append(buf, ')')
+	}
+	return buf
+}
+
+// 按顺序追加每个参数的类型，以逗号分隔；一个字段声明多个名称时类型重复多次
+func (q *typeQualifier) appendFieldTypes(buf []byte, list *ast.FieldList) []byte {
+	if list == nil {
+		return buf
+	}
+	first := true
+	for _, field := range list.List {
+		typeStr := q.typeString(field.Type)
+		count := max(len(field.Names), 1)
+		for i := 0; i < count; i++ {
+			if !first {
+				buf = append(buf, ',')
+			}
+			first = false
+			buf = append(buf, typeStr...)
+		}
+	}
+	return buf
+}
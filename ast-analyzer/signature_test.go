@@ -0,0 +1,59 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// 接口和实现的参数都是匿名结构体和匿名接口：完全相同时匹配，字段不同则不匹配
+func TestAnonymousStructAndInterfaceParameters(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"a.go": `package p
+
+type Configurer interface {
+	Configure(opts struct{ Verbose bool; Level int "json:\"level\"" }, v interface{ Name() string; ID() int }) error
+}
+
+// 匿名接口中方法的顺序不影响类型是否相同
+type Same struct{}
+
+func (Same) Configure(opts struct {
+	Verbose bool
+	Level   int "json:\"level\""
+}, v interface {
+	ID() int
+	Name() string
+}) error {
+	return nil
+}
+
+type OtherField struct{}
+
+func (OtherField) Configure(opts struct{ Quiet bool; Level int "json:\"level\"" }, v interface{ Name() string; ID() int }) error {
+	return nil
+}
+
+type OtherTag struct{}
+
+func (OtherTag) Configure(opts struct{ Verbose bool; Level int }, v interface{ Name() string; ID() int }) error {
+	return nil
+}
+`,
+	})
+
+	result := runArgs(t, "find-implementations", dir, "Configure").(AnalysisResult)
+	got := receiverTypes(result.Implementations)
+	sort.Strings(got)
+	if want := []string{"Same"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got implementations %v, want %v", got, want)
+	}
+	if got, want := result.Implementations[0].Signature, `Configure(opts struct { Verbose bool; Level int "json:\"level\"" }, v interface { ID() int; Name() string }) error`; got != want {
+		t.Errorf("implementation signature = %s, want %s", got, want)
+	}
+
+	described := runArgs(t, "describe-interface", dir, "Configurer").(DescribeResult)
+	if got, want := described.Interfaces[0].Methods[0].Signature, `Configure(opts struct { Verbose bool; Level int "json:\"level\"" }, v interface { Name() string; ID() int }) error`; got != want {
+		t.Errorf("interface signature = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// 默认的单文件大小上限（字节），超过的文件（通常是生成代码）不参与目录扫描
+const defaultMaxFileSize = 10 << 20
+
+// 超过该大小的文件在单独的 goroutine 中解析，受 --parse-timeout 限制
+const largeFileSize = 1 << 20
+
+// 归还到缓冲池的缓冲区容量上限，避免个别大文件的缓冲区长期占用内存
+const maxPooledBufferSize = 1 << 20
+
+// 扫描访问源文件使用的文件系统：目录遍历和文件读取都经过这里，测试中可以替换为记录读取的实现
+type sourceFileSystem interface {
+	// 与 filepath.Walk 相同，回调收到的 FileInfo 用于在读取之前过滤文件
+	Walk(root string, fn filepath.WalkFunc) error
+	Open(path string) (fs.File, error)
+}
+
+type osFileSystem struct{}
+
+func (osFileSystem) Walk(root string, fn filepath.WalkFunc) error {
+	return filepath.Walk(root, fn)
+}
+
+func (osFileSystem) Open(path string) (fs.File, error) {
+	return os.Open(path)
+}
+
+var sourceFS sourceFileSystem = osFileSystem{}
+
+var sourceBuffers = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// 文件是否超过 --max-file-size（0 表示不限制）
+func fileTooLarge(size int64) bool {
+	limit := int64(options.Int("max-file-size", defaultMaxFileSize))
+	return limit > 0 && size > limit
+}
+
+// UTF-8 BOM，Windows 上编辑的文件常以它开头
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// 文件不是合法的 UTF-8（通常是 GBK 等其他编码），位置为第一个非法字节（从 1 开始）
+type encodingError struct {
+	path   string
+	line   int
+	column int
+}
+
+func (e *encodingError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: file is not valid UTF-8 (Go source must be UTF-8)", e.path, e.line, e.column)
+}
+
+// 查找第一个非法的 UTF-8 字节，全部合法时返回 nil
+func checkUTF8(path string, src []byte) error {
+	if utf8.Valid(src) {
+		return nil
+	}
+	line, column := 1, 1
+	for len(src) > 0 {
+		r, size := utf8.DecodeRune(src)
+		if r == utf8.RuneError && size == 1 {
+			break
+		}
+		if r == '\n' {
+			line, column = line+1, 1
+		} else {
+			column += size
+		}
+		src = src[size:]
+	}
+	return &encodingError{path: path, line: line, column: column}
+}
+
+// 大文件的解析超过 --parse-timeout 或者请求被取消，结果被放弃
+type abandonedError struct {
+	path    string
+	timeout time.Duration
+	// serve 模式下请求被取消
+	cancelled bool
+}
+
+func (e *abandonedError) Error() string {
+	if e.cancelled {
+		return fmt.Sprintf("%s: parse abandoned because the request was cancelled", e.path)
+	}
+	return fmt.Sprintf("%s: parse abandoned after %v (--parse-timeout)", e.path, e.timeout)
+}
+
+// 请求已被取消，剩下的文件不再读取
+var errRequestCancelled = errors.New("request cancelled")
+
+// 编码错误记为 encodingError 诊断，放弃的解析记为 abandoned 诊断，其他解析错误照旧忽略
+func reportParseError(err error) {
+	var encErr *encodingError
+	if errors.As(err, &encErr) {
+		reportDiagnostic(Diagnostic{
+			Kind:     "encodingError",
+			Message:  encErr.Error(),
+			Location: Location{File: encErr.path, Line: encErr.line - 1, Column: encErr.column - 1},
+		})
+	}
+	var ignored *buildIgnoredError
+	if errors.As(err, &ignored) {
+		fmt.Fprintf(os.Stderr, "跳过 //go:build ignore 文件: %s\n", ignored.path)
+	}
+	var abandoned *abandonedError
+	if errors.As(err, &abandoned) {
+		reportDiagnostic(Diagnostic{
+			Kind:     "abandoned",
+			Message:  abandoned.Error(),
+			Location: Location{File: abandoned.path},
+		})
+	}
+}
+
+// 解析源码，测试中可以替换
+var parseSource = parser.ParseFile
+
+// 解析大文件：parser.ParseFile 无法中途取消。serve 模式下请求被取消时、或者超过 --parse-timeout（毫秒）时不再等待，
+// 返回 *abandonedError，后台的解析照常完成后丢弃。源码需要拷贝，调用方的缓冲区会被复用
+func parseLargeFile(fset *token.FileSet, path string, src []byte) (*ast.File, error) {
+	timeout := time.Duration(options.Int("parse-timeout", 0)) * time.Millisecond
+	cancelled := requestCtx.Done()
+	if len(src) <= largeFileSize || (timeout <= 0 && cancelled == nil) {
+		return parseSource(fset, path, src, parser.ParseComments)
+	}
+
+	type parsed struct {
+		file *ast.File
+		err  error
+	}
+	done := make(chan parsed, 1)
+	src = append([]byte(nil), src...)
+	parse := parseSource
+	go func() {
+		f, err := parse(fset, path, src, parser.ParseComments)
+		done <- parsed{file: f, err: err}
+	}()
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case result := <-done:
+		return result.file, result.err
+	case <-expired:
+		return nil, &abandonedError{path: path, timeout: timeout}
+	case <-cancelled:
+		return nil, &abandonedError{path: path, cancelled: true}
+	}
+}
+
+// 读取并解析 Go 文件。源码读入复用的缓冲区，AST 中的字符串都是拷贝，
+// 解析完成后缓冲区即可归还，不会在长时间遍历中保留源码。
+// 开头的 BOM 在解析前去掉，使第一行的列号与编辑器一致；不是 UTF-8 的文件返回 *encodingError
+func parseGoFile(fset *token.FileSet, path string) (*ast.File, error) {
+	if requestCtx.Err() != nil {
+		return nil, errRequestCancelled
+	}
+	file, err := sourceFS.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	buf := sourceBuffers.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer func() {
+		if buf.Cap() <= maxPooledBufferSize {
+			sourceBuffers.Put(buf)
+		}
+	}()
+
+	if info, err := file.Stat(); err == nil {
+		buf.Grow(int(info.Size()) + bytes.MinRead)
+	}
+	if _, err := buf.ReadFrom(file); err != nil {
+		return nil, err
+	}
+
+	src := bytes.TrimPrefix(buf.Bytes(), utf8BOM)
+	if err := checkUTF8(path, src); err != nil {
+		return nil, err
+	}
+	f, err := parseLargeFile(fset, path, src)
+	if err == nil && !options.Has("include-ignored") && buildIgnored(f) {
+		return nil, &buildIgnoredError{path: path}
+	}
+	return f, err
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// 超过 largeFileSize 的生成文件
+func oversizedSource() string {
+	var src strings.Builder
+	src.WriteString("package p\n\nvar table = []int{\n")
+	for i := 0; src.Len() <= largeFileSize; i++ {
+		src.WriteString("\t1234567890,\n")
+	}
+	src.WriteString("}\n")
+	return src.String()
+}
+
+// 让 huge.go 的解析一直阻塞到 release（最迟在测试结束时），模拟解析极慢的文件
+func blockHugeParses(t *testing.T) (enable, release func()) {
+	saved := parseSource
+	released := make(chan struct{})
+	var once sync.Once
+	release = func() { once.Do(func() { close(released) }) }
+	var blocking atomic.Bool
+	parseSource = func(fset *token.FileSet, path string, src interface{}, mode parser.Mode) (*ast.File, error) {
+		if blocking.Load() && filepath.Base(path) == "huge.go" {
+			<-released
+		}
+		return saved(fset, path, src, mode)
+	}
+	t.Cleanup(func() {
+		release()
+		parseSource = saved
+	})
+	return func() { blocking.Store(true) }, release
+}
+
+func TestParseTimeoutAbandonsLargeFiles(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"a.go":    "package p\n\ntype Runner interface {\n\tRun()\n}\n\ntype A struct{}\n\nfunc (A) Run() {}\n",
+		"huge.go": oversizedSource(),
+	})
+	enable, release := blockHugeParses(t)
+	enable()
+
+	result := runArgs(t, "find-implementations", dir, "Run", "--parse-timeout", "50").(AnalysisResult)
+	if got := receiverTypes(result.Implementations); len(got) != 1 || got[0] != "A" {
+		t.Fatalf("got implementations %v, want [A]", got)
+	}
+	abandoned := diagnosticsOfKind("abandoned")
+	if len(abandoned) != 1 || abandoned[0].Location.File != filepath.Join(dir, "huge.go") {
+		t.Fatalf("got abandoned diagnostics %v, want one for huge.go", abandoned)
+	}
+
+	// 解析在期限内完成时不报告
+	release()
+	runArgs(t, "find-implementations", dir, "Run", "--parse-timeout", "60000")
+	if abandoned := diagnosticsOfKind("abandoned"); len(abandoned) != 0 {
+		t.Fatalf("got abandoned diagnostics %v after the parse finished in time", abandoned)
+	}
+}
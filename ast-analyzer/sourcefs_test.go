@@ -0,0 +1,73 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// 报告为指定大小的文件信息，磁盘上的文件本身很小
+type sizedFileInfo struct {
+	os.FileInfo
+	size int64
+}
+
+func (i sizedFileInfo) Size() int64 { return i.size }
+
+// 记录每个文件被打开的次数，并可以把文件的大小报告为任意值
+type recordingFS struct {
+	osFileSystem
+	sizes  map[string]int64
+	mu     sync.Mutex
+	opened map[string]int
+}
+
+func useRecordingFS(t *testing.T, sizes map[string]int64) *recordingFS {
+	t.Helper()
+	recorder := &recordingFS{sizes: sizes, opened: make(map[string]int)}
+	saved := sourceFS
+	sourceFS = recorder
+	t.Cleanup(func() { sourceFS = saved })
+	return recorder
+}
+
+func (r *recordingFS) Walk(root string, fn filepath.WalkFunc) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if size, ok := r.sizes[filepath.Base(path)]; ok && info != nil {
+			info = sizedFileInfo{FileInfo: info, size: size}
+		}
+		return fn(path, info, err)
+	})
+}
+
+func (r *recordingFS) Open(path string) (fs.File, error) {
+	r.mu.Lock()
+	r.opened[filepath.Base(path)]++
+	r.mu.Unlock()
+	return os.Open(path)
+}
+
+func TestOversizedFileIsSkippedWithoutBeingRead(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"a.go": serveFixtureInterface,
+		// 被读取的话 Generated 会出现在结果中
+		"generated.go": "package p\n\ntype Generated struct{}\n\nfunc (Generated) Run() {}\n",
+	})
+	recorder := useRecordingFS(t, map[string]int64{"generated.go": 200 << 20})
+
+	for _, jobs := range []string{"1", "4"} {
+		result := runArgs(t, "find-implementations", dir, "Run", "--jobs", jobs).(AnalysisResult)
+		if got, want := receiverTypes(result.Implementations), []string{"A"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("--jobs %s: got %v, want %v", jobs, got, want)
+		}
+	}
+	if recorder.opened["generated.go"] > 0 {
+		t.Fatal("generated.go was opened although it exceeds --max-file-size")
+	}
+	if recorder.opened["a.go"] == 0 {
+		t.Fatal("a.go was not read through sourceFS")
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// 标准库接口索引中的一个方法：签名用于展示，Key 用于匹配
+type stdlibMethod struct {
+	Name      string `json:"name"`
+	Signature string `json:"signature"`
+	Key       string `json:"key"`
+}
+
+// 标准库接口索引：导入路径 -> 接口名 -> 展开后的方法
+type stdlibIndex map[string]map[string][]stdlibMethod
+
+var loadedStdlibIndex stdlibIndex
+
+// 磁盘缓存文件：按 GOROOT 路径和 Go 版本区分，升级 Go 后自动重建
+func stdlibIndexFile(goroot string) string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	version := "unknown"
+	if data, err := os.ReadFile(filepath.Join(goroot, "VERSION")); err == nil {
+		version = strings.TrimSpace(strings.SplitN(string(data), "\n", 2)[0])
+	}
+	h := fnv.New32a()
+	h.Write([]byte(goroot))
+	return filepath.Join(cacheDir, "ast-analyzer", fmt.Sprintf("stdlib-%s-%08x.json", version, h.Sum32()))
+}
+
+// 加载标准库接口索引：内存缓存、磁盘缓存，最后才扫描 GOROOT/src。
+// GOROOT 由 --goroot=<dir>、$GOROOT 或 go env GOROOT 决定
+func loadStdlibIndex() stdlibIndex {
+	if loadedStdlibIndex != nil {
+		return loadedStdlibIndex
+	}
+
+	goroot := gorootDir()
+	if goroot == "" {
+		fmt.Fprintf(os.Stderr, "找不到 GOROOT，无法加载标准库接口\n")
+		loadedStdlibIndex = stdlibIndex{}
+		return loadedStdlibIndex
+	}
+
+	cacheFile := stdlibIndexFile(goroot)
+	if data, err := os.ReadFile(cacheFile); cacheFile != "" && err == nil {
+		var index stdlibIndex
+		if json.Unmarshal(data, &index) == nil {
+			loadedStdlibIndex = index
+			return index
+		}
+	}
+
+	started := time.Now()
+	index := scanStdlibInterfaces(goroot)
+	fmt.Fprintf(os.Stderr, "扫描标准库接口: %d 个包, 耗时 %v\n", len(index), time.Since(started))
+
+	if cacheFile != "" {
+		if data, err := json.Marshal(index); err == nil {
+			if os.MkdirAll(filepath.Dir(cacheFile), 0o755) == nil {
+				os.WriteFile(cacheFile, data, 0o644)
+			}
+		}
+	}
+
+	loadedStdlibIndex = index
+	return index
+}
+
+// 扫描 GOROOT/src 中所有可导入的包（跳过 cmd、internal、vendor、testdata），
+// 收集导出的非泛型接口并展开嵌入。Go 1.27.1 上完整扫描约 0.7-0.85 秒、分配约 195MB
+// （49 个包含导出接口，缓存 38KB），结果写入磁盘缓存，之后读取缓存约 0.45 毫秒；
+// 数据见 BenchmarkStdlibIndex
+func scanStdlibInterfaces(goroot string) stdlibIndex {
+	src := filepath.Join(goroot, "src")
+	resolver := newInterfaceResolver()
+	var all []*InterfaceInfo
+
+	filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return nil
+		}
+		name := info.Name()
+		if path != src && (name == "cmd" || name == "internal" || name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+			return filepath.SkipDir
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil || rel == "." {
+			return nil
+		}
+
+		importPath := filepath.ToSlash(rel)
+		interfaces := parseStdlibPackage(importPath)
+		resolver.stdlibPackages[importPath] = path
+		for i := range interfaces {
+			if len(interfaces[i].TypeParams) > 0 {
+				continue
+			}
+			resolver.add(&interfaces[i])
+			all = append(all, &interfaces[i])
+		}
+		return nil
+	})
+
+	index := make(stdlibIndex)
+	for _, iface := range all {
+		resolver.flatten(iface)
+		if len(iface.Specs) == 0 || iface.Sealed() {
+			continue
+		}
+		importPath, _ := filepath.Rel(src, iface.Package)
+		importPath = filepath.ToSlash(importPath)
+		if index[importPath] == nil {
+			index[importPath] = make(map[string][]stdlibMethod)
+		}
+		methods := make([]stdlibMethod, 0, len(iface.Specs))
+		for _, spec := range iface.Specs {
+			methods = append(methods, stdlibMethod{Name: spec.Name, Signature: spec.Signature, Key: spec.Key})
+		}
+		index[importPath][iface.Name] = methods
+	}
+	return index
+}
+
+// 索引中的接口转换为可匹配的接口信息
+func (index stdlibIndex) interfaces() []InterfaceInfo {
+	var interfaces []InterfaceInfo
+	for importPath, byName := range index {
+		for name, methods := range byName {
+			iface := InterfaceInfo{Name: name, Package: importPath, PackageName: importPackageName(importPath)}
+			for _, method := range methods {
+				iface.addMethod(MethodSpec{Name: method.Name, Signature: method.Signature, Key: method.Key, DeclaredIn: name})
+			}
+			interfaces = append(interfaces, iface)
+		}
+	}
+	return interfaces
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+//go:generate go run ./tools/genstdlib -o stdlib_interfaces.json
+
+// 内置的标准库接口注册表（导入路径 -> 接口名 -> 方法签名，已展开嵌入接口），
+// 由 tools/genstdlib 从 GOROOT 源码生成并嵌入二进制；
+// 在无法读取 GOROOT 源码时作为回退，--stdlib-embeddings 模式下直接使用
+//
+//go:embed stdlib_interfaces.json
+var stdlibInterfacesJSON []byte
+
+var (
+	builtinInterfaces     map[string]map[string][]string
+	builtinInterfacesOnce sync.Once
+)
+
+func stdlibRegistry() map[string]map[string][]string {
+	builtinInterfacesOnce.Do(func() {
+		if err := json.Unmarshal(stdlibInterfacesJSON, &builtinInterfaces); err != nil {
+			fmt.Fprintf(os.Stderr, "解析内置标准库接口失败: %v\n", err)
+		}
+	})
+	return builtinInterfaces
+}
+
+// 预声明的 error 接口
+var errorInterface = newRegistryInterface("", "error", []string{"Error() string"})
+
+// 判断导入路径是否属于标准库（第一段不含域名）
+func isStdlibPath(importPath string) bool {
+	first := strings.SplitN(importPath, "/", 2)[0]
+	return !strings.Contains(first, ".")
+}
+
+// 根据注册表中的签名构造接口信息
+func newRegistryInterface(importPath, name string, signatures []string) *InterfaceInfo {
+	iface := &InterfaceInfo{Name: name, Package: importPath}
+	if importPath != "" {
+		iface.PackageName = importPackageName(importPath)
+	}
+	q := &typeQualifier{pkgName: iface.PackageName}
+
+	for _, signature := range signatures {
+		paren := strings.Index(signature, "(")
+		if paren <= 0 {
+			continue
+		}
+		expr, err := parser.ParseExpr("func" + signature[paren:])
+		if err != nil {
+			continue
+		}
+		funcType, ok := expr.(*ast.FuncType)
+		if !ok {
+			continue
+		}
+		iface.addMethod(MethodSpec{
+			Name:       signature[:paren],
+			Signature:  signature,
+			Key:        q.signatureKey(funcType),
+			Func:       funcType,
+			DeclaredIn: name,
+		})
+	}
+	return iface
+}
+
+// 从内置注册表中查找标准库接口
+func registryInterface(importPath, name string) *InterfaceInfo {
+	signatures, ok := stdlibRegistry()[importPath][name]
+	if !ok {
+		return nil
+	}
+	return newRegistryInterface(importPath, name, signatures)
+}
+
+var cachedGoroot *string
+
+// 解析 GOROOT：--goroot=<dir> 优先，其次是环境变量和 go env
+func gorootDir() string {
+	if cachedGoroot != nil {
+		return *cachedGoroot
+	}
+
+	dir := options.String("goroot", "")
+	if dir == "" {
+		dir = goEnv("GOROOT")
+	}
+	if dir == "" {
+		dir, _ = runGoCommand("env", "GOROOT")
+	}
+	if dir != "" {
+		if info, err := os.Stat(filepath.Join(dir, "src")); err != nil || !info.IsDir() {
+			dir = ""
+		}
+	}
+
+	cachedGoroot = &dir
+	return dir
+}
+
+// 解析 GOROOT 中某个标准库包的接口定义
+func parseStdlibPackage(importPath string) []InterfaceInfo {
+	var interfaces []InterfaceInfo
+	goroot := gorootDir()
+	if goroot == "" {
+		return interfaces
+	}
+
+	dir := filepath.Join(goroot, "src", filepath.FromSlash(importPath))
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return interfaces
+	}
+
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		// 标准库接口一定是导出的，只需要保留这些
+		for _, iface := range extractInterfaceInfos(f, fset, file, nil) {
+			if ast.IsExported(iface.Name) {
+				interfaces = append(interfaces, iface)
+			}
+		}
+	}
+	return interfaces
+}
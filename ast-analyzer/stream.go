@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+)
+
+// --stream 时逐行输出的实现记录（NDJSON）
+type StreamRecord struct {
+	Type           string         `json:"type"` // implementation
+	Implementation Implementation `json:"implementation"`
+}
+
+// --stream 的最后一条记录：输出数量和截断信息
+type StreamTrailer struct {
+	Type string `json:"type"` // trailer
+	// 已输出的实现数量
+	Count int `json:"count"`
+	// 确认满足接口的实现总数（--package-prefix 过滤后、--limit 截断前）
+	Total     int  `json:"total"`
+	Truncated bool `json:"truncated"`
+	// 目录下没有结果、改为从模块根目录查找时为 "module"
+	Scope string `json:"scope,omitempty"`
+	// --explain 时列出只因参数指针/值不一致而没有匹配的类型
+	NearMisses []NearMiss `json:"nearMisses,omitempty"`
+}
+
+// 确认一个实现后立即写出，供选择列表逐步填充
+type implementationStream struct {
+	out     io.Writer
+	matches func(file string) bool
+	limit   int
+	trailer StreamTrailer
+}
+
+// findImplementationsIn 每确认一个实现就调用一次；未开启 --stream 时为 nil
+var onImplementation func(Implementation)
+
+func newImplementationStream(prefixBase string) *implementationStream {
+	return &implementationStream{
+		out:     os.Stdout,
+		matches: packagePrefixMatcher(prefixBase),
+		limit:   options.Int("limit", 0),
+		trailer: StreamTrailer{Type: "trailer"},
+	}
+}
+
+func (s *implementationStream) emit(impl Implementation) {
+	if s.matches != nil && !s.matches(impl.Location.File) {
+		return
+	}
+	s.trailer.Total++
+	if s.limit > 0 && s.trailer.Count >= s.limit {
+		s.trailer.Truncated = true
+		return
+	}
+	s.trailer.Count++
+
+	line, _ := json.Marshal(StreamRecord{Type: "implementation", Implementation: impl})
+	if options.Has("lsp") {
+		line = toLSPLocations(line)
+	}
+	s.out.Write(append(line, '\n'))
+}
+
+// 流式执行 find-implementations：实现逐条写到标准输出，返回的结尾记录由 printResult 输出。
+// 流式输出按发现顺序，不做 --origin 排序；--limit 只截断已输出的数量。
+// 与非流式一样，目录下没有结果时从模块根目录重试一次
+func streamImplementations(directory, methodName string) StreamTrailer {
+	trailer := streamImplementationsIn(directory, directory, methodName)
+	if trailer.Total > 0 {
+		return trailer
+	}
+	if root := fallbackScope(directory); root != "" {
+		trailer = streamImplementationsIn(root, directory, methodName)
+		trailer.Scope = "module"
+	}
+	return trailer
+}
+
+func streamImplementationsIn(searchDir, prefixBase, methodName string) StreamTrailer {
+	stream := newImplementationStream(prefixBase)
+	onImplementation = stream.emit
+	defer func() { onImplementation = nil }()
+
+	findImplementations(searchDir, methodName)
+	if options.Has("explain") {
+		stream.trailer.NearMisses = findNearMisses(searchDir, methodName)
+	}
+	return stream.trailer
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// 直接返回结构体字面量的接口实现方法，调用方与具体类型耦合
+type StructLiteralReturn struct {
+	ReceiverType string `json:"receiverType"`
+	MethodName   string `json:"methodName"`
+	// 返回的类型，&Foo{...} 记为 *Foo
+	ReturnedType string   `json:"returnedType"`
+	Location     Location `json:"location"`
+}
+
+type StructLiteralReturnResult struct {
+	Returns []StructLiteralReturn `json:"returns"`
+}
+
+// 返回值是否为结构体字面量 Foo{...} 或 &Foo{...}，返回其类型；切片和 map 字面量不算
+func structLiteralType(expr ast.Expr) (string, bool) {
+	pointer := ""
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		pointer, expr = "*", unary.X
+	}
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok || lit.Type == nil {
+		return "", false
+	}
+	switch lit.Type.(type) {
+	case *ast.ArrayType, *ast.MapType:
+		return "", false
+	}
+	return pointer + types.ExprString(lit.Type), true
+}
+
+// 查找 return 语句中直接返回结构体字面量的接口实现方法，每个返回值输出一条；函数字面量中的 return 不算
+func findStructLiteralReturns(directory string) []StructLiteralReturn {
+	results := []StructLiteralReturn{}
+
+	for _, method := range uniqueImplementedMethods(matchAllImplementations(directory)) {
+		if method.fset == nil || method.FuncDecl.Body == nil {
+			continue
+		}
+		ast.Inspect(method.FuncDecl.Body, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.ReturnStmt:
+				for _, result := range node.Results {
+					returnedType, ok := structLiteralType(result)
+					if !ok {
+						continue
+					}
+					pos := method.fset.Position(result.Pos())
+					results = append(results, StructLiteralReturn{
+						ReceiverType: method.ReceiverType,
+						MethodName:   method.Name,
+						ReturnedType: returnedType,
+						Location:     Location{File: pos.Filename, Line: pos.Line - 1, Column: pos.Column - 1},
+					})
+				}
+			}
+			return true
+		})
+	}
+
+	return results
+}
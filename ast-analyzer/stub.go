@@ -0,0 +1,346 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// 对目标文件的文本修改，位置从 0 开始；location 与 endLocation 相同时为插入
+type TextEdit struct {
+	Location    Location `json:"location"`
+	EndLocation Location `json:"endLocation"`
+	NewText     string   `json:"newText"`
+}
+
+// 桩代码引用的包在目标文件中的导入
+type StubImport struct {
+	Path string `json:"path"`
+	// 目标文件中使用的名称
+	Name string `json:"name"`
+	// 目标文件已经导入了该包，直接复用
+	Existing bool `json:"existing"`
+}
+
+type StubResult struct {
+	Interface string `json:"interface"`
+	TypeName  string `json:"typeName"`
+	File      string `json:"file"`
+	// 全部桩方法的源码
+	Code    string       `json:"code"`
+	Imports []StubImport `json:"imports"`
+	// 新增导入和追加桩方法的编辑，位置都相对于原文件（与 LSP 的 TextEdit 相同），全部应用后即可编译
+	Edits []TextEdit `json:"edits"`
+}
+
+// 生成桩方法时目标文件的导入状态
+type stubImports struct {
+	// 目标文件所在的包目录和导入路径，引用这个包的类型不需要限定
+	destDir  string
+	destPath string
+	// 目标文件已有的导入：导入路径 -> 名称（点导入为空字符串）
+	existing map[string]string
+	// 目标文件中已经占用的名称：导入名和包级声明
+	taken map[string]bool
+	// 本次选定的导入：导入路径 -> 名称
+	chosen map[string]string
+	list   []StubImport
+}
+
+func newStubImports(destFile string, f *ast.File) *stubImports {
+	s := &stubImports{
+		existing: make(map[string]string),
+		taken:    make(map[string]bool),
+		chosen:   make(map[string]string),
+		list:     []StubImport{},
+	}
+	s.destDir, _ = filepath.Abs(filepath.Dir(destFile))
+	s.destPath = stubImportPath(s.destDir)
+
+	for _, spec := range f.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := importPackageName(path)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		switch name {
+		case "_":
+			continue
+		case ".":
+			s.existing[path] = ""
+			continue
+		}
+		s.existing[path] = name
+		s.taken[name] = true
+	}
+	// 包级声明与导入名冲突也是编译错误，包括同一包的其他文件中的声明
+	for name := range packageLevelNames(destFile, f.Name.Name) {
+		s.taken[name] = true
+	}
+	return s
+}
+
+// 包目录对应的导入路径；GOROOT/src 的模块路径是 std，标准库包去掉这个前缀
+func stubImportPath(dir string) string {
+	return strings.TrimPrefix(packageImportPath(dir), "std/")
+}
+
+// 与目标文件同一个包的所有文件中的包级声明名称
+func packageLevelNames(destFile, pkgName string) map[string]bool {
+	names := make(map[string]bool)
+	paths, _ := filepath.Glob(filepath.Join(filepath.Dir(destFile), "*.go"))
+	fset := token.NewFileSet()
+	for _, path := range paths {
+		f, err := parseGoFile(fset, path)
+		if err != nil || f.Name.Name != pkgName {
+			continue
+		}
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if d.Recv == nil {
+					names[d.Name.Name] = true
+				}
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					switch s := spec.(type) {
+					case *ast.TypeSpec:
+						names[s.Name.Name] = true
+					case *ast.ValueSpec:
+						for _, name := range s.Names {
+							names[name.Name] = true
+						}
+					}
+				}
+			}
+		}
+	}
+	return names
+}
+
+// 目标文件中引用该包使用的名称：同包为空，已导入时复用，否则选择一个不冲突的新名称
+func (s *stubImports) nameFor(path, preferred string) string {
+	if path == s.destPath {
+		return ""
+	}
+	if name, ok := s.existing[path]; ok {
+		if _, listed := s.chosen[path]; !listed {
+			s.chosen[path] = name
+			s.list = append(s.list, StubImport{Path: path, Name: name, Existing: true})
+		}
+		return name
+	}
+	if name, ok := s.chosen[path]; ok {
+		return name
+	}
+
+	base := preferred
+	if !token.IsIdentifier(base) || base == "_" {
+		base = importPackageName(path)
+	}
+	name := base
+	for i := 2; s.taken[name]; i++ {
+		name = base + strconv.Itoa(i)
+	}
+	s.taken[name] = true
+	s.chosen[path] = name
+	s.list = append(s.list, StubImport{Path: path, Name: name})
+	return name
+}
+
+// 渲染桩方法的签名：其他包的类型换成目标文件中的导入名，接口所在包的类型加上包名限定
+func (s *stubImports) renderSignature(spec MethodSpec) (string, error) {
+	// 从渲染好的签名重新解析出一份独立的语法树，修改它不会影响扫描结果
+	expr, err := parser.ParseExpr("func" + strings.TrimPrefix(spec.Signature, spec.Name))
+	if err != nil {
+		return "", fmt.Errorf("cannot parse signature of %s: %v", spec.Name, err)
+	}
+	funcType := expr.(*ast.FuncType)
+
+	aliases := make(map[string]string)
+	for _, imp := range spec.Imports {
+		aliases[imp.Alias] = imp.Path
+	}
+	homeDir, _ := filepath.Abs(spec.pkgDir)
+
+	const unqualified = "\x00"
+	var visitErr error
+	var visit func(n ast.Node) bool
+	visit = func(n ast.Node) bool {
+		if visitErr != nil {
+			return false
+		}
+		switch t := n.(type) {
+		case *ast.Field:
+			// 参数名和匿名结构体的字段名不是类型，只处理类型部分
+			ast.Inspect(t.Type, visit)
+			return false
+		case *ast.SelectorExpr:
+			x, ok := t.X.(*ast.Ident)
+			if !ok {
+				return false
+			}
+			path, ok := aliases[x.Name]
+			if !ok {
+				visitErr = fmt.Errorf("%s: cannot resolve the import path of %s.%s", spec.Name, x.Name, t.Sel.Name)
+				return false
+			}
+			if name := s.nameFor(path, x.Name); name != "" {
+				x.Name = name
+			} else {
+				x.Name = unqualified
+			}
+			return false
+		case *ast.Ident:
+			if predeclaredTypes[t.Name] || spec.pkgDir == "" || homeDir == s.destDir {
+				return true
+			}
+			if !t.IsExported() {
+				visitErr = fmt.Errorf("%s: references unexported type %s of package %s", spec.Name, t.Name, spec.pkgName)
+				return false
+			}
+			path := stubImportPath(homeDir)
+			if path == "" {
+				visitErr = fmt.Errorf("%s: package %s is not in a module and cannot be imported", spec.Name, spec.pkgDir)
+				return false
+			}
+			if name := s.nameFor(path, spec.pkgName); name != "" {
+				t.Name = name + "." + t.Name
+			}
+		}
+		return true
+	}
+	ast.Inspect(funcType, visit)
+	if visitErr != nil {
+		return "", visitErr
+	}
+
+	rendered := strings.TrimPrefix(singleLine(renderNode(token.NewFileSet(), funcType)), "func")
+	return strings.ReplaceAll(rendered, unqualified+".", ""), nil
+}
+
+// 为接口生成桩方法，并计算插入目标文件所需的导入编辑
+func generateStub(directory, interfaceQuery, destFile, typeName string) (StubResult, error) {
+	if !token.IsIdentifier(typeName) {
+		return StubResult{}, fmt.Errorf("invalid type name: %q", typeName)
+	}
+	scan := scanDirectory(directory)
+	iface, err := lookupInterface(scan, interfaceQuery)
+	if err != nil {
+		return StubResult{}, err
+	}
+	if len(iface.TypeParams) > 0 {
+		return StubResult{}, fmt.Errorf("interface %s is generic; stubs for generic interfaces are not supported", iface.Name)
+	}
+
+	content, err := os.ReadFile(destFile)
+	if err != nil {
+		return StubResult{}, err
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, destFile, content, parser.ImportsOnly)
+	if err != nil {
+		return StubResult{}, err
+	}
+
+	imports := newStubImports(destFile, f)
+	var code strings.Builder
+	for _, spec := range iface.Specs {
+		signature, err := imports.renderSignature(spec)
+		if err != nil {
+			return StubResult{}, err
+		}
+		// 接收者名与参数同名时改用 _
+		receiver := strings.ToLower(typeName[:1])
+		if spec.Func != nil && (fieldNamed(spec.Func.Params, receiver) || fieldNamed(spec.Func.Results, receiver)) {
+			receiver = "_"
+		}
+		fmt.Fprintf(&code, "\nfunc (%s *%s) %s%s {\n\tpanic(\"not implemented\")\n}\n", receiver, typeName, spec.Name, signature)
+	}
+
+	result := StubResult{
+		Interface: iface.PackageName + "." + iface.Name,
+		TypeName:  typeName,
+		File:      destFile,
+		Code:      code.String(),
+		Imports:   imports.list,
+		Edits:     []TextEdit{},
+	}
+	if edit, ok := importEdit(fset, f, destFile, content, imports.list); ok {
+		result.Edits = append(result.Edits, edit)
+	}
+	result.Edits = append(result.Edits, appendEdit(destFile, content, result.Code))
+	return result, nil
+}
+
+func fieldNamed(list *ast.FieldList, name string) bool {
+	if list == nil {
+		return false
+	}
+	for _, field := range list.List {
+		for _, ident := range field.Names {
+			if ident.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// 新增导入的编辑：加到已有的 import ( ... ) 分组末尾，没有分组时在最后一个导入声明或 package 子句之后新建
+func importEdit(fset *token.FileSet, f *ast.File, destFile string, content []byte, imports []StubImport) (TextEdit, bool) {
+	var specs []string
+	for _, imp := range imports {
+		if imp.Existing {
+			continue
+		}
+		spec := strconv.Quote(imp.Path)
+		if imp.Name != importPackageName(imp.Path) {
+			spec = imp.Name + " " + spec
+		}
+		specs = append(specs, spec)
+	}
+	if len(specs) == 0 {
+		return TextEdit{}, false
+	}
+
+	var last *ast.GenDecl
+	for _, decl := range f.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			last = gen
+		}
+	}
+	if last != nil && last.Rparen.IsValid() {
+		// 插在 ) 之前；) 与导入写在同一行时（例如 import ("fmt")）先换行
+		pos := fset.Position(last.Rparen)
+		location := Location{File: destFile, Line: pos.Line - 1, Column: pos.Column - 1}
+		newText := "\t" + strings.Join(specs, "\n\t") + "\n"
+		if lineStart := pos.Offset - (pos.Column - 1); strings.TrimSpace(string(content[lineStart:pos.Offset])) != "" {
+			newText = "\n" + newText
+		}
+		return TextEdit{Location: location, EndLocation: location, NewText: newText}, true
+	}
+
+	end := f.Name.End()
+	if last != nil {
+		end = last.End()
+	}
+	pos := fset.Position(end)
+	location := Location{File: destFile, Line: pos.Line - 1, Column: pos.Column - 1}
+	return TextEdit{Location: location, EndLocation: location, NewText: "\n\nimport (\n\t" + strings.Join(specs, "\n\t") + "\n)"}, true
+}
+
+// 在文件末尾追加桩方法的编辑
+func appendEdit(destFile string, content []byte, code string) TextEdit {
+	lines := strings.Split(string(content), "\n")
+	location := Location{File: destFile, Line: len(lines) - 1, Column: len(lines[len(lines)-1])}
+	return TextEdit{Location: location, EndLocation: location, NewText: code}
+}
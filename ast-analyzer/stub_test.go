@@ -0,0 +1,99 @@
+package main
+
+import (
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// 按 LSP 的规则应用编辑：位置都相对于原文件，同一位置的编辑按给出的顺序插入
+func applyEdits(t *testing.T, content string, edits []TextEdit) string {
+	t.Helper()
+	lines := strings.SplitAfter(content, "\n")
+	offset := func(l Location) int {
+		if l.Line >= len(lines) || l.Column > len(strings.TrimSuffix(lines[l.Line], "\n")) {
+			t.Fatalf("edit position %d:%d is outside the file", l.Line, l.Column)
+		}
+		n := l.Column
+		for _, line := range lines[:l.Line] {
+			n += len(line)
+		}
+		return n
+	}
+
+	// 从后往前应用；同一位置后给出的编辑先应用，它的文本最终排在前一个编辑之后
+	order := make([]int, len(edits))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		oa, ob := offset(edits[order[a]].Location), offset(edits[order[b]].Location)
+		return oa > ob || oa == ob && order[a] > order[b]
+	})
+	for _, i := range order {
+		start, end := offset(edits[i].Location), offset(edits[i].EndLocation)
+		content = content[:start] + edits[i].NewText + content[end:]
+	}
+	return content
+}
+
+// 模块 ex：接口 iface.Getter 的签名引用 context 和 ex/model，桩方法生成到 impl/i.go 的 T 上。
+// impl/check.go 在编译期断言 *T 实现了接口
+func stubModule(t *testing.T, dest string) string {
+	t.Helper()
+	return writeTree(t, map[string]string{
+		"go.mod":         "module ex\n\ngo 1.21\n",
+		"model/model.go": "package model\n\ntype Item struct{}\n",
+		"iface/iface.go": "package iface\n\nimport (\n\t\"context\"\n\n\t\"ex/model\"\n)\n\ntype Getter interface {\n\tGet(ctx context.Context, id string) (*model.Item, error)\n}\n",
+		"impl/check.go":  "package impl\n\nimport \"ex/iface\"\n\nvar _ iface.Getter = (*T)(nil)\n",
+		"impl/i.go":      dest,
+	})
+}
+
+func TestGenerateStubEditsCompile(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain not available")
+	}
+	t.Setenv("GOFLAGS", "-mod=mod")
+	t.Setenv("GOPROXY", "off")
+	t.Setenv("GOWORK", "off")
+	t.Setenv("GOTOOLCHAIN", "local")
+
+	for _, tc := range []struct {
+		name  string
+		dest  string
+		alias string
+	}{
+		{"one-line group", "package impl\n\nimport (\"fmt\")\n\nvar model = 1\n\nvar _ = fmt.Sprint\n\ntype T struct{}\n", "model2"},
+		{"one-line group with two imports", "package impl\n\nimport (\"fmt\"; \"os\")\n\nvar _, _ = fmt.Sprint, os.Exit\n\ntype T struct{}\n", "model"},
+		{"paren after the last import", "package impl\n\nimport (\n\t\"fmt\")\n\nvar _ = fmt.Sprint\n\ntype T struct{}\n", "model"},
+		{"multi-line group", "package impl\n\nimport (\n\t\"fmt\"\n\tmodel \"strings\"\n)\n\nvar _, _ = fmt.Sprint, model.ToUpper\n\ntype T struct{}\n", "model2"},
+		{"single import", "package impl\n\nimport \"fmt\"\n\nvar _ = fmt.Sprint\n\ntype T struct{}\n", "model"},
+		{"no imports", "package impl\n\ntype T struct{}\n", "model"},
+		{"package-level name", "package impl\n\ntype T struct{}\n\nfunc model() {}\n\nvar _ = model\n", "model2"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := stubModule(t, tc.dest)
+			dest := filepath.Join(dir, "impl", "i.go")
+			result := runArgs(t, "generate-stub", dir, "Getter", dest, "T").(StubResult)
+
+			aliases := make(map[string]string)
+			for _, imp := range result.Imports {
+				aliases[imp.Path] = imp.Name
+			}
+			if aliases["ex/model"] != tc.alias {
+				t.Fatalf("ex/model imported as %q, want %q", aliases["ex/model"], tc.alias)
+			}
+
+			edited := applyEdits(t, tc.dest, result.Edits)
+			writeFile(t, dest, edited)
+			cmd := exec.Command("go", "build", "./...")
+			cmd.Dir = dir
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("go build failed: %v\n%s\nimpl/i.go after the edits:\n%s", err, out, edited)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// 方法体只有一句 panic("not implemented") 之类的桩实现
+type StubImplementation struct {
+	ReceiverType string   `json:"receiverType"`
+	MethodName   string   `json:"methodName"`
+	PanicMessage string   `json:"panicMessage"`
+	Location     Location `json:"location"`
+}
+
+type StubImplementationResult struct {
+	Stubs []StubImplementation `json:"stubs"`
+}
+
+// 方法体是否恰好是一个以字符串字面量为参数的 panic 调用，且消息包含 not implemented 或 TODO（不区分大小写）
+func stubPanicMessage(body *ast.BlockStmt) (*ast.CallExpr, string, bool) {
+	if body == nil || len(body.List) != 1 {
+		return nil, "", false
+	}
+	stmt, ok := body.List[0].(*ast.ExprStmt)
+	if !ok {
+		return nil, "", false
+	}
+	call, ok := stmt.X.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return nil, "", false
+	}
+	if fun, ok := call.Fun.(*ast.Ident); !ok || fun.Name != "panic" {
+		return nil, "", false
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return nil, "", false
+	}
+	message, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return nil, "", false
+	}
+	lower := strings.ToLower(message)
+	if !strings.Contains(lower, "not implemented") && !strings.Contains(lower, "todo") {
+		return nil, "", false
+	}
+	return call, message, true
+}
+
+// 查找仍是桩代码的接口实现方法
+func findStubImplementations(directory string) []StubImplementation {
+	results := []StubImplementation{}
+
+	for _, method := range uniqueImplementedMethods(matchAllImplementations(directory)) {
+		if method.fset == nil {
+			continue
+		}
+		call, message, ok := stubPanicMessage(method.FuncDecl.Body)
+		if !ok {
+			continue
+		}
+		pos := method.fset.Position(call.Pos())
+		results = append(results, StubImplementation{
+			ReceiverType: method.ReceiverType,
+			MethodName:   method.Name,
+			PanicMessage: message,
+			Location:     Location{File: pos.Filename, Line: pos.Line - 1, Column: pos.Column - 1},
+		})
+	}
+
+	return results
+}
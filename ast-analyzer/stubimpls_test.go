@@ -0,0 +1,51 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+const stubImplsFixture = `package p
+
+type Store interface {
+	Get(id string) string
+	Put(id, value string)
+	Delete(id string)
+	Size() int
+	Close()
+}
+
+type Mem struct{}
+
+func (Mem) Get(id string) string { panic("Not Implemented") }
+func (Mem) Put(id, value string) { panic(` + "`TODO: persist`" + `) }
+func (Mem) Delete(id string)     { panic("unreachable") }
+func (Mem) Size() int {
+	println("size")
+	panic("not implemented")
+}
+func (Mem) Close() { panic("not implemented: " + "close") }
+
+func (Mem) Helper() { panic("not implemented") }
+`
+
+func TestPanicOnNotImplementedFindsStubs(t *testing.T) {
+	dir := writeTree(t, map[string]string{"p.go": stubImplsFixture})
+	stubs := runArgs(t, "find-interface-method-panic-on-not-implemented", dir).(StubImplementationResult).Stubs
+
+	// 多条语句、非字面量参数、消息不符以及不属于接口的方法都不报告
+	got := make(map[string]string)
+	for _, stub := range stubs {
+		got[stub.ReceiverType+"."+stub.MethodName] = stub.PanicMessage
+	}
+	want := map[string]string{"Mem.Get": "Not Implemented", "Mem.Put": "TODO: persist"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	sort.Slice(stubs, func(i, j int) bool { return stubs[i].MethodName < stubs[j].MethodName })
+	if line, column := stubs[0].Location.Line, stubs[0].Location.Column; line != 12 || column != 35 {
+		t.Fatalf("Get stub at %d:%d, want the panic call at 12:35", line, column)
+	}
+}
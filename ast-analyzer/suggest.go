@@ -0,0 +1,131 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// 类型只差少量方法就能实现的接口
+type InterfaceSuggestion struct {
+	InterfaceName string `json:"interfaceName"`
+	PackageName   string `json:"packageName"`
+	// 补齐缺失方法后实现接口的类型，需要指针接收者的方法时为 *T
+	ReceiverType string `json:"receiverType"`
+	TypePackage  string `json:"typePackage"`
+	Implemented  int    `json:"implemented"`
+	Total        int    `json:"total"`
+	// 已实现的方法名，以及缺失（或签名不一致）方法的接口签名，可直接用于生成桩代码
+	ImplementedMethods []string `json:"implementedMethods"`
+	MissingMethods     []string `json:"missingMethods"`
+	Location           Location `json:"location"`
+	// --std 时匹配到的标准库接口
+	Stdlib     bool   `json:"stdlib,omitempty"`
+	ImportPath string `json:"importPath,omitempty"`
+}
+
+type SuggestionResult struct {
+	Suggestions []InterfaceSuggestion `json:"suggestions"`
+}
+
+// 按方法集统计接口的实现情况，返回已实现的方法名和缺失方法的签名
+func coverageOf(methods map[string]*MethodInfo, iface *InterfaceInfo) ([]string, []string) {
+	implemented, missing := []string{}, []string{}
+	for _, spec := range iface.Specs {
+		method, ok := methods[spec.Name]
+		if ok && (spec.Key == "" || method.Key == "" || spec.Key == method.Key) {
+			implemented = append(implemented, spec.Name)
+		} else {
+			missing = append(missing, spec.Signature)
+		}
+	}
+	return implemented, missing
+}
+
+// 为类型推荐接近实现的接口：已实现比例不低于 --min-ratio（默认 0.5）且尚未完全实现，
+// 按实现比例从高到低、缺失方法从少到多排序；--std 时也考虑标准库接口
+func suggestInterfaces(directory, query string) []InterfaceSuggestion {
+	suggestions := []InterfaceSuggestion{}
+	minRatio := options.Float("min-ratio", 0.5)
+
+	scan := scanDirectory(directory)
+	// T 和 *T 合并为同一个类型，*T 的方法集包含 T 的方法
+	bases := make(map[string]bool)
+	for typeKey := range matchingTypeKeys(scan, query) {
+		bases[strings.Replace(typeKey, ":*", ":", 1)] = true
+	}
+	baseKeys := make([]string, 0, len(bases))
+	for baseKey := range bases {
+		baseKeys = append(baseKeys, baseKey)
+	}
+	sort.Strings(baseKeys)
+
+	interfaces := make([]*InterfaceInfo, 0, len(scan.Interfaces))
+	for i := range scan.Interfaces {
+		interfaces = append(interfaces, &scan.Interfaces[i])
+	}
+	// 工作区接口在前，之后是标准库接口
+	workspaceCount := len(interfaces)
+	if options.Has("std") {
+		stdlib := loadStdlibIndex().interfaces()
+		for i := range stdlib {
+			interfaces = append(interfaces, &stdlib[i])
+		}
+	}
+
+	for _, baseKey := range baseKeys {
+		pkg, name := splitTypeKey(baseKey)
+		valueMethods := scan.TypeMethods[baseKey]
+		pointerMethods := scan.TypeMethods[pkg+":*"+name]
+		if pointerMethods == nil {
+			pointerMethods = valueMethods
+		}
+
+		for i, iface := range interfaces {
+			if len(iface.Specs) == 0 || !iface.visibleTo(pkg) {
+				continue
+			}
+			implemented, missing := coverageOf(pointerMethods, iface)
+			if len(missing) == 0 || len(implemented) == 0 || float64(len(implemented)) < minRatio*float64(len(iface.Specs)) {
+				continue
+			}
+			receiver := name
+			if valueImplemented, _ := coverageOf(valueMethods, iface); len(valueImplemented) < len(implemented) {
+				receiver = "*" + name
+			}
+
+			suggestion := InterfaceSuggestion{
+				InterfaceName:      iface.Name,
+				PackageName:        iface.PackageName,
+				ReceiverType:       receiver,
+				TypePackage:        pkg,
+				Implemented:        len(implemented),
+				Total:              len(iface.Specs),
+				ImplementedMethods: implemented,
+				MissingMethods:     missing,
+				Location:           iface.Location,
+			}
+			if i >= workspaceCount {
+				suggestion.Stdlib = true
+				suggestion.ImportPath = iface.Package
+			}
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		// 比较 a.Implemented/a.Total 与 b.Implemented/b.Total，避免浮点误差
+		if ra, rb := a.Implemented*b.Total, b.Implemented*a.Total; ra != rb {
+			return ra > rb
+		}
+		if len(a.MissingMethods) != len(b.MissingMethods) {
+			return len(a.MissingMethods) < len(b.MissingMethods)
+		}
+		if a.PackageName != b.PackageName {
+			return a.PackageName < b.PackageName
+		}
+		return a.InterfaceName < b.InterfaceName
+	})
+
+	return suggestions
+}
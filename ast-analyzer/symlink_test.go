@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func symlink(t *testing.T, target, link string) {
+	t.Helper()
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+}
+
+// 同一个包通过目录链接、文件链接和回到上级的循环链接重复出现；ext 链接到扫描目录之外的包
+func TestSymlinkedPackagesAreReportedOnce(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"a.go":        serveFixtureInterface,
+		"pkg/impl.go": "package pkg\n\ntype Impl struct{}\n\nfunc (Impl) Run() {}\n",
+	})
+	outside := writeTree(t, map[string]string{"ext.go": "package ext\n\ntype Ext struct{}\n\nfunc (Ext) Run() {}\n"})
+	// 这些链接在遍历顺序中都排在 pkg 之前
+	symlink(t, "pkg", filepath.Join(dir, "alink"))
+	symlink(t, filepath.Join("pkg", "impl.go"), filepath.Join(dir, "impl.go"))
+	symlink(t, "..", filepath.Join(dir, "pkg", "loop"))
+	symlink(t, outside, filepath.Join(dir, "ext"))
+
+	want := map[string]string{
+		"A":    filepath.Join(dir, "a.go"),
+		"Ext":  filepath.Join(dir, "ext", "ext.go"),
+		"Impl": filepath.Join(dir, "pkg", "impl.go"),
+	}
+	for _, jobs := range []string{"1", "4"} {
+		for run := 0; run < 3; run++ {
+			result := runArgs(t, "find-implementations", dir, "Run", "--jobs", jobs).(AnalysisResult)
+			got := make(map[string]string)
+			var receivers []string
+			for _, impl := range result.Implementations {
+				got[impl.ReceiverType] = impl.Location.File
+				receivers = append(receivers, impl.ReceiverType)
+			}
+			sort.Strings(receivers)
+			if !reflect.DeepEqual(receivers, []string{"A", "Ext", "Impl"}) || !reflect.DeepEqual(got, want) {
+				t.Fatalf("--jobs %s run %d: got %v at %v, want each of %v once", jobs, run, receivers, got, want)
+			}
+		}
+	}
+}
+
+func TestDedupeSymlinkedPrefersTheRealPath(t *testing.T) {
+	dir := writeTree(t, map[string]string{"pkg/a.go": "package pkg\n"})
+	symlink(t, "pkg", filepath.Join(dir, "alink"))
+	symlink(t, filepath.Join("pkg", "a.go"), filepath.Join(dir, "b.go"))
+
+	real := filepath.Join(dir, "pkg", "a.go")
+	for _, paths := range [][]string{
+		{filepath.Join(dir, "alink", "a.go"), filepath.Join(dir, "b.go"), real},
+		{real, filepath.Join(dir, "b.go"), filepath.Join(dir, "alink", "a.go")},
+	} {
+		kept := dedupeSymlinked(dir, paths)
+		if len(kept) != 1 || paths[kept[0]] != real {
+			t.Fatalf("dedupeSymlinked(%v) kept %v, want only %s", paths, kept, real)
+		}
+	}
+}
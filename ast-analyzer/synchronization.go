@@ -0,0 +1,78 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// 使用了同步原语的接口实现方法
+type SynchronizedMethod struct {
+	ReceiverType string `json:"receiverType"`
+	MethodName   string `json:"methodName"`
+	// mutex、rwmutex、atomic 或 channel
+	SyncKind string   `json:"syncKind"`
+	Location Location `json:"location"`
+}
+
+type SynchronizationResult struct {
+	Methods []SynchronizedMethod `json:"methods"`
+}
+
+// 同步原语的检查顺序，也是输出顺序
+var syncKinds = []string{"mutex", "rwmutex", "atomic", "channel"}
+
+// 扫描方法体中使用的同步原语。没有类型信息，按调用形式判断：
+// RLock/RUnlock 视为读写锁，Lock/Unlock/TryLock 视为互斥锁，atomic.X 视为原子操作，
+// 发送、接收和 select 视为 channel 操作
+func syncKindsOf(body *ast.BlockStmt) map[string]bool {
+	kinds := make(map[string]bool)
+	if body == nil {
+		return kinds
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.SelectorExpr:
+			if ident, ok := node.X.(*ast.Ident); ok && ident.Name == "atomic" {
+				kinds["atomic"] = true
+				break
+			}
+			switch node.Sel.Name {
+			case "RLock", "RUnlock", "TryRLock", "RLocker":
+				kinds["rwmutex"] = true
+			case "Lock", "Unlock", "TryLock":
+				kinds["mutex"] = true
+			}
+		case *ast.SendStmt, *ast.SelectStmt:
+			kinds["channel"] = true
+		case *ast.UnaryExpr:
+			if node.Op == token.ARROW {
+				kinds["channel"] = true
+			}
+		}
+		return true
+	})
+	return kinds
+}
+
+// 查找方法体中使用了锁、原子操作或 channel 的接口实现方法
+func findSynchronizedMethods(directory string) []SynchronizedMethod {
+	results := []SynchronizedMethod{}
+
+	for _, method := range uniqueImplementedMethods(matchAllImplementations(directory)) {
+		kinds := syncKindsOf(method.FuncDecl.Body)
+		for _, kind := range syncKinds {
+			if !kinds[kind] {
+				continue
+			}
+			results = append(results, SynchronizedMethod{
+				ReceiverType: method.ReceiverType,
+				MethodName:   method.Name,
+				SyncKind:     kind,
+				Location:     method.Location,
+			})
+		}
+	}
+
+	return results
+}
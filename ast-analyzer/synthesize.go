@@ -0,0 +1,101 @@
+package main
+
+import (
+	"go/ast"
+	"sort"
+	"strings"
+)
+
+// 由类型的导出方法合成的接口，用于“提取接口”重构
+type SynthesizedInterface struct {
+	TypeName      string `json:"typeName"`
+	TypePackage   string `json:"typePackage"`
+	InterfaceName string `json:"interfaceName"`
+	// 导出方法的签名，按声明位置排序
+	Methods []string `json:"methods"`
+	// 可直接粘贴的接口声明
+	Declaration string   `json:"declaration"`
+	Location    Location `json:"location"`
+}
+
+type SynthesizeResult struct {
+	Interfaces []SynthesizedInterface `json:"interfaces"`
+}
+
+// 由类型的导出方法集（包括指针接收者方法和嵌入字段提升的方法）合成接口声明，
+// 接口名由 --name 指定，默认为 类型名+Interface；泛型类型的类型参数原样带到接口上
+func synthesizeInterface(directory, query string) []SynthesizedInterface {
+	results := []SynthesizedInterface{}
+
+	scan := scanDirectory(directory)
+	bases := make(map[string]bool)
+	for typeKey := range matchingTypeKeys(scan, query) {
+		bases[strings.Replace(typeKey, ":*", ":", 1)] = true
+	}
+	baseKeys := make([]string, 0, len(bases))
+	for baseKey := range bases {
+		baseKeys = append(baseKeys, baseKey)
+	}
+	sort.Strings(baseKeys)
+
+	for _, baseKey := range baseKeys {
+		pkg, name := splitTypeKey(baseKey)
+		// *T 的方法集包含 T 的方法
+		methodSet := scan.TypeMethods[pkg+":*"+name]
+		if methodSet == nil {
+			methodSet = scan.TypeMethods[baseKey]
+		}
+
+		var methods []*MethodInfo
+		for methodName, method := range methodSet {
+			if ast.IsExported(methodName) {
+				methods = append(methods, method)
+			}
+		}
+		if len(methods) == 0 {
+			continue
+		}
+		sort.Slice(methods, func(i, j int) bool {
+			a, b := methods[i].Location, methods[j].Location
+			if a.File != b.File {
+				return a.File < b.File
+			}
+			if a.Line != b.Line {
+				return a.Line < b.Line
+			}
+			return methods[i].Name < methods[j].Name
+		})
+
+		synthesized := SynthesizedInterface{
+			TypeName:      name,
+			TypePackage:   pkg,
+			InterfaceName: options.String("name", name+"Interface"),
+			Methods:       []string{},
+		}
+		header := synthesized.InterfaceName
+		if info, ok := scan.Types[baseKey]; ok {
+			synthesized.Location = info.Location
+			if len(info.TypeParams) > 0 {
+				params := make([]string, 0, len(info.TypeParams))
+				for _, param := range info.TypeParams {
+					params = append(params, param.Name+" "+param.Constraint)
+				}
+				header += "[" + strings.Join(params, ", ") + "]"
+			}
+		}
+
+		var b strings.Builder
+		b.WriteString("type " + header + " interface {\n")
+		for _, method := range methods {
+			signature := method.Signature()
+			synthesized.Methods = append(synthesized.Methods, signature)
+			b.WriteString("\t" + signature + "\n")
+		}
+		b.WriteString("}\n")
+		synthesized.Declaration = b.String()
+
+		results = append(results, synthesized)
+	}
+
+	return results
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"strings"
+)
+
+// 按 Test<类型名>_<方法名> 命名约定找到的接口方法实现的测试函数
+type TestCoverage struct {
+	Interface   string `json:"interface"`
+	Method      string `json:"method"`
+	Implementor string `json:"implementor"`
+	TestFunc    string `json:"testFunc"`
+	TestFile    string `json:"testFile"`
+	// 测试函数名的位置
+	Location Location `json:"location"`
+}
+
+// 没有找到对应测试函数的接口方法实现
+type UntestedException struct {
+	Interface   string `json:"interface"`
+	Method      string `json:"method"`
+	Implementor string `json:"implementor"`
+}
+
+type TestCoverageResult struct {
+	Tested   []TestCoverage      `json:"tested"`
+	Untested []UntestedException `json:"untested"`
+}
+
+// _test.go 文件中的测试函数
+type testFunc struct {
+	name     string
+	location Location
+}
+
+// 按目录收集 _test.go 文件中的 TestXxx 函数（内部和外部测试包都算在该目录下）
+func collectTestFuncs(directory string) map[string][]testFunc {
+	tests := make(map[string][]testFunc)
+	walkGoFiles(directory, func(path string, f *ast.File, fset *token.FileSet) {
+		if !strings.HasSuffix(path, "_test.go") {
+			return
+		}
+		dir := filepath.Dir(path)
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || !strings.HasPrefix(fn.Name.Name, "Test") {
+				continue
+			}
+			pos := fset.Position(fn.Name.Pos())
+			tests[dir] = append(tests[dir], testFunc{
+				name:     fn.Name.Name,
+				location: Location{File: path, Line: pos.Line - 1, Column: pos.Column - 1},
+			})
+		}
+	})
+	return tests
+}
+
+// 测试函数名是否符合 Test<类型名>_<方法名>，允许再带 _<场景> 后缀，例如 TestSimpleTokenManager_AddToken_Empty
+func testsMethod(testName, typeName, methodName string) bool {
+	prefix := "Test" + typeName + "_" + methodName
+	return testName == prefix || strings.HasPrefix(testName, prefix+"_")
+}
+
+// 把接口方法的实现和同目录测试文件中按命名约定对应的测试函数关联起来。需要 --include-tests 才会遍历测试文件
+func findTestCoverage(directory string) TestCoverageResult {
+	result := TestCoverageResult{Tested: []TestCoverage{}, Untested: []UntestedException{}}
+	tests := collectTestFuncs(directory)
+	seen := make(map[string]bool)
+
+	for _, match := range matchAllImplementations(directory) {
+		pkg, receiverType := splitTypeKey(match.TypeKey)
+		dir := strings.TrimSuffix(pkg, "_test")
+		typeName := strings.TrimPrefix(receiverType, "*")
+		if i := strings.Index(typeName, "["); i >= 0 {
+			typeName = typeName[:i]
+		}
+
+		for _, method := range match.ImplementedMethods() {
+			key := match.Interface.Name + "." + method.Name + "@" + dir + ":" + typeName
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			tested := false
+			for _, test := range tests[dir] {
+				if !testsMethod(test.name, typeName, method.Name) {
+					continue
+				}
+				tested = true
+				result.Tested = append(result.Tested, TestCoverage{
+					Interface:   match.Interface.Name,
+					Method:      method.Name,
+					Implementor: typeName,
+					TestFunc:    test.name,
+					TestFile:    test.location.File,
+					Location:    test.location,
+				})
+			}
+			if !tested {
+				result.Untested = append(result.Untested, UntestedException{
+					Interface:   match.Interface.Name,
+					Method:      method.Name,
+					Implementor: typeName,
+				})
+			}
+		}
+	}
+
+	return result
+}
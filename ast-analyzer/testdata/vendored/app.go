@@ -0,0 +1,12 @@
+package app
+
+import "example.com/dep"
+
+type memory map[string]string
+
+func (m memory) Get(key string) (string, bool) {
+	value, ok := m[key]
+	return value, ok
+}
+
+var _ dep.Store = memory{}
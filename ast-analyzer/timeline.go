@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// 实现方法所在文件的最后修改信息（来自 git log）。
+// 不在 git 仓库中的文件没有 LastModified；在仓库中但还没有提交过的文件标记为 Untracked
+type ImplementationTimeline struct {
+	ReceiverType string     `json:"receiverType"`
+	MethodName   string     `json:"methodName"`
+	Location     Location   `json:"location"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	Author       string     `json:"author,omitempty"`
+	Untracked    bool       `json:"untracked,omitempty"`
+}
+
+type TimelineResult struct {
+	Timeline []ImplementationTimeline `json:"timeline"`
+}
+
+// 文件的最后一次提交信息
+type fileCommit struct {
+	time   time.Time
+	author string
+}
+
+// 运行 git 命令，返回去掉首尾空白的输出
+func runGit(dir string, args ...string) (string, bool) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(out)), true
+}
+
+// 转为绝对路径并解析符号链接，保证仓库根目录和文件路径可以互相求相对路径
+func resolvePath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return path
+}
+
+// 用一次 git log --name-only 查出 files 中每个文件最后一次提交的时间和作者。
+// 提交按时间倒序输出，每个文件第一次出现的提交就是它的最后一次提交；所有文件都找到后提前结束。
+// 返回值 untracked 是位于仓库工作区中但没有提交记录的文件；directory 不在 git 仓库中时报告诊断
+func lastFileCommits(directory string, files []string) (commits map[string]fileCommit, untracked map[string]bool) {
+	commits = make(map[string]fileCommit)
+	untracked = make(map[string]bool)
+
+	top, ok := runGit(directory, "rev-parse", "--show-toplevel")
+	if !ok {
+		reportDiagnostic(Diagnostic{Kind: "git", Message: "not a git repository, last-modified times are unavailable: " + directory})
+		return commits, untracked
+	}
+	top = resolvePath(top)
+
+	// 仓库内相对路径 -> 文件；pathspec 只包含这些文件所在的目录
+	pending := make(map[string]string)
+	var pathspecs []string
+	seenDirs := make(map[string]bool)
+	for _, file := range files {
+		dir := resolvePath(filepath.Dir(file))
+		rel, err := filepath.Rel(top, filepath.Join(dir, filepath.Base(file)))
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		pending[filepath.ToSlash(rel)] = file
+		relDir := filepath.ToSlash(filepath.Dir(rel))
+		if relDir == "." {
+			// 仓库根目录：魔法前缀后不带路径
+			relDir = ""
+		}
+		if !seenDirs[relDir] {
+			seenDirs[relDir] = true
+			pathspecs = append(pathspecs, ":(top,literal)"+relDir)
+		}
+	}
+	if len(pending) == 0 {
+		return commits, untracked
+	}
+
+	args := append([]string{"-c", "core.quotePath=false", "log", "--format=%x00%aI%x00%an", "--name-only", "--"}, pathspecs...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = top
+	stdout, err := cmd.StdoutPipe()
+	if err == nil {
+		err = cmd.Start()
+	}
+	if err != nil {
+		reportDiagnostic(Diagnostic{Kind: "git", Message: "git log: " + err.Error()})
+		return commits, untracked
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	var current fileCommit
+	for len(pending) > 0 && scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "\x00") {
+			parts := strings.SplitN(line[1:], "\x00", 2)
+			current = fileCommit{}
+			if len(parts) == 2 {
+				if modified, err := time.Parse(time.RFC3339, parts[0]); err == nil {
+					current = fileCommit{time: modified, author: parts[1]}
+				}
+			}
+			continue
+		}
+		if file, ok := pending[line]; ok && !current.time.IsZero() {
+			commits[file] = current
+			delete(pending, line)
+		}
+	}
+	if len(pending) == 0 {
+		// 剩余的历史不再需要
+		cmd.Process.Kill()
+	}
+	cmd.Wait()
+
+	for _, file := range pending {
+		untracked[file] = true
+	}
+	return commits, untracked
+}
+
+// 解析 --since 参数，支持 2006-01-02 和 RFC3339 两种格式
+func parseSince(value string) (time.Time, bool) {
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if since, err := time.Parse(layout, value); err == nil {
+			return since, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// 列出所有实现方法及其所在文件的最后修改时间，可按 since 过滤。
+// 未提交的文件是最新的改动，总是保留；不在仓库中的文件没有时间，指定 since 时过滤掉
+func findAdoptionTimeline(directory string, since time.Time) []ImplementationTimeline {
+	timeline := []ImplementationTimeline{}
+	methods := uniqueImplementedMethods(matchAllImplementations(directory))
+	if len(methods) == 0 {
+		return timeline
+	}
+
+	var files []string
+	seen := make(map[string]bool)
+	for _, method := range methods {
+		if file := method.Location.File; !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+	gitDir := directory
+	if info, err := os.Stat(directory); err == nil && !info.IsDir() {
+		gitDir = filepath.Dir(directory)
+	}
+	commits, untracked := lastFileCommits(gitDir, files)
+
+	for _, method := range methods {
+		file := method.Location.File
+		entry := ImplementationTimeline{
+			ReceiverType: method.ReceiverType,
+			MethodName:   method.Name,
+			Location:     method.Location,
+			Untracked:    untracked[file],
+		}
+		if commit, ok := commits[file]; ok {
+			modified := commit.time
+			entry.LastModified = &modified
+			entry.Author = commit.author
+		}
+
+		if !since.IsZero() && !entry.Untracked && (entry.LastModified == nil || entry.LastModified.Before(since)) {
+			continue
+		}
+		timeline = append(timeline, entry)
+	}
+
+	return timeline
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 在 dir 中以指定作者和时间执行 git 命令
+func gitAt(t *testing.T, dir, author, date string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(cmd.Environ(),
+		"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_NOSYSTEM=1",
+		"GIT_AUTHOR_NAME="+author, "GIT_AUTHOR_EMAIL="+author+"@example.com", "GIT_AUTHOR_DATE="+date,
+		"GIT_COMMITTER_NAME="+author, "GIT_COMMITTER_EMAIL="+author+"@example.com", "GIT_COMMITTER_DATE="+date)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+}
+
+// a.go 和 sub/b.go 分别由 alice、bob 提交，c.go 没有提交
+func timelineRepository(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := writeTree(t, map[string]string{
+		"iface.go": "package p\n\ntype Runner interface {\n\tRun()\n}\n",
+		"a.go":     "package p\n\ntype A struct{}\n\nfunc (A) Run() {}\n",
+	})
+	gitAt(t, dir, "alice", "2024-01-10T12:00:00Z", "init", "-q")
+	gitAt(t, dir, "alice", "2024-01-10T12:00:00Z", "add", ".")
+	gitAt(t, dir, "alice", "2024-01-10T12:00:00Z", "commit", "-q", "-m", "a")
+	writeFile(t, filepath.Join(dir, "sub", "b.go"), "package sub\n\ntype B struct{}\n\nfunc (B) Run() {}\n")
+	gitAt(t, dir, "bob", "2024-03-05T08:30:00Z", "add", "sub")
+	gitAt(t, dir, "bob", "2024-03-05T08:30:00Z", "commit", "-q", "-m", "b")
+	// a.go 之后的提交不涉及 a.go，它的最后修改时间仍是第一次提交
+	writeFile(t, filepath.Join(dir, "c.go"), "package p\n\ntype C struct{}\n\nfunc (C) Run() {}\n")
+	return dir
+}
+
+func timelineByReceiver(t *testing.T, args ...string) map[string]ImplementationTimeline {
+	t.Helper()
+	result := runArgs(t, append([]string{"find-interface-adoption-timeline"}, args...)...).(TimelineResult)
+	byReceiver := make(map[string]ImplementationTimeline)
+	for _, entry := range result.Timeline {
+		byReceiver[entry.ReceiverType] = entry
+	}
+	return byReceiver
+}
+
+func TestAdoptionTimelineReportsCommitsAndUntrackedFiles(t *testing.T) {
+	dir := timelineRepository(t)
+	timeline := timelineByReceiver(t, dir)
+
+	for receiver, want := range map[string]struct {
+		author string
+		date   string
+	}{"A": {"alice", "2024-01-10T12:00:00Z"}, "B": {"bob", "2024-03-05T08:30:00Z"}} {
+		entry := timeline[receiver]
+		if entry.LastModified == nil || entry.LastModified.UTC().Format(time.RFC3339) != want.date || entry.Author != want.author || entry.Untracked {
+			t.Errorf("%s: got %+v, want %s at %s", receiver, entry, want.author, want.date)
+		}
+	}
+
+	untracked := timeline["C"]
+	if !untracked.Untracked || untracked.LastModified != nil || untracked.Author != "" {
+		t.Fatalf("C: got %+v, want an untracked entry without a commit", untracked)
+	}
+	encoded, _ := json.Marshal(untracked)
+	if strings.Contains(string(encoded), "lastModified") {
+		t.Fatalf("untracked entry encodes lastModified: %s", encoded)
+	}
+}
+
+func TestAdoptionTimelineSinceKeepsUntrackedFiles(t *testing.T) {
+	dir := timelineRepository(t)
+	timeline := timelineByReceiver(t, dir, "--since", "2024-02-01")
+
+	if _, ok := timeline["A"]; ok || len(timeline) != 2 || timeline["B"].Author != "bob" || !timeline["C"].Untracked {
+		t.Fatalf("got %+v, want B and the untracked C", timeline)
+	}
+}
+
+func TestAdoptionTimelineOutsideRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := writeTree(t, map[string]string{
+		"iface.go": "package p\n\ntype Runner interface {\n\tRun()\n}\n",
+		"a.go":     "package p\n\ntype A struct{}\n\nfunc (A) Run() {}\n",
+	})
+	t.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(dir))
+
+	entry := timelineByReceiver(t, dir)["A"]
+	if entry.LastModified != nil || entry.Untracked {
+		t.Fatalf("got %+v, want neither a commit nor untracked", entry)
+	}
+	if found := diagnosticsOfKind("git"); len(found) != 1 {
+		t.Fatalf("got git diagnostics %v, want one", found)
+	}
+}
@@ -0,0 +1,235 @@
+// genstdlib 从 GOROOT 源码生成 stdlib_interfaces.json：常用标准库包中导出接口的方法签名（已展开嵌入接口）。
+//
+//	go run ./tools/genstdlib -o stdlib_interfaces.json
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// 需要收录的标准库包
+var packages = []string{
+	"bufio", "container/heap", "context", "crypto", "database/sql", "database/sql/driver",
+	"encoding", "encoding/binary", "encoding/gob", "encoding/json", "encoding/xml",
+	"flag", "fmt", "go/ast", "hash", "image", "image/color", "image/draw",
+	"io", "io/fs", "log/slog", "math/rand", "net", "net/http", "os",
+	"runtime", "sort", "sync",
+}
+
+// 接口中直接声明的方法
+type rawMethod struct {
+	name string
+	fset *token.FileSet
+	ft   *ast.FuncType
+	pkg  string
+}
+
+// 接口的原始定义：直接声明的方法和嵌入的接口
+type rawInterface struct {
+	pkg     string
+	methods []rawMethod
+	embeds  []string // 同包为 Name，其他包为 import/path.Name
+	invalid bool     // 含类型约束或无法解析的嵌入
+}
+
+func main() {
+	output := flag.String("o", "stdlib_interfaces.json", "output file")
+	flag.Parse()
+
+	goroot := os.Getenv("GOROOT")
+	if goroot == "" {
+		out, err := exec.Command("go", "env", "GOROOT").Output()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "无法获取 GOROOT: %v\n", err)
+			os.Exit(1)
+		}
+		goroot = strings.TrimSpace(string(out))
+	}
+
+	raw := make(map[string]*rawInterface) // import/path.Name -> 定义
+	for _, pkg := range packages {
+		parsePackage(goroot, pkg, raw)
+	}
+
+	result := make(map[string]map[string][]string)
+	for key, iface := range raw {
+		flattened, ok := flatten(key, raw, map[string]bool{})
+		if !ok || len(flattened) == 0 {
+			continue
+		}
+		var methods []string
+		for _, method := range flattened {
+			methods = append(methods, render(method, iface.pkg))
+		}
+		name := key[strings.LastIndex(key, ".")+1:]
+		if result[iface.pkg] == nil {
+			result[iface.pkg] = make(map[string][]string)
+		}
+		result[iface.pkg][name] = methods
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "编码失败: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*output, append(data, '\n'), 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "写入失败: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func parsePackage(goroot, pkg string, raw map[string]*rawInterface) {
+	dir := filepath.Join(goroot, "src", filepath.FromSlash(pkg))
+	files, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	fset := token.NewFileSet()
+
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, parser.SkipObjectResolution)
+		// 包名与目录名不一致的是 // +build ignore 之类的生成器
+		if err != nil || f.Name.Name != filepath.Base(dir) {
+			continue
+		}
+
+		imports := make(map[string]string)
+		for _, spec := range f.Imports {
+			path, _ := strconv.Unquote(spec.Path.Value)
+			name := path[strings.LastIndex(path, "/")+1:]
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+			imports[name] = path
+		}
+
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				interfaceType, ok := typeSpec.Type.(*ast.InterfaceType)
+				if !ok || !typeSpec.Name.IsExported() || typeSpec.TypeParams != nil {
+					continue
+				}
+				iface := &rawInterface{pkg: pkg}
+				for _, field := range interfaceType.Methods.List {
+					switch t := field.Type.(type) {
+					case *ast.FuncType:
+						for _, name := range field.Names {
+							iface.methods = append(iface.methods, rawMethod{name: name.Name, fset: fset, ft: t, pkg: pkg})
+						}
+					case *ast.Ident:
+						if t.Name == "error" {
+							iface.embeds = append(iface.embeds, ".error")
+						} else {
+							iface.embeds = append(iface.embeds, pkg+"."+t.Name)
+						}
+					case *ast.SelectorExpr:
+						x, ok := t.X.(*ast.Ident)
+						if !ok || imports[x.Name] == "" {
+							iface.invalid = true
+							continue
+						}
+						iface.embeds = append(iface.embeds, imports[x.Name]+"."+t.Sel.Name)
+					default:
+						iface.invalid = true
+					}
+				}
+				raw[pkg+"."+typeSpec.Name.Name] = iface
+			}
+		}
+	}
+}
+
+// 展开嵌入的接口；嵌入的接口不在收录范围内时整个接口放弃
+func flatten(key string, raw map[string]*rawInterface, visiting map[string]bool) ([]rawMethod, bool) {
+	if key == ".error" {
+		return []rawMethod{errorMethod}, true
+	}
+	iface, ok := raw[key]
+	if !ok || iface.invalid || visiting[key] {
+		return nil, false
+	}
+	visiting[key] = true
+	defer delete(visiting, key)
+
+	methods := append([]rawMethod{}, iface.methods...)
+	for _, embed := range iface.embeds {
+		embedded, ok := flatten(embed, raw, visiting)
+		if !ok {
+			return nil, false
+		}
+		for _, method := range embedded {
+			if !containsMethod(methods, method.name) {
+				methods = append(methods, method)
+			}
+		}
+	}
+	return methods, true
+}
+
+// 预声明 error 接口的方法
+var errorMethod = rawMethod{
+	name: "Error",
+	fset: token.NewFileSet(),
+	ft:   &ast.FuncType{Params: &ast.FieldList{}, Results: &ast.FieldList{List: []*ast.Field{{Type: ast.NewIdent("string")}}}},
+}
+
+func containsMethod(methods []rawMethod, name string) bool {
+	for _, m := range methods {
+		if m.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// 渲染方法签名，例如 Read(p []byte) (n int, err error)。
+// 来自其他包的方法中，该包的导出类型以包名限定，例如 io 包中的 Writer 写作 io.Writer
+func render(method rawMethod, pkg string) string {
+	var renamed []*ast.Ident
+	if method.pkg != "" && method.pkg != pkg {
+		qualifier := method.pkg[strings.LastIndex(method.pkg, "/")+1:] + "."
+		var visit func(n ast.Node) bool
+		visit = func(n ast.Node) bool {
+			switch t := n.(type) {
+			case *ast.SelectorExpr:
+				return false
+			case *ast.Field:
+				// 参数名不是类型，只处理类型部分
+				ast.Inspect(t.Type, visit)
+				return false
+			case *ast.Ident:
+				if t.IsExported() {
+					renamed = append(renamed, t)
+					t.Name = qualifier + t.Name
+				}
+			}
+			return true
+		}
+		ast.Inspect(method.ft, visit)
+	}
+
+	var buf bytes.Buffer
+	printer.Fprint(&buf, method.fset, method.ft)
+	for _, ident := range renamed {
+		ident.Name = ident.Name[strings.Index(ident.Name, ".")+1:]
+	}
+	return method.name + strings.TrimPrefix(buf.String(), "func")
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"go/ast"
+)
+
+// 在方法中开启并提交或回滚事务的接口实现方法
+type TransactionMethod struct {
+	ReceiverType string `json:"receiverType"`
+	MethodName   string `json:"methodName"`
+	// 开启事务的调用位置
+	Location Location `json:"location"`
+	// 是否用 defer 保证回滚：defer tx.Rollback() 或 defer 的函数字面量中调用 Rollback
+	HasDefer bool `json:"hasDefer"`
+}
+
+type TransactionResult struct {
+	Methods []TransactionMethod `json:"methods"`
+}
+
+// 开启事务的方法名：database/sql 的 Begin、BeginTx，sqlx 的 Beginx、MustBegin 等
+var beginFuncNames = map[string]bool{"Begin": true, "BeginTx": true, "Beginx": true, "BeginTxx": true, "MustBegin": true, "MustBeginTx": true}
+
+// 方法调用的方法名，不是 x.M(...) 形式时返回空字符串
+func calledMethodName(n ast.Node) string {
+	call, ok := n.(*ast.CallExpr)
+	if !ok {
+		return ""
+	}
+	if selector, ok := call.Fun.(*ast.SelectorExpr); ok {
+		return selector.Sel.Name
+	}
+	return ""
+}
+
+// 查找方法体中既开启事务（x.Begin() 等）、又调用了 Commit 或 Rollback 的接口实现方法
+func findTransactionMethods(directory string) []TransactionMethod {
+	results := []TransactionMethod{}
+
+	for _, method := range uniqueImplementedMethods(matchAllImplementations(directory)) {
+		if method.fset == nil || method.FuncDecl.Body == nil {
+			continue
+		}
+
+		var begin ast.Node
+		finishes, hasDefer := false, false
+		ast.Inspect(method.FuncDecl.Body, func(n ast.Node) bool {
+			if deferStmt, ok := n.(*ast.DeferStmt); ok {
+				ast.Inspect(deferStmt.Call, func(inner ast.Node) bool {
+					hasDefer = hasDefer || calledMethodName(inner) == "Rollback"
+					return !hasDefer
+				})
+			}
+			switch name := calledMethodName(n); {
+			case beginFuncNames[name]:
+				if begin == nil {
+					begin = n
+				}
+			case name == "Commit" || name == "Rollback":
+				finishes = true
+			}
+			return true
+		})
+		if begin == nil || !finishes {
+			continue
+		}
+
+		pos := method.fset.Position(begin.Pos())
+		results = append(results, TransactionMethod{
+			ReceiverType: method.ReceiverType,
+			MethodName:   method.Name,
+			Location:     Location{File: pos.Filename, Line: pos.Line - 1, Column: pos.Column - 1},
+			HasDefer:     hasDefer,
+		})
+	}
+
+	return results
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// 类型实现的接口
+type TypeInterface struct {
+	InterfaceName string `json:"interfaceName"`
+	PackageName   string `json:"packageName"`
+	// 实现接口的类型，指针接收者实现时为 *T
+	ReceiverType string   `json:"receiverType"`
+	TypePackage  string   `json:"typePackage"`
+	Location     Location `json:"location"`
+	// 类型的定义形式：struct、func（函数类型适配器）、alias 等
+	Kind string `json:"kind,omitempty"`
+	// --std 时匹配到的标准库接口
+	Stdlib     bool   `json:"stdlib,omitempty"`
+	ImportPath string `json:"importPath,omitempty"`
+	// 类型位于接口所在包的外部测试包（package foo_test）中
+	ExternalTestPackage bool `json:"externalTestPackage,omitempty"`
+}
+
+type TypeInterfacesResult struct {
+	Interfaces []TypeInterface `json:"interfaces"`
+}
+
+// 解析类型查询：T、*T、pkg.T 或 import/path.T，返回限定符和类型名
+func parseTypeQuery(query string) (string, string) {
+	query = strings.TrimPrefix(query, "*")
+	if dot := strings.LastIndex(query, "."); dot >= 0 {
+		return query[:dot], query[dot+1:]
+	}
+	return "", query
+}
+
+// 包目录对应的导入路径（模块路径加相对目录），不在模块中时返回空字符串
+func packageImportPath(dir string) string {
+	root := findModuleRoot(dir)
+	if root == "" {
+		return ""
+	}
+	module := parseGoMod(filepath.Join(root, "go.mod"))
+	abs, err := filepath.Abs(dir)
+	if err != nil || module.Path == "" {
+		return ""
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil {
+		return ""
+	}
+	return path.Join(module.Path, filepath.ToSlash(rel))
+}
+
+// 限定符是否指向该包：包名、目录名、导入路径或以 / 分隔的目录后缀
+func packageMatches(dir, packageName, qualifier string) bool {
+	if qualifier == "" {
+		return true
+	}
+	if !strings.Contains(qualifier, "/") {
+		return qualifier == packageName || qualifier == filepath.Base(dir)
+	}
+	if packageImportPath(dir) == qualifier {
+		return true
+	}
+	slashDir := filepath.ToSlash(dir)
+	return slashDir == qualifier || strings.HasSuffix(slashDir, "/"+strings.Trim(qualifier, "/"))
+}
+
+// 与类型查询匹配的方法集键（pkg:T 和 pkg:*T）
+func matchingTypeKeys(scan *ScanResult, query string) map[string]bool {
+	qualifier, name := parseTypeQuery(query)
+	matching := make(map[string]bool)
+	for typeKey := range scan.TypeMethods {
+		pkg, receiver := splitTypeKey(typeKey)
+		if strings.TrimPrefix(receiver, "*") != name {
+			continue
+		}
+		packageName := filepath.Base(pkg)
+		if info, ok := scan.Types[pkg+":"+name]; ok {
+			packageName = info.PackageName
+		}
+		if packageMatches(pkg, packageName, qualifier) {
+			matching[typeKey] = true
+		}
+	}
+	return matching
+}
+
+// 查找类型实现的所有接口；类型名可用包名或导入路径限定以区分不同包中的同名类型
+func findTypeInterfaces(directory, query string) []TypeInterface {
+	results := []TypeInterface{}
+
+	scan := scanDirectory(directory)
+	matching := matchingTypeKeys(scan, query)
+
+	for _, match := range matchImplementations(scan) {
+		if !matching[match.TypeKey] {
+			continue
+		}
+		pkg, receiver := splitTypeKey(match.TypeKey)
+		results = append(results, TypeInterface{
+			InterfaceName:       match.Interface.Name,
+			PackageName:         match.Interface.PackageName,
+			ReceiverType:        receiver,
+			TypePackage:         pkg,
+			Location:            match.Interface.Location,
+			Kind:                typeKindOf(scan.Types, pkg, receiver),
+			ExternalTestPackage: inExternalTestPackage(match.Interface.Package, pkg),
+		})
+	}
+
+	if options.Has("std") {
+		results = append(results, matchStdlibInterfaces(scan, matching)...)
+	}
+
+	return results
+}
+
+// 匹配标准库中的导出接口（值类型已经实现时不再报告指针类型）
+func matchStdlibInterfaces(scan *ScanResult, matching map[string]bool) []TypeInterface {
+	var results []TypeInterface
+	interfaces := loadStdlibIndex().interfaces()
+	sort.Slice(interfaces, func(i, j int) bool {
+		if interfaces[i].Package != interfaces[j].Package {
+			return interfaces[i].Package < interfaces[j].Package
+		}
+		return interfaces[i].Name < interfaces[j].Name
+	})
+
+	typeKeys := make([]string, 0, len(matching))
+	for typeKey := range matching {
+		typeKeys = append(typeKeys, typeKey)
+	}
+	sort.Strings(typeKeys)
+
+	for i := range interfaces {
+		iface := &interfaces[i]
+		for _, typeKey := range typeKeys {
+			if strings.Contains(typeKey, ":*") && isExactMatch(scan.TypeMethods[strings.Replace(typeKey, ":*", ":", 1)], iface) {
+				continue
+			}
+			if !isExactMatch(scan.TypeMethods[typeKey], iface) {
+				continue
+			}
+			pkg, receiver := splitTypeKey(typeKey)
+			results = append(results, TypeInterface{
+				InterfaceName: iface.Name,
+				PackageName:   iface.PackageName,
+				ReceiverType:  receiver,
+				TypePackage:   pkg,
+				Kind:          typeKindOf(scan.Types, pkg, receiver),
+				Stdlib:        true,
+				ImportPath:    iface.Package,
+			})
+		}
+	}
+	return results
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"go/token"
+	"path/filepath"
+	"strings"
+)
+
+// 实现类型的定义形式：struct、func（函数类型适配器，如 type stringerFunc func() string）、
+// alias、named（以其他具名类型定义）或 other；找不到类型定义时为空
+func typeKindOf(types map[string]*TypeInfo, pkg, receiverType string) string {
+	if info, ok := types[pkg+":"+strings.TrimPrefix(receiverType, "*")]; ok {
+		return info.Kind
+	}
+	return ""
+}
+
+// 收集与文件同目录的所有文件中的类型定义：方法和类型定义可能不在同一个文件中。
+// 只有文件本身是测试文件时才读取同目录的测试文件
+func packageTypeSpecs(filePath string) map[string]*TypeInfo {
+	types := make(map[string]*TypeInfo)
+	paths, _ := filepath.Glob(filepath.Join(filepath.Dir(filePath), "*.go"))
+	fset := token.NewFileSet()
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") && !strings.HasSuffix(filePath, "_test.go") {
+			continue
+		}
+		if f, err := parseGoFile(fset, path); err == nil {
+			collectTypeSpecs(f, fset, path, types)
+		}
+	}
+	return types
+}
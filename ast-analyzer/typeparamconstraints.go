@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+)
+
+// 以具名接口为约束的类型参数：泛型代码中，类型实参必须实现该接口
+type ConstrainedTypeParam struct {
+	TypeParam string `json:"typeParam"`
+	// 源码中的约束，例如 fmt.Stringer、Number[T]
+	Constraint string `json:"constraint"`
+	// 约束接口的名称，不含包名和类型实参
+	InterfaceName string `json:"interfaceName"`
+	// 声明类型参数的函数或类型
+	Declaration     string `json:"declaration"`
+	DeclarationKind string `json:"declarationKind"` // func 或 type
+	// 类型参数名的位置
+	Location Location `json:"location"`
+	// 约束接口在工作区中的定义位置，标准库或找不到定义时省略
+	InterfaceLocation *Location `json:"interfaceLocation,omitempty"`
+}
+
+type ConstrainedTypeParamResult struct {
+	TypeParams []ConstrainedTypeParam `json:"typeParams"`
+}
+
+// 查找约束为具名接口（含泛型接口的实例化）的类型参数；any、comparable 和
+// interface{ ... }、~int | string 这类字面约束不算
+func findConstrainedTypeParams(directory string) []ConstrainedTypeParam {
+	results := []ConstrainedTypeParam{}
+	resolver := newInterfaceResolver()
+	scan := scanDirectory(directory)
+	for i := range scan.Interfaces {
+		resolver.add(&scan.Interfaces[i])
+	}
+
+	err := walkGoFiles(directory, func(path string, f *ast.File, fset *token.FileSet) {
+		qualifier := newTypeQualifier(f)
+		from := &InterfaceInfo{Package: packageDir(path, f)}
+
+		report := func(declaration, kind string, params *ast.FieldList) {
+			if params == nil {
+				return
+			}
+			for _, field := range params.List {
+				constraint := field.Type
+				switch t := constraint.(type) {
+				case *ast.IndexExpr:
+					constraint = t.X
+				case *ast.IndexListExpr:
+					constraint = t.X
+				}
+				embed, ok := embedRefOf(constraint, qualifier)
+				if !ok || (embed.ImportPath == "" && (embed.Name == "any" || embed.Name == "comparable")) {
+					continue
+				}
+
+				var ifaceLocation *Location
+				if embed.ImportPath == "" || !isStdlibPath(embed.ImportPath) {
+					if iface := resolver.lookup(from, embed); iface != nil && iface.Location.File != "" {
+						location := iface.Location
+						ifaceLocation = &location
+					}
+				}
+				for _, name := range field.Names {
+					pos := fset.Position(name.Pos())
+					results = append(results, ConstrainedTypeParam{
+						TypeParam:         name.Name,
+						Constraint:        types.ExprString(field.Type),
+						InterfaceName:     embed.Name,
+						Declaration:       declaration,
+						DeclarationKind:   kind,
+						Location:          Location{File: path, Line: pos.Line - 1, Column: pos.Column - 1},
+						InterfaceLocation: ifaceLocation,
+					})
+				}
+			}
+		}
+
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.FuncDecl:
+				report(node.Name.Name, "func", node.Type.TypeParams)
+			case *ast.TypeSpec:
+				report(node.Name.Name, "type", node.TypeParams)
+			}
+			return true
+		})
+	})
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "查找类型参数约束时出错: %v\n", err)
+	}
+
+	return results
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// 接口被使用的位置：以接口类型声明的变量、给这类变量的赋值、以接口类型返回的值、接口类型的结构体字段
+type UsageLocation struct {
+	// var、assign、return 或 field
+	Kind string `json:"kind"`
+	// 变量名、函数名或 Struct.Field
+	Name     string   `json:"name"`
+	Location Location `json:"location"`
+}
+
+// 单个文件中查找接口使用位置的状态
+type usageFinder struct {
+	iface     *InterfaceInfo
+	path      string
+	fset      *token.FileSet
+	qualifier *typeQualifier
+	samePkg   bool
+	usages    []UsageLocation
+}
+
+// 类型表达式是否指向该接口：同包的 Name、其他包的 pkg.Name，泛型接口的实例化也算在内
+func (u *usageFinder) refersTo(expr ast.Expr) bool {
+	switch t := expr.(type) {
+	case *ast.IndexExpr:
+		expr = t.X
+	case *ast.IndexListExpr:
+		expr = t.X
+	}
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return u.samePkg && t.Name == u.iface.Name
+	case *ast.SelectorExpr:
+		alias, ok := t.X.(*ast.Ident)
+		if !ok || t.Sel.Name != u.iface.Name {
+			return false
+		}
+		path, ok := u.qualifier.importPath[alias.Name]
+		return ok && importPackageName(path) == u.iface.PackageName && packageMatches(u.iface.Package, u.iface.PackageName, path)
+	}
+	return false
+}
+
+func (u *usageFinder) add(kind, name string, node ast.Node) {
+	pos := u.fset.Position(node.Pos())
+	u.usages = append(u.usages, UsageLocation{
+		Kind:     kind,
+		Name:     name,
+		Location: Location{File: u.path, Line: pos.Line - 1, Column: pos.Column - 1},
+	})
+}
+
+// 记录以接口类型声明的变量，返回声明的变量名
+func (u *usageFinder) valueSpec(spec *ast.ValueSpec) []string {
+	if spec.Type == nil || !u.refersTo(spec.Type) {
+		return nil
+	}
+	var names []string
+	for _, name := range spec.Names {
+		if name.Name == "_" {
+			continue
+		}
+		u.add("var", name.Name, name)
+		names = append(names, name.Name)
+	}
+	return names
+}
+
+// 结果列表中类型为该接口的位置（按展开后的结果下标）
+func (u *usageFinder) resultIndexes(ft *ast.FuncType) ([]int, int) {
+	results := expandFields(ft.Results)
+	var indexes []int
+	for i, expr := range results {
+		if u.refersTo(expr) {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes, len(results)
+}
+
+// 遍历函数体；变量按名称跟踪，不区分嵌套作用域
+func (u *usageFinder) funcBody(name string, ft *ast.FuncType, body *ast.BlockStmt, outer map[string]bool) {
+	if body == nil {
+		return
+	}
+	vars := make(map[string]bool, len(outer))
+	for v := range outer {
+		vars[v] = true
+	}
+	if ft.Params != nil {
+		for _, field := range ft.Params.List {
+			if u.refersTo(field.Type) {
+				for _, param := range field.Names {
+					vars[param.Name] = true
+				}
+			}
+		}
+	}
+	indexes, total := u.resultIndexes(ft)
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch t := n.(type) {
+		case *ast.FuncLit:
+			u.funcBody(name, t.Type, t.Body, vars)
+			return false
+		case *ast.DeclStmt:
+			if gen, ok := t.Decl.(*ast.GenDecl); ok && gen.Tok == token.VAR {
+				for _, spec := range gen.Specs {
+					for _, v := range u.valueSpec(spec.(*ast.ValueSpec)) {
+						vars[v] = true
+					}
+				}
+			}
+		case *ast.AssignStmt:
+			if t.Tok != token.ASSIGN {
+				return true
+			}
+			for i, lhs := range t.Lhs {
+				ident, ok := lhs.(*ast.Ident)
+				if !ok || !vars[ident.Name] {
+					continue
+				}
+				// 多值赋值 a, b = f() 时定位到右侧的调用
+				if len(t.Rhs) == len(t.Lhs) {
+					u.add("assign", ident.Name, t.Rhs[i])
+				} else {
+					u.add("assign", ident.Name, t.Rhs[0])
+				}
+			}
+		case *ast.ReturnStmt:
+			if len(indexes) == 0 {
+				return true
+			}
+			// 裸返回或 return f() 这种多值转发，定位到 return 语句本身
+			if len(t.Results) != total {
+				u.add("return", name, t)
+				return true
+			}
+			for _, i := range indexes {
+				u.add("return", name, t.Results[i])
+			}
+		}
+		return true
+	})
+}
+
+// 在扫描过的文件中查找接口被赋值、返回以及作为结构体字段类型使用的位置。
+// 所有接口在同一次遍历中查找，结果与 ifaces 一一对应
+func findUsageLocations(files []scannedFile, ifaces []*InterfaceInfo) [][]UsageLocation {
+	usages := make([][]UsageLocation, len(ifaces))
+	for i := range usages {
+		usages[i] = []UsageLocation{}
+	}
+
+	for _, file := range files {
+		for i, iface := range ifaces {
+			usages[i] = append(usages[i], fileUsageLocations(file, iface)...)
+		}
+	}
+	return usages
+}
+
+// 单个文件中接口的使用位置
+func fileUsageLocations(file scannedFile, iface *InterfaceInfo) []UsageLocation {
+	f, path, fset := file.file, file.path, file.fset
+	u := &usageFinder{
+		iface:     iface,
+		path:      path,
+		fset:      fset,
+		qualifier: newTypeQualifier(f),
+		samePkg:   packageDir(path, f) == iface.Package && f.Name.Name == iface.PackageName,
+	}
+
+	// 包级变量对文件中的所有函数可见
+	pkgVars := make(map[string]bool)
+	for _, decl := range f.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.VAR {
+			for _, spec := range gen.Specs {
+				for _, v := range u.valueSpec(spec.(*ast.ValueSpec)) {
+					pkgVars[v] = true
+				}
+			}
+		}
+	}
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch t := n.(type) {
+		case *ast.TypeSpec:
+			structType, ok := t.Type.(*ast.StructType)
+			if !ok {
+				return true
+			}
+			for _, field := range structType.Fields.List {
+				if !u.refersTo(field.Type) {
+					continue
+				}
+				if len(field.Names) == 0 {
+					u.add("field", t.Name.Name+"."+u.iface.Name, field)
+				}
+				for _, name := range field.Names {
+					u.add("field", t.Name.Name+"."+name.Name, name)
+				}
+			}
+		case *ast.FuncDecl:
+			u.funcBody(t.Name.Name, t.Type, t.Body, pkgVars)
+			return false
+		}
+		return true
+	})
+
+	return u.usages
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// 默认最多进入的目录数量，防止 node_modules 之类的巨大目录树拖慢扫描
+const defaultMaxDirs = 20000
+
+// 目录遍历的限制：--max-depth（相对于扫描根目录，0 表示不限）和 --max-dirs
+type walkLimits struct {
+	root      string
+	maxDepth  int
+	maxDirs   int
+	dirs      int
+	truncated bool
+}
+
+func newWalkLimits(root string) *walkLimits {
+	return &walkLimits{
+		root:     root,
+		maxDepth: options.Int("max-depth", 0),
+		maxDirs:  options.Int("max-dirs", defaultMaxDirs),
+	}
+}
+
+// 是否进入目录；超过 --max-dirs 后不再进入任何新目录，已进入目录中的文件照常处理
+func (l *walkLimits) enter(dir string) bool {
+	if l.truncated {
+		return false
+	}
+	if l.maxDepth > 0 && dir != l.root {
+		if rel, err := filepath.Rel(l.root, dir); err == nil && strings.Count(filepath.ToSlash(rel), "/")+1 > l.maxDepth {
+			return false
+		}
+	}
+	if l.maxDirs > 0 && l.dirs >= l.maxDirs {
+		l.truncated = true
+		return false
+	}
+	l.dirs++
+	return true
+}
+
+// 遍历因 --max-dirs 被截断时报告 scanTruncated 诊断
+func (l *walkLimits) report() {
+	if !l.truncated {
+		return
+	}
+	reportDiagnostic(Diagnostic{
+		Kind: "scanTruncated",
+		Message: fmt.Sprintf("stopped descending into %s after visiting %d directories (--max-dirs); exclude large directories or raise the limit",
+			l.root, l.dirs),
+		Location: Location{File: l.root},
+	})
+}
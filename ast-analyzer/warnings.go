@@ -0,0 +1,41 @@
+package main
+
+import "fmt"
+
+// 代码异味提示
+type InterfaceWarning struct {
+	Kind          string   `json:"kind"`
+	InterfaceName string   `json:"interfaceName"`
+	PackageName   string   `json:"packageName"`
+	Location      Location `json:"location"`
+	MethodCount   int      `json:"methodCount"`
+	Message       string   `json:"message"`
+}
+
+// 查找方法数（含嵌入接口展开后的方法）超过阈值的接口
+func findLargeInterfaces(directory string, maxMethods int) []InterfaceWarning {
+	warnings := []InterfaceWarning{}
+	for _, iface := range scanDirectory(directory).Interfaces {
+		if len(iface.Methods) <= maxMethods {
+			continue
+		}
+		warnings = append(warnings, InterfaceWarning{
+			Kind:          "large-interface",
+			InterfaceName: iface.Name,
+			PackageName:   iface.PackageName,
+			Location:      iface.Location,
+			MethodCount:   len(iface.Methods),
+			Message:       fmt.Sprintf("interface %s has %d methods (limit %d)", iface.Name, len(iface.Methods), maxMethods),
+		})
+	}
+	return warnings
+}
+
+// --warn-large-interface N：在目录命令的结果中追加 warnings 字段
+func appendWarnings(output []byte) []byte {
+	if !options.Has("warn-large-interface") || scanStats.directory == "" {
+		return output
+	}
+	maxMethods := options.Int("warn-large-interface", 10)
+	return appendJSONField(output, "warnings", findLargeInterfaces(scanStats.directory, maxMethods))
+}
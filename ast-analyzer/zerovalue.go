@@ -0,0 +1,74 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// --with-stubs：按方法的返回类型生成默认实现，例如 return "", nil；没有返回值的方法为空。
+// 类型按接口文件中的写法渲染，在其他包中使用时需要按 imports 限定
+func defaultBody(spec MethodSpec, types map[string]*TypeInfo) string {
+	if spec.Func == nil {
+		return `panic("not implemented")`
+	}
+	if spec.Func.Results == nil || len(spec.Func.Results.List) == 0 {
+		return ""
+	}
+	var values []string
+	for _, field := range spec.Func.Results.List {
+		value := zeroValue(field.Type, spec, types)
+		for i := 0; i < max(len(field.Names), 1); i++ {
+			values = append(values, value)
+		}
+	}
+	return "return " + strings.Join(values, ", ")
+}
+
+// 类型的零值表达式；无法确定底层类型的具名类型（其他包的类型、类型参数等）使用 *new(T)，
+// 内置注册表中的标准库接口（例如 io.ReadCloser）为 nil
+func zeroValue(expr ast.Expr, spec MethodSpec, types map[string]*TypeInfo) string {
+	rendered := singleLine(renderNode(token.NewFileSet(), expr))
+	switch t := expr.(type) {
+	case *ast.ParenExpr:
+		return zeroValue(t.X, spec, types)
+	case *ast.StarExpr, *ast.MapType, *ast.ChanType, *ast.FuncType, *ast.InterfaceType:
+		return "nil"
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return "nil"
+		}
+		return rendered + "{}"
+	case *ast.StructType:
+		return rendered + "{}"
+	case *ast.SelectorExpr:
+		if x, ok := t.X.(*ast.Ident); ok {
+			for _, imp := range spec.Imports {
+				if _, known := stdlibRegistry()[imp.Path][t.Sel.Name]; known && imp.Alias == x.Name {
+					return "nil"
+				}
+			}
+		}
+	case *ast.Ident:
+		switch t.Name {
+		case "bool":
+			return "false"
+		case "string":
+			return `""`
+		case "error", "any":
+			return "nil"
+		}
+		if predeclaredTypes[t.Name] {
+			return "0"
+		}
+		if info, ok := types[spec.pkgDir+":"+t.Name]; ok && len(info.TypeParams) == 0 {
+			switch info.Kind {
+			case "struct":
+				return t.Name + "{}"
+			case "interface", "func":
+				return "nil"
+			}
+		}
+	}
+	return "*new(" + rendered + ")"
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+const zeroValueFixture = `package p
+
+import (
+	"context"
+	"io"
+
+	m "ex/model"
+)
+
+type Point struct{ X, Y int }
+
+type Handler func()
+
+type Named interface{ Name() string }
+
+type Box[T any] struct{ v T }
+
+type ID int
+
+type Store interface {
+	Locate(ctx context.Context) (Point, bool, error)
+	Close()
+	Counts() (a, b int, ok bool)
+	Open() (io.ReadCloser, *Point, []byte, map[string]int, Handler, Named)
+	Raw() ([2]int, struct{}, rune, ID, m.Item, chan int)
+	Boxed() Box[int]
+}
+`
+
+func TestWithStubsDefaultBodies(t *testing.T) {
+	dir := writeTree(t, map[string]string{"p.go": zeroValueFixture})
+	bodies := func(args ...string) map[string]string {
+		descriptions := runArgs(t, append([]string{"describe-interface", dir, "Store"}, args...)...).(DescribeResult).Interfaces
+		if len(descriptions) != 1 {
+			t.Fatalf("got %d descriptions, want 1", len(descriptions))
+		}
+		got := make(map[string]string)
+		for _, method := range descriptions[0].Methods {
+			got[method.Name] = method.DefaultBody
+		}
+		return got
+	}
+
+	want := map[string]string{
+		"Locate": "return Point{}, false, nil",
+		"Close":  "",
+		"Counts": "return 0, 0, false",
+		"Open":   "return nil, nil, nil, nil, nil, nil",
+		"Raw":    "return [2]int{}, struct{}{}, 0, *new(ID), *new(m.Item), nil",
+		"Boxed":  "return *new(Box[int])",
+	}
+	if got := bodies("--with-stubs"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	for name, body := range bodies() {
+		if body != "" {
+			t.Fatalf("%s has defaultBody %q without --with-stubs", name, body)
+		}
+	}
+}
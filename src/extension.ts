@@ -22,17 +22,76 @@ interface InterfaceMethod {
   interfaceName: string;
   location: Location;
   endLocation: Location;
+  anchor?: Location;
+  nameLocation?: Location;
+  nameEndLocation?: Location;
+  ordinal?: number;
+  interfaceOrdinal?: number;
 }
 
 interface Implementation {
   methodName: string;
   receiverType: string;
   location: Location;
+  endLocation?: Location;
+  nameLocation?: Location;
+  nameEndLocation?: Location;
+  interfaces?: string[];
+  receiverName?: string;
+  receiverLocation?: Location;
+  receiverEndLocation?: Location;
+  // 分析器生成的标签，例如 *SimpleTokenManager.AddToken(token string) error
+  label?: string;
+  // 实现类型所在包与接口所在包的导入关系
+  importRelation?: 'same-package' | 'imports-interface-package' | 'shared-importer' | 'unrelated';
+  // 实现来自主模块以外的模块时的模块路径和版本；readOnly 表示位于模块缓存中
+  module?: { path: string; version?: string; readOnly?: boolean };
+}
+
+// 外部模块中实现的提示，例如 外部依赖 foo@v1.2.3
+function describeModule(impl: Implementation): string | undefined {
+  if (!impl.module) {
+    return undefined;
+  }
+  const name = impl.module.version ? `${impl.module.path}@${impl.module.version}` : impl.module.path;
+  return impl.module.readOnly ? `外部依赖 ${name}（只读）` : `模块 ${name}`;
+}
+
+// 与接口所在包没有导入关系的实现排在最后；开启 hideUnrelatedImplementations 时不显示
+function orderByImportRelation(implementations: Implementation[]): Implementation[] {
+  const hideUnrelated = vscode.workspace.getConfiguration('goInterfaceNavigator').get<boolean>('hideUnrelatedImplementations', false);
+  const related = implementations.filter(impl => impl.importRelation !== 'unrelated');
+  const unrelated = implementations.filter(impl => impl.importRelation === 'unrelated');
+  return hideUnrelated && related.length > 0 ? related : [...related, ...unrelated];
+}
+
+// 接口方法 CodeLens 的锚点：method-line、above-method 或 interface-line，对应分析器的 --lens-anchor
+function lensAnchor(): string {
+  const anchor = vscode.workspace.getConfiguration('goInterfaceNavigator').get<string>('codeLens.anchor', 'method-line');
+  return ['method-line', 'above-method', 'interface-line'].includes(anchor) ? anchor : 'method-line';
+}
+
+// 按源码形式渲染接收者，例如 (s *SimpleTokenManager)；匿名和 _ 接收者只渲染类型
+function formatReceiver(impl: Implementation): string {
+  return impl.receiverName ? `(${impl.receiverName} ${impl.receiverType})` : `(${impl.receiverType})`;
 }
 
 interface AnalysisResult {
   interfaces?: InterfaceMethod[];
   implementations?: Implementation[];
+  // 目录下没有结果、分析器改为从模块根目录查找时为 "module"
+  scope?: string;
+  diagnostics?: { kind: string; message: string }[];
+}
+
+// 扫描因目录过多被截断时提示一次，提醒用户配置排除目录
+let scanTruncationShown = false;
+function reportScanTruncation(result: AnalysisResult) {
+  const truncated = result.diagnostics?.find(d => d.kind === 'scanTruncated');
+  if (truncated && !scanTruncationShown) {
+    scanTruncationShown = true;
+    vscode.window.showWarningMessage(`Go 接口分析的扫描被截断: ${truncated.message}`);
+  }
 }
 
 // 获取AST分析器路径 - 修复路径问题
@@ -61,6 +120,11 @@ class GoInterfaceCodeLensProvider implements vscode.CodeLensProvider {
     try {
       // 分析当前文件中的接口和实现
       const interfaces = await this.analyzeFileInterfaces(document.fileName);
+      // 同一行的 CodeLens 按接口序号和方法序号排列，刷新后顺序不变
+      interfaces.sort((a, b) =>
+        (a.anchor ?? a.location).line - (b.anchor ?? b.location).line ||
+        (a.interfaceOrdinal ?? 0) - (b.interfaceOrdinal ?? 0) ||
+        (a.ordinal ?? 0) - (b.ordinal ?? 0));
       const implementations = await this.analyzeFileImplementations(document.fileName);
       this.addInterfaceDecorations(document);
       this.addImplementationDecorations(document);
@@ -70,12 +134,9 @@ class GoInterfaceCodeLensProvider implements vscode.CodeLensProvider {
       // const interfaceMethodNames = new Set(interfaces.map(iface => iface.name));
       // console.log('Interface method names:', Array.from(interfaceMethodNames));
        for (const interfaceMethod of interfaces) {
-          const range = new vscode.Range(
-            interfaceMethod.location.line,
-            interfaceMethod.location.column,
-            interfaceMethod.location.line,
-            interfaceMethod.location.column + interfaceMethod.name.length
-          );
+          // CodeLens 放在分析器按 codeLens.anchor 给出的锚点上，旧版分析器没有锚点时退回方法位置
+          const anchor = interfaceMethod.anchor ?? interfaceMethod.location;
+          const range = new vscode.Range(anchor.line, anchor.column, anchor.line, anchor.column);
       
       codeLenses.push(new vscode.CodeLens(range, {
         title: "🔍 implementations",
@@ -85,10 +146,12 @@ class GoInterfaceCodeLensProvider implements vscode.CodeLensProvider {
     }
        // 分析方法实现（只显示完整且精确实现接口的方法）
       for (const impl of implementations) {
-        if (packageAnalysis.methodToInterface[impl.methodName]) {
+        // 一个方法可能同时实现多个接口，逐个列出
+        const satisfied = impl.interfaces ?? packageAnalysis.methodToInterfaces?.[impl.methodName] ?? [];
+        if (satisfied.length > 0 || packageAnalysis.methodToInterface[impl.methodName]) {
             const range = new vscode.Range(impl.location.line, impl.location.column, impl.location.line, impl.location.column);
             const codeLens = new vscode.CodeLens(range, {
-                title: '✅ interface implementation',
+                title: satisfied.length > 1 ? `✅ implements ${satisfied.length} interfaces: ${satisfied.join(', ')}` : '✅ interface implementation',
                 command: 'goInterfaceNavigator.findInterface',
                 arguments: [impl.methodName]
             });
@@ -143,7 +206,7 @@ class GoInterfaceCodeLensProvider implements vscode.CodeLensProvider {
     const decorations: vscode.DecorationOptions[] = [];
     
     for (const interfaceMethod of interfaces) {
-      const line = interfaceMethod.location.line; // 移除 -1
+      const line = (interfaceMethod.anchor ?? interfaceMethod.location).line;
       const range = new vscode.Range(line, 0, line, 0);
       
       decorations.push({
@@ -183,7 +246,7 @@ class GoInterfaceCodeLensProvider implements vscode.CodeLensProvider {
   private async analyzeFileInterfaces(filePath: string): Promise<InterfaceMethod[]> {
     return new Promise((resolve) => {
       const astAnalyzerPath = getAstAnalyzerPath();
-      const command = `"${astAnalyzerPath}" find-file-interfaces "${filePath}"`;
+      const command = `"${astAnalyzerPath}" find-file-interfaces "${filePath}" --lens-anchor ${lensAnchor()}`;
       
       // console.log('执行接口分析命令:', command);
       
@@ -258,7 +321,7 @@ class GoInterfaceCodeLensProvider implements vscode.CodeLensProvider {
       
         decorations.push({
           range,
-          hoverMessage: `🔧 接口实现: ${impl.receiverType}.${impl.methodName}`
+          hoverMessage: `🔧 接口实现: ${impl.label ?? `${formatReceiver(impl)} ${impl.methodName}`}`
         });
       }
     }
@@ -390,17 +453,24 @@ export function activate(context: vscode.ExtensionContext) {
           return;
         }
 
+        // 有方法名范围时选中方法名，否则退回到声明起始位置
         const locations = implementations.map(impl => new vscode.Location(
           vscode.Uri.file(impl.location.file),
-          new vscode.Position(impl.location.line - 1, impl.location.column)
+          impl.nameLocation && impl.nameEndLocation
+            ? new vscode.Range(impl.nameLocation.line - 1, impl.nameLocation.column - 1, impl.nameEndLocation.line - 1, impl.nameEndLocation.column - 1)
+            : new vscode.Position(impl.location.line - 1, impl.location.column)
         ));
 
         if (locations.length === 1) {
-          // 直接跳转
+          // 直接跳转；实现位于其他模块时提示模块和版本
           const location = locations[0];
+          const moduleNote = describeModule(implementations[0]);
+          if (moduleNote) {
+            vscode.window.setStatusBarMessage(`"${methodName}" 的实现位于${moduleNote}`, 5000);
+          }
           const doc = await vscode.workspace.openTextDocument(location.uri);
           const newEditor = await vscode.window.showTextDocument(doc);
-          newEditor.selection = new vscode.Selection(location.range.start, location.range.start);
+          newEditor.selection = new vscode.Selection(location.range.start, location.range.end);
           newEditor.revealRange(location.range, vscode.TextEditorRevealType.InCenter);
         } else {
           // 显示引用面板
@@ -469,7 +539,9 @@ export function activate(context: vscode.ExtensionContext) {
 
         const locations = interfaces.map(iface => new vscode.Location(
           vscode.Uri.file(iface.location.file),
-          new vscode.Position(iface.location.line - 1, iface.location.column)
+          iface.nameLocation && iface.nameEndLocation
+            ? new vscode.Range(iface.nameLocation.line, iface.nameLocation.column, iface.nameEndLocation.line, iface.nameEndLocation.column)
+            : new vscode.Position(iface.location.line - 1, iface.location.column)
         ));
 
         if (locations.length === 1) {
@@ -477,7 +549,7 @@ export function activate(context: vscode.ExtensionContext) {
           const location = locations[0];
           const doc = await vscode.workspace.openTextDocument(location.uri);
           const newEditor = await vscode.window.showTextDocument(doc);
-          newEditor.selection = new vscode.Selection(location.range.start, location.range.start);
+          newEditor.selection = new vscode.Selection(location.range.start, location.range.end);
           newEditor.revealRange(location.range, vscode.TextEditorRevealType.InCenter);
         } else {
           // 显示引用面板
@@ -517,7 +589,11 @@ async function findImplementationsWithAST(directory: string, methodName: string)
       
       try {
         const result: AnalysisResult = JSON.parse(stdout);
-        resolve(result.implementations || []);
+        reportScanTruncation(result);
+        if (result.scope === 'module') {
+          vscode.window.setStatusBarMessage(`目录中未找到 "${methodName}" 的实现，已扩大到模块根目录查找`, 5000);
+        }
+        resolve(orderByImportRelation(result.implementations || []));
       } catch (parseError) {
         console.error('解析AST输出失败:', parseError);
         console.error('stdout:', stdout);
@@ -544,6 +620,7 @@ async function findInterfacesWithAST(directory: string, methodName: string): Pro
       
       try {
         const result: AnalysisResult = JSON.parse(stdout);
+        reportScanTruncation(result);
         resolve(result.interfaces || []);
       } catch (parseError) {
         console.error('解析AST输出失败:', parseError);